@@ -25,7 +25,8 @@ type LabelSelectorRequirement struct {
 	// +patchStrategy=merge
 	Key string `json:"key" patchStrategy:"merge" patchMergeKey:"key" protobuf:"bytes,1,opt,name=key"`
 	// operator represents a key's relationship to a set of values.
-	// Valid operators are In, NotIn, Exists and DoesNotExist.
+	// Valid operators are In, NotIn, Exists, DoesNotExist, Match and NotMatch.
+	// Match and NotMatch use the regexValue as a regular expression to match the label value.
 	Operator LabelSelectorOperator `json:"operator" protobuf:"bytes,2,opt,name=operator,casttype=LabelSelectorOperator"`
 	// values is an array of string values. If the operator is In or NotIn,
 	// the values array must be non-empty. If the operator is Exists or DoesNotExist,
@@ -40,7 +41,8 @@ type LabelSelectorRequirement struct {
 type LabelSelectorOperator string
 
 const (
-	LabelSelectorOpMatch LabelSelectorOperator = "Match"
+	LabelSelectorOpMatch    LabelSelectorOperator = "Match"
+	LabelSelectorOpNotMatch LabelSelectorOperator = "NotMatch"
 )
 
 func (ls *LabelSelector) Matches(label map[string]string) (bool, error) {
@@ -48,30 +50,82 @@ func (ls *LabelSelector) Matches(label map[string]string) (bool, error) {
 		return false, nil
 	}
 
+	m, err := ls.Compile()
+	if err != nil {
+		return false, err
+	}
+
+	return m.Matches(label), nil
+}
+
+// Matcher is a compiled LabelSelector, the regular expressions and the selector are built only once.
+// It is safe for concurrent use.
+// +kubebuilder:object:generate=false
+type Matcher struct {
+	selector labels.Selector
+	regexes  []regexRequirement
+}
+
+type regexRequirement struct {
+	key    string
+	regex  *regexp.Regexp
+	negate bool
+}
+
+// Compile compiles the label selector into a Matcher.
+func (ls *LabelSelector) Compile() (*Matcher, error) {
+
+	if ls == nil {
+		return nil, fmt.Errorf("label selector is nil")
+	}
+
 	selector := &metav1.LabelSelector{
 		MatchLabels: ls.MatchLabels,
 	}
+	m := &Matcher{}
 	for _, requirement := range ls.MatchExpressions {
-		if requirement.Operator != LabelSelectorOpMatch {
+		switch requirement.Operator {
+		case LabelSelectorOpMatch, LabelSelectorOpNotMatch:
+			regex, err := regexp.Compile(requirement.RegexValue)
+			if err != nil {
+				return nil, err
+			}
+			m.regexes = append(m.regexes, regexRequirement{
+				key:    requirement.Key,
+				regex:  regex,
+				negate: requirement.Operator == LabelSelectorOpNotMatch,
+			})
+		default:
 			selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
 				Key:      requirement.Key,
 				Operator: metav1.LabelSelectorOperator(requirement.Operator),
 				Values:   requirement.Values,
 			})
-		} else {
-			match, err := regexp.MatchString(requirement.RegexValue, label[requirement.Key])
-			if !match {
-				return false, err
-			}
 		}
 	}
 
 	sl, err := metav1.LabelSelectorAsSelector(selector)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	m.selector = sl
+
+	return m, nil
+}
+
+// Matches returns true if the labels match the compiled label selector, nil labels match nothing.
+func (m *Matcher) Matches(label map[string]string) bool {
+	if label == nil {
+		return false
+	}
+
+	for _, r := range m.regexes {
+		if r.regex.MatchString(label[r.key]) == r.negate {
+			return false
+		}
 	}
 
-	return sl.Matches(labels.Set(label)), nil
+	return m.selector.Matches(labels.Set(label))
 }
 
 func (ls *LabelSelector) Validate() error {
@@ -80,7 +134,7 @@ func (ls *LabelSelector) Validate() error {
 	}
 
 	for _, requirement := range ls.MatchExpressions {
-		if requirement.Operator != LabelSelectorOpMatch {
+		if requirement.Operator != LabelSelectorOpMatch && requirement.Operator != LabelSelectorOpNotMatch {
 			selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
 				Key:      requirement.Key,
 				Operator: metav1.LabelSelectorOperator(requirement.Operator),
@@ -203,12 +203,16 @@ type ConfigSpec struct {
 
 // ConfigStatus defines the observed state of Config
 type ConfigStatus struct {
+	// The receivers bound to the config, in the format of `type/name`.
+	Receivers []string `json:"receivers,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Cluster,shortName=nc,categories=notification-manager
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Receivers",type=string,JSONPath=`.status.receivers`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Config is the Schema for the dingtalkconfigs API
 type Config struct {
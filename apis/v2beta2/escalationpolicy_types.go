@@ -0,0 +1,75 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EscalationStep defines which receivers the alert will be sent to after it has been firing for a while.
+type EscalationStep struct {
+	// How long to wait after the alert was first sent before executing this step.
+	// Zero means executing this step immediately.
+	Delay metav1.Duration `json:"delay,omitempty"`
+	// Receivers which need to receive the alert in this step.
+	Receivers ReceiverSelector `json:"receivers"`
+}
+
+// EscalationPolicySpec defines the desired state of EscalationPolicy
+type EscalationPolicySpec struct {
+	// whether the escalation policy is enabled
+	Enabled *bool `json:"enabled,omitempty"`
+	// The steps executed in order until the alert is resolved or acknowledged.
+	//
+	// +kubebuilder:validation:MinItems=1
+	Steps []EscalationStep `json:"steps"`
+}
+
+// EscalationPolicyStatus defines the observed state of EscalationPolicy
+type EscalationPolicyStatus struct {
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories=notification-manager
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+
+// EscalationPolicy is the Schema for the EscalationPolicy API
+type EscalationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EscalationPolicySpec   `json:"spec,omitempty"`
+	Status EscalationPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// EscalationPolicyList contains a list of EscalationPolicy
+type EscalationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EscalationPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EscalationPolicy{}, &EscalationPolicyList{})
+}
+
+func (e *EscalationPolicy) IsActive() bool {
+	return e.Spec.Enabled == nil || *e.Spec.Enabled
+}
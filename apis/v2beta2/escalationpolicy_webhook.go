@@ -0,0 +1,77 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func (e *EscalationPolicy) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(e).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,mutating=false,failurePolicy=fail,groups=notification.kubesphere.io,resources=escalationpolicies,versions=v2beta2
+var _ webhook.Validator = &EscalationPolicy{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (e *EscalationPolicy) ValidateCreate() (warnings admission.Warnings, err error) {
+
+	return e.validateEscalationPolicy()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (e *EscalationPolicy) ValidateUpdate(_ runtime.Object) (warnings admission.Warnings, err error) {
+	return e.validateEscalationPolicy()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (e *EscalationPolicy) ValidateDelete() (warnings admission.Warnings, err error) {
+	return admission.Warnings{}, nil
+}
+
+func (e *EscalationPolicy) validateEscalationPolicy() (warnings admission.Warnings, err error) {
+	var allErrs field.ErrorList
+
+	if len(e.Spec.Steps) == 0 {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "steps"), "must have at least one step"))
+	}
+
+	for index, step := range e.Spec.Steps {
+		path := field.NewPath("spec", "steps").Index(index)
+		if step.Delay.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("delay"), step.Delay, "must not be negative"))
+		}
+
+		allErrs = append(allErrs, validateReceiverSelector(path.Child("receivers"), step.Receivers)...)
+	}
+
+	if allErrs == nil || len(allErrs) == 0 {
+		return admission.Warnings{}, nil
+	}
+
+	return admission.Warnings{}, errors.NewInvalid(
+		schema.GroupKind{Group: "notification.kubesphere.io", Kind: "EscalationPolicy"},
+		e.Name, allErrs)
+}
@@ -0,0 +1,68 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// InhibitorSpec defines the desired state of Inhibitor
+type InhibitorSpec struct {
+	// whether the inhibitor is enabled
+	Enabled *bool `json:"enabled,omitempty"`
+	// The alerts matched the source matcher will inhibit the alerts matched the target matcher.
+	Source *LabelSelector `json:"source"`
+	// The alerts matched the target matcher will be inhibited if a source alert is firing.
+	Target *LabelSelector `json:"target"`
+	// Labels which must have an equal value in the source alert and the target alert for the inhibition to take effect.
+	Equal []string `json:"equal,omitempty"`
+}
+
+// InhibitorStatus defines the observed state of Inhibitor
+type InhibitorStatus struct {
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories=notification-manager
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+
+// Inhibitor is the Schema for the Inhibitor API
+type Inhibitor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InhibitorSpec   `json:"spec,omitempty"`
+	Status InhibitorStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// InhibitorList contains a list of Inhibitor
+type InhibitorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Inhibitor `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Inhibitor{}, &InhibitorList{})
+}
+
+func (i *Inhibitor) IsActive() bool {
+	return i.Spec.Enabled == nil || *i.Spec.Enabled
+}
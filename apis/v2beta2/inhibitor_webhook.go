@@ -0,0 +1,83 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func (i *Inhibitor) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(i).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,mutating=false,failurePolicy=fail,groups=notification.kubesphere.io,resources=inhibitors,versions=v2beta2
+var _ webhook.Validator = &Inhibitor{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (i *Inhibitor) ValidateCreate() (warnings admission.Warnings, err error) {
+
+	return i.validateInhibitor()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (i *Inhibitor) ValidateUpdate(_ runtime.Object) (warnings admission.Warnings, err error) {
+	return i.validateInhibitor()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (i *Inhibitor) ValidateDelete() (warnings admission.Warnings, err error) {
+	return admission.Warnings{}, nil
+}
+
+func (i *Inhibitor) validateInhibitor() (warnings admission.Warnings, err error) {
+	var allErrs field.ErrorList
+
+	if i.Spec.Source == nil {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "source"), "must be specified"))
+	} else if err := validateSelector(i.Spec.Source); err != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "source"), i.Spec.Source, err.Error()))
+	}
+
+	if i.Spec.Target == nil {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "target"), "must be specified"))
+	} else if err := validateSelector(i.Spec.Target); err != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "target"), i.Spec.Target, err.Error()))
+	}
+
+	for index, label := range i.Spec.Equal {
+		for _, msg := range validation.IsQualifiedName(label) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "equal").Index(index), label, msg))
+		}
+	}
+
+	if allErrs == nil || len(allErrs) == 0 {
+		return admission.Warnings{}, nil
+	}
+
+	return admission.Warnings{}, errors.NewInvalid(
+		schema.GroupKind{Group: "notification.kubesphere.io", Kind: "Inhibitor"},
+		i.Name, allErrs)
+}
@@ -67,6 +67,63 @@ type HistoryReceiver struct {
 	Webhook *WebhookReceiver `json:"webhook"`
 }
 
+// RelabelConfig allows dynamic rewriting of the labels or annotations of the incoming alerts.
+// It works the same as the relabel_configs of Prometheus.
+type RelabelConfig struct {
+	// The source labels select values from the existing labels or annotations. Their content is concatenated
+	// using the configured separator and matched against the configured regular expression
+	// for the replace, keep, and drop actions.
+	SourceLabels []string `json:"sourceLabels,omitempty"`
+	// Separator placed between concatenated source label values, default is ';'.
+	Separator *string `json:"separator,omitempty"`
+	// Regular expression against which the extracted value is matched, default is '(.*)'.
+	// The regular expression is fully anchored.
+	Regex string `json:"regex,omitempty"`
+	// Label to which the resulting value is written in a replace action.
+	// It is mandatory for replace actions. Regex capture groups are available.
+	TargetLabel string `json:"targetLabel,omitempty"`
+	// Replacement value against which a regex replace is performed if the regular expression matches,
+	// default is '$1'. Regex capture groups are available.
+	Replacement *string `json:"replacement,omitempty"`
+	// Action to perform based on regex matching, default is 'replace'.
+	// replace: Match regex against the concatenated source labels, and set target label to replacement.
+	// keep: Drop the alerts for which regex does not match the concatenated source labels.
+	// drop: Drop the alerts for which regex matches the concatenated source labels.
+	// labeldrop: Match regex against all label names, any label that matches will be removed.
+	// labelkeep: Match regex against all label names, any label that does not match will be removed.
+	// labelmap: Match regex against all label names, copy the values of the matching labels
+	// to the label names given by replacement.
+	//
+	// +kubebuilder:validation:Enum=replace;keep;drop;labeldrop;labelkeep;labelmap
+	Action string `json:"action,omitempty"`
+	// Which the relabel config applies to, labels or annotations, default is 'labels'.
+	//
+	// +kubebuilder:validation:Enum=labels;annotations
+	Scope string `json:"scope,omitempty"`
+}
+
+// Enrichment defines which metadata of the Kubernetes objects will be copied to the labels of the alerts.
+// The namespace of an alert is identified by the label `namespace`, and the pod is identified by the labels `namespace` and `pod`.
+// The workload is the owner of the pod, which can be a Deployment, StatefulSet, DaemonSet, Job or CronJob.
+type Enrichment struct {
+	// The metadata of the namespace the alert belongs to.
+	Namespace *MetadataEnrichment `json:"namespace,omitempty"`
+	// The metadata of the pod the alert belongs to.
+	Pod *MetadataEnrichment `json:"pod,omitempty"`
+	// The metadata of the workload which the pod of the alert belongs to.
+	Workload *MetadataEnrichment `json:"workload,omitempty"`
+}
+
+type MetadataEnrichment struct {
+	// The labels of the object which will be copied to the labels of the alert.
+	Labels []string `json:"labels,omitempty"`
+	// The annotations of the object which will be copied to the labels of the alert.
+	Annotations []string `json:"annotations,omitempty"`
+	// The prefix added to the names of the copied labels and annotations.
+	// The label of the alert will not be overwritten if it already exists.
+	Prefix string `json:"prefix,omitempty"`
+}
+
 type Template struct {
 	// Template file.
 	Text *ConfigmapKeySelector `json:"text,omitempty"`
@@ -131,6 +188,14 @@ type NotificationManagerSpec struct {
 	History *HistoryReceiver `json:"history,omitempty"`
 	// Labels for grouping notifiations.
 	GroupLabels []string `json:"groupLabels,omitempty"`
+	// How long to wait to buffer the alerts of a group before sending the first notification of the group.
+	// The alerts of a group which arrive in this time will be sent together.
+	// Nil or zero means sending the notification immediately.
+	GroupWait *metav1.Duration `json:"groupWait,omitempty"`
+	// How long to wait before sending the next notification of a group after the last one was sent.
+	// The alerts of a group which arrive in this time will be sent together in the next notification.
+	// Nil or zero means sending the notification immediately.
+	GroupInterval *metav1.Duration `json:"groupInterval,omitempty"`
 	// The maximum size of a batch. A batch used to buffer alerts and asynchronously process them.
 	//
 	// +kubebuilder:default=100
@@ -149,6 +214,16 @@ type NotificationManagerSpec struct {
 	//
 	// +kubebuilder:default=All
 	RoutePolicy string `json:"routePolicy,omitempty"`
+	// The minimum time to wait before sending a notification again if it has already been sent successfully to a receiver.
+	// The alerts resent by Alertmanager within this interval will not be notified again,
+	// and the resolved notification will only be sent if the firing notification has been sent.
+	// Nil or zero means notifying all incoming alerts.
+	RepeatInterval *metav1.Duration `json:"repeatInterval,omitempty"`
+	// The relabel configs used to rewrite the labels and annotations of the incoming alerts before they are processed.
+	// The relabel configs are applied in order, the same as the relabel_configs of Prometheus.
+	RelabelConfigs []RelabelConfig `json:"relabelConfigs,omitempty"`
+	// Enrichment used to copy the metadata of the Kubernetes objects the alerts belong to onto the alerts before they are routed.
+	Enrichment *Enrichment `json:"enrichment,omitempty"`
 	// Template used to define information about templates
 	Template *Template `json:"template,omitempty"`
 
@@ -180,6 +255,9 @@ type GlobalOptions struct {
 	Template string `json:"template,omitempty"`
 	// The name of the cluster in which the notification manager is deployed.
 	Cluster string `json:"cluster,omitempty"`
+	// The retry policy used to retry the failed notifications.
+	// If the receiver type dose not setup retry policy, it will use this.
+	Retry *RetryPolicy `json:"retry,omitempty"`
 }
 
 type EmailOptions struct {
@@ -196,6 +274,8 @@ type EmailOptions struct {
 	SubjectTemplate string `json:"subjectTemplate,omitempty"`
 	// template type: text or html, default type is html
 	TmplType string `json:"tmplType,omitempty"`
+	// The retry policy used to retry the failed notifications.
+	Retry *RetryPolicy `json:"retry,omitempty"`
 }
 
 type WechatOptions struct {
@@ -209,6 +289,8 @@ type WechatOptions struct {
 	MessageMaxSize int `json:"messageMaxSize,omitempty"`
 	// The time of token expired.
 	TokenExpires time.Duration `json:"tokenExpires,omitempty"`
+	// The retry policy used to retry the failed notifications.
+	Retry *RetryPolicy `json:"retry,omitempty"`
 }
 
 type SlackOptions struct {
@@ -217,6 +299,8 @@ type SlackOptions struct {
 	// The name of the template to generate Slack message.
 	// If the global template is not set, it will use default.
 	Template string `json:"template,omitempty"`
+	// The retry policy used to retry the failed notifications.
+	Retry *RetryPolicy `json:"retry,omitempty"`
 }
 
 type WebhookOptions struct {
@@ -225,6 +309,30 @@ type WebhookOptions struct {
 	// The name of the template to generate webhook message.
 	// If the global template is not set, it will use default.
 	Template string `json:"template,omitempty"`
+	// The retry policy used to retry the failed notifications.
+	Retry *RetryPolicy `json:"retry,omitempty"`
+}
+
+// RetryPolicy defines how to retry a notification which failed to be sent.
+type RetryPolicy struct {
+	// The maximum number of attempts to send a notification, including the first one.
+	// A notification which still fails after all attempts will be put into the dead-letter queue.
+	// Defaults to 1, which means do not retry.
+	//
+	// +kubebuilder:validation:Minimum=1
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// The time to wait before the first retry, the backoff doubles after every failed retry.
+	// Defaults to 1s if not specified.
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+	// The maximum time to wait between two attempts.
+	// Defaults to 1m if not specified.
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+	// The maximum percentage of the backoff which will be randomly added to or subtracted from the backoff,
+	// so that the retries of different notifications will not happen at the same time.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Jitter int `json:"jitter,omitempty"`
 }
 
 // Throttle is the config of flow control.
@@ -258,6 +366,8 @@ type DingTalkOptions struct {
 	ChatBotThrottle *Throttle `json:"chatBotThrottle,omitempty"`
 	// The flow control for conversation.
 	ConversationThrottle *Throttle `json:"conversationThrottle,omitempty"`
+	// The retry policy used to retry the failed notifications.
+	Retry *RetryPolicy `json:"retry,omitempty"`
 }
 
 type SmsOptions struct {
@@ -266,6 +376,8 @@ type SmsOptions struct {
 	// The name of the template to generate sms message.
 	// If the global template is not set, it will use default.
 	Template string `json:"template,omitempty"`
+	// The retry policy used to retry the failed notifications.
+	Retry *RetryPolicy `json:"retry,omitempty"`
 }
 
 type PushoverOptions struct {
@@ -276,6 +388,8 @@ type PushoverOptions struct {
 	Template string `json:"template,omitempty"`
 	// The name of the template to generate message title
 	TitleTemplate string `json:"titleTemplate,omitempty"`
+	// The retry policy used to retry the failed notifications.
+	Retry *RetryPolicy `json:"retry,omitempty"`
 }
 
 type FeishuOptions struct {
@@ -288,6 +402,8 @@ type FeishuOptions struct {
 	TmplType string `json:"tmplType,omitempty"`
 	// The time of token expired.
 	TokenExpires time.Duration `json:"tokenExpires,omitempty"`
+	// The retry policy used to retry the failed notifications.
+	Retry *RetryPolicy `json:"retry,omitempty"`
 }
 
 type DiscordOptions struct {
@@ -295,6 +411,8 @@ type DiscordOptions struct {
 	NotificationTimeout *int32 `json:"notificationTimeout,omitempty"`
 
 	Template string `json:"template,omitempty"`
+	// The retry policy used to retry the failed notifications.
+	Retry *RetryPolicy `json:"retry,omitempty"`
 }
 
 type TelegramOptions struct {
@@ -303,6 +421,8 @@ type TelegramOptions struct {
 	// The name of the template to generate telegram message.
 	// If the global template is not set, it will use default.
 	Template string `json:"template,omitempty"`
+	// The retry policy used to retry the failed notifications.
+	Retry *RetryPolicy `json:"retry,omitempty"`
 }
 
 type Options struct {
@@ -321,12 +441,29 @@ type Options struct {
 
 // NotificationManagerStatus defines the observed state of NotificationManager
 type NotificationManagerStatus struct {
+	// The number of notifications sent successfully, including the notifications sent to the receivers
+	// which are not defined by the Receiver CRDs, such as the receivers of the tenant sidecar.
+	SentNotifications int64 `json:"sentNotifications,omitempty"`
+	// The number of notifications which failed to be sent.
+	FailedNotifications int64 `json:"failedNotifications,omitempty"`
+	// The last time a notification was sent successfully.
+	LastSuccessTime *metav1.Time `json:"lastSuccessTime,omitempty"`
+	// The last time a notification failed to be sent.
+	LastErrorTime *metav1.Time `json:"lastErrorTime,omitempty"`
+	// The error of the last failed notification.
+	LastError string `json:"lastError,omitempty"`
+	// The number of the silences which are active now.
+	ActiveSilences int32 `json:"activeSilences,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Cluster,shortName=nm,categories=notification-manager
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Sent",type=integer,JSONPath=`.status.sentNotifications`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failedNotifications`
+// +kubebuilder:printcolumn:name="Active Silences",type=integer,JSONPath=`.status.activeSilences`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // NotificationManager is the Schema for the notificationmanagers API
 type NotificationManager struct {
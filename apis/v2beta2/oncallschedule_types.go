@@ -0,0 +1,111 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	RotationHourly = "hourly"
+	RotationDaily  = "daily"
+	RotationWeekly = "weekly"
+)
+
+// OnCallRotation defines how often the participants of a layer hand off.
+type OnCallRotation struct {
+	// The unit of the shift length, known values are hourly, daily, weekly.
+	// The daily and weekly rotations hand off at the same local time of the handoff time, even across daylight saving time changes.
+	//
+	// +kubebuilder:validation:Enum=hourly;daily;weekly
+	// +kubebuilder:default=weekly
+	Type string `json:"type,omitempty"`
+	// The number of units of each shift.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	ShiftLength int `json:"shiftLength,omitempty"`
+}
+
+// OnCallLayer defines a rotation of the participants.
+type OnCallLayer struct {
+	Name string `json:"name"`
+	// The time of the first handoff, the first participant is on call since then.
+	Start metav1.Time `json:"start"`
+	// The time the layer ends, the layer never ends if not set.
+	End *metav1.Time `json:"end,omitempty"`
+	// How often the participants hand off.
+	Rotation OnCallRotation `json:"rotation,omitempty"`
+	// The participants which take turns to be on call. Each participant selects the existing receivers by name or selector.
+	//
+	// +kubebuilder:validation:MinItems=1
+	Participants []ReceiverSelector `json:"participants"`
+}
+
+// OnCallOverride replaces the on-call receivers of the schedule in a time range.
+type OnCallOverride struct {
+	Start metav1.Time `json:"start"`
+	End   metav1.Time `json:"end"`
+	// The receivers which are on call in the time range.
+	Receivers ReceiverSelector `json:"receivers"`
+}
+
+// OnCallScheduleSpec defines the desired state of OnCallSchedule
+type OnCallScheduleSpec struct {
+	// The time zone used to calculate the handoff times, such as `Asia/Shanghai`.
+	//
+	// +kubebuilder:default=UTC
+	TimeZone string `json:"timeZone,omitempty"`
+	// The layers of the schedule. If several layers have someone on call at the same time,
+	// the layer listed later takes precedence.
+	//
+	// +kubebuilder:validation:MinItems=1
+	Layers []OnCallLayer `json:"layers"`
+	// The overrides take precedence over the layers.
+	Overrides []OnCallOverride `json:"overrides,omitempty"`
+}
+
+// OnCallScheduleStatus defines the observed state of OnCallSchedule
+type OnCallScheduleStatus struct {
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories=notification-manager
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+
+// OnCallSchedule is the Schema for the OnCallSchedule API
+type OnCallSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OnCallScheduleSpec   `json:"spec,omitempty"`
+	Status OnCallScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OnCallScheduleList contains a list of OnCallSchedule
+type OnCallScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OnCallSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OnCallSchedule{}, &OnCallScheduleList{})
+}
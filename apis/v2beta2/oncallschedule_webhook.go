@@ -0,0 +1,120 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func (o *OnCallSchedule) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(o).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,mutating=false,failurePolicy=fail,groups=notification.kubesphere.io,resources=oncallschedules,versions=v2beta2
+var _ webhook.Validator = &OnCallSchedule{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (o *OnCallSchedule) ValidateCreate() (warnings admission.Warnings, err error) {
+
+	return o.validateOnCallSchedule()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (o *OnCallSchedule) ValidateUpdate(_ runtime.Object) (warnings admission.Warnings, err error) {
+	return o.validateOnCallSchedule()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (o *OnCallSchedule) ValidateDelete() (warnings admission.Warnings, err error) {
+	return admission.Warnings{}, nil
+}
+
+func (o *OnCallSchedule) validateOnCallSchedule() (warnings admission.Warnings, err error) {
+	var allErrs field.ErrorList
+
+	if o.Spec.TimeZone != "" {
+		if _, err := time.LoadLocation(o.Spec.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "timeZone"), o.Spec.TimeZone, err.Error()))
+		}
+	}
+
+	if len(o.Spec.Layers) == 0 {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "layers"), "must have at least one layer"))
+	}
+
+	for index, layer := range o.Spec.Layers {
+		path := field.NewPath("spec", "layers").Index(index)
+		if layer.End != nil && !layer.End.After(layer.Start.Time) {
+			allErrs = append(allErrs, field.Invalid(path.Child("end"), layer.End, "must be after start"))
+		}
+
+		switch layer.Rotation.Type {
+		case "", RotationHourly, RotationDaily, RotationWeekly:
+		default:
+			allErrs = append(allErrs, field.NotSupported(path.Child("rotation", "type"), layer.Rotation.Type, []string{RotationHourly, RotationDaily, RotationWeekly}))
+		}
+
+		if layer.Rotation.ShiftLength < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("rotation", "shiftLength"), layer.Rotation.ShiftLength, "must not be negative"))
+		}
+
+		if len(layer.Participants) == 0 {
+			allErrs = append(allErrs, field.Required(path.Child("participants"), "must have at least one participant"))
+		}
+
+		for i, participant := range layer.Participants {
+			allErrs = append(allErrs, validateParticipant(path.Child("participants").Index(i), participant)...)
+		}
+	}
+
+	for index, override := range o.Spec.Overrides {
+		path := field.NewPath("spec", "overrides").Index(index)
+		if !override.End.After(override.Start.Time) {
+			allErrs = append(allErrs, field.Invalid(path.Child("end"), override.End, "must be after start"))
+		}
+
+		allErrs = append(allErrs, validateParticipant(path.Child("receivers"), override.Receivers)...)
+	}
+
+	if allErrs == nil || len(allErrs) == 0 {
+		return admission.Warnings{}, nil
+	}
+
+	return admission.Warnings{}, errors.NewInvalid(
+		schema.GroupKind{Group: "notification.kubesphere.io", Kind: "OnCallSchedule"},
+		o.Name, allErrs)
+}
+
+// validateParticipant validates the receiver selector of the participant, a participant can not reference another schedule.
+func validateParticipant(path *field.Path, rs ReceiverSelector) field.ErrorList {
+	allErrs := validateReceiverSelector(path, rs)
+	if rs.Schedule != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("schedule"), "can not reference another schedule"))
+	}
+
+	return allErrs
+}
@@ -50,6 +50,12 @@ type DingTalkReceiver struct {
 	DingTalkConfigSelector *LabelSelector `json:"dingtalkConfigSelector,omitempty"`
 	// Selector to filter alerts.
 	AlertSelector *LabelSelector `json:"alertSelector,omitempty"`
+	// Labels for grouping the notifications sent to this receiver,
+	// it overrides the groupLabels of the router and the NotificationManager.
+	GroupLabels []string `json:"groupLabels,omitempty"`
+	// The name of the EscalationPolicy used to escalate the firing alerts sent to this receiver,
+	// it overrides the escalationPolicy of the router.
+	EscalationPolicy string `json:"escalationPolicy,omitempty"`
 	// Be careful, a ChatBot only can send 20 message per minute.
 	ChatBot *DingTalkChatBot `json:"chatbot,omitempty"`
 	// The conversation which message will send to.
@@ -74,6 +80,12 @@ type EmailReceiver struct {
 	EmailConfigSelector *LabelSelector `json:"emailConfigSelector,omitempty"`
 	// Selector to filter alerts.
 	AlertSelector *LabelSelector `json:"alertSelector,omitempty"`
+	// Labels for grouping the notifications sent to this receiver,
+	// it overrides the groupLabels of the router and the NotificationManager.
+	GroupLabels []string `json:"groupLabels,omitempty"`
+	// The name of the EscalationPolicy used to escalate the firing alerts sent to this receiver,
+	// it overrides the escalationPolicy of the router.
+	EscalationPolicy string `json:"escalationPolicy,omitempty"`
 	// The name of the template to generate notification.
 	// If the global template is not set, it will use default.
 	Template *string `json:"template,omitempty"`
@@ -92,6 +104,12 @@ type SlackReceiver struct {
 	SlackConfigSelector *LabelSelector `json:"slackConfigSelector,omitempty"`
 	// Selector to filter alerts.
 	AlertSelector *LabelSelector `json:"alertSelector,omitempty"`
+	// Labels for grouping the notifications sent to this receiver,
+	// it overrides the groupLabels of the router and the NotificationManager.
+	GroupLabels []string `json:"groupLabels,omitempty"`
+	// The name of the EscalationPolicy used to escalate the firing alerts sent to this receiver,
+	// it overrides the escalationPolicy of the router.
+	EscalationPolicy string `json:"escalationPolicy,omitempty"`
 	// The channel or user to send notifications to.
 	Channels []string `json:"channels"`
 	// The name of the template to generate notification.
@@ -134,6 +152,12 @@ type WebhookReceiver struct {
 	WebhookConfigSelector *LabelSelector `json:"webhookConfigSelector,omitempty"`
 	// Selector to filter alerts.
 	AlertSelector *LabelSelector `json:"alertSelector,omitempty"`
+	// Labels for grouping the notifications sent to this receiver,
+	// it overrides the groupLabels of the router and the NotificationManager.
+	GroupLabels []string `json:"groupLabels,omitempty"`
+	// The name of the EscalationPolicy used to escalate the firing alerts sent to this receiver,
+	// it overrides the escalationPolicy of the router.
+	EscalationPolicy string `json:"escalationPolicy,omitempty"`
 	// `url` gives the location of the webhook, in standard URL form
 	// (`scheme://host:port/path`). Exactly one of `url` or `service`
 	// must be specified.
@@ -184,6 +208,12 @@ type WechatReceiver struct {
 	WechatConfigSelector *LabelSelector `json:"wechatConfigSelector,omitempty"`
 	// Selector to filter alerts.
 	AlertSelector *LabelSelector `json:"alertSelector,omitempty"`
+	// Labels for grouping the notifications sent to this receiver,
+	// it overrides the groupLabels of the router and the NotificationManager.
+	GroupLabels []string `json:"groupLabels,omitempty"`
+	// The name of the EscalationPolicy used to escalate the firing alerts sent to this receiver,
+	// it overrides the escalationPolicy of the router.
+	EscalationPolicy string `json:"escalationPolicy,omitempty"`
 	// +optional
 	ToUser  []string `json:"toUser,omitempty"`
 	ToParty []string `json:"toParty,omitempty"`
@@ -229,6 +259,12 @@ type DiscordReceiver struct {
 
 	// Selector to filter alerts.
 	AlertSelector *LabelSelector `json:"alertSelector,omitempty"`
+	// Labels for grouping the notifications sent to this receiver,
+	// it overrides the groupLabels of the router and the NotificationManager.
+	GroupLabels []string `json:"groupLabels,omitempty"`
+	// The name of the EscalationPolicy used to escalate the firing alerts sent to this receiver,
+	// it overrides the escalationPolicy of the router.
+	EscalationPolicy string `json:"escalationPolicy,omitempty"`
 }
 
 type SmsReceiver struct {
@@ -238,6 +274,12 @@ type SmsReceiver struct {
 	SmsConfigSelector *LabelSelector `json:"smsConfigSelector,omitempty"`
 	// Selector to filter alerts.
 	AlertSelector *LabelSelector `json:"alertSelector,omitempty"`
+	// Labels for grouping the notifications sent to this receiver,
+	// it overrides the groupLabels of the router and the NotificationManager.
+	GroupLabels []string `json:"groupLabels,omitempty"`
+	// The name of the EscalationPolicy used to escalate the firing alerts sent to this receiver,
+	// it overrides the escalationPolicy of the router.
+	EscalationPolicy string `json:"escalationPolicy,omitempty"`
 	// Receivers' phone numbers
 	PhoneNumbers []string `json:"phoneNumbers"`
 	// The name of the template to generate notification.
@@ -270,6 +312,12 @@ type PushoverReceiver struct {
 	PushoverConfigSelector *LabelSelector `json:"pushoverConfigSelector,omitempty"`
 	// Selector to filter alerts.
 	AlertSelector *LabelSelector `json:"alertSelector,omitempty"`
+	// Labels for grouping the notifications sent to this receiver,
+	// it overrides the groupLabels of the router and the NotificationManager.
+	GroupLabels []string `json:"groupLabels,omitempty"`
+	// The name of the EscalationPolicy used to escalate the firing alerts sent to this receiver,
+	// it overrides the escalationPolicy of the router.
+	EscalationPolicy string `json:"escalationPolicy,omitempty"`
 	// The name of the template to generate notification.
 	// If the global template is not set, it will use default.
 	Template *string `json:"template,omitempty"`
@@ -300,6 +348,12 @@ type FeishuReceiver struct {
 	FeishuConfigSelector *LabelSelector `json:"feishuConfigSelector,omitempty"`
 	// Selector to filter alerts.
 	AlertSelector *LabelSelector `json:"alertSelector,omitempty"`
+	// Labels for grouping the notifications sent to this receiver,
+	// it overrides the groupLabels of the router and the NotificationManager.
+	GroupLabels []string `json:"groupLabels,omitempty"`
+	// The name of the EscalationPolicy used to escalate the firing alerts sent to this receiver,
+	// it overrides the escalationPolicy of the router.
+	EscalationPolicy string `json:"escalationPolicy,omitempty"`
 	// +optional
 	// +kubebuilder:validation:MaxItems=200
 	User []string `json:"user,omitempty"`
@@ -323,6 +377,12 @@ type TelegramReceiver struct {
 	TelegramConfigSelector *LabelSelector `json:"telegramConfigSelector,omitempty"`
 	// Selector to filter alerts.
 	AlertSelector *LabelSelector `json:"alertSelector,omitempty"`
+	// Labels for grouping the notifications sent to this receiver,
+	// it overrides the groupLabels of the router and the NotificationManager.
+	GroupLabels []string `json:"groupLabels,omitempty"`
+	// The name of the EscalationPolicy used to escalate the firing alerts sent to this receiver,
+	// it overrides the escalationPolicy of the router.
+	EscalationPolicy string `json:"escalationPolicy,omitempty"`
 	// The channel or user to send notifications to.
 	Channels []string `json:"channels"`
 	//optional
@@ -351,12 +411,27 @@ type ReceiverSpec struct {
 
 // ReceiverStatus defines the observed state of Receiver
 type ReceiverStatus struct {
+	// The number of notifications sent to the receiver successfully.
+	SentNotifications int64 `json:"sentNotifications,omitempty"`
+	// The number of notifications which failed to be sent to the receiver.
+	FailedNotifications int64 `json:"failedNotifications,omitempty"`
+	// The last time a notification was sent to the receiver successfully.
+	LastSuccessTime *metav1.Time `json:"lastSuccessTime,omitempty"`
+	// The last time a notification failed to be sent to the receiver.
+	LastErrorTime *metav1.Time `json:"lastErrorTime,omitempty"`
+	// The error of the last failed notification.
+	LastError string `json:"lastError,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Cluster,shortName=nr,categories=notification-manager
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Sent",type=integer,JSONPath=`.status.sentNotifications`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failedNotifications`
+// +kubebuilder:printcolumn:name="Last Success",type=date,JSONPath=`.status.lastSuccessTime`
+// +kubebuilder:printcolumn:name="Last Error",type=string,JSONPath=`.status.lastError`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Receiver is the Schema for the receivers API
 type Receiver struct {
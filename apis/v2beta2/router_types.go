@@ -17,6 +17,9 @@ limitations under the License.
 package v2beta2
 
 import (
+	"encoding/json"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -33,6 +36,8 @@ type ReceiverSelector struct {
 	Channels  []Channel      `json:"channels,omitempty"`
 	// Receiver type, known values are dingtalk, email, slack, sms, pushover, webhook, wechat.
 	Type string `json:"type,omitempty"`
+	// The name of the OnCallSchedule, the receivers which are on call when the alert is routed will be selected.
+	Schedule string `json:"schedule,omitempty"`
 }
 
 // RouterSpec defines the desired state of Router
@@ -42,16 +47,99 @@ type RouterSpec struct {
 	AlertSelector *LabelSelector `json:"alertSelector"`
 	// Receivers which need to receive the matched alert.
 	Receivers ReceiverSelector `json:"receivers"`
+	// Labels for grouping the notifications sent to the receivers of this router,
+	// it overrides the groupLabels of the NotificationManager.
+	GroupLabels []string `json:"groupLabels,omitempty"`
+	// How long to wait before sending the first notification of a group,
+	// it overrides the groupWait of the NotificationManager.
+	GroupWait *metav1.Duration `json:"groupWait,omitempty"`
+	// How long to wait before sending the next notification of a group,
+	// it overrides the groupInterval of the NotificationManager.
+	GroupInterval *metav1.Duration `json:"groupInterval,omitempty"`
+	// The name of the EscalationPolicy used to escalate the firing alerts sent to the receivers of this router.
+	EscalationPolicy string `json:"escalationPolicy,omitempty"`
+	// The router is only active in these time intervals. The router is always active if not set.
+	ActiveTimeIntervals *TimeIntervals `json:"activeTimeIntervals,omitempty"`
+	// The router is inactive in these time intervals, it takes precedence over the active time intervals.
+	InactiveTimeIntervals *TimeIntervals `json:"inactiveTimeIntervals,omitempty"`
+	// The routers with higher priority are evaluated first, the routers with the same priority are evaluated by name.
+	Priority int32 `json:"priority,omitempty"`
+	// Whether to continue evaluating the subsequent routers after the alert matches this router.
+	// The alert is sent to the receivers of all the matched routers by default,
+	// set it to false to stop the evaluation at this router.
+	Continue *bool `json:"continue,omitempty"`
+	// The RoutePolicy used for the alerts which match this router, it overrides the routePolicy of the NotificationManager.
+	// If an alert matches several routers, the routePolicy of the first matched router which sets it is used.
+	//
+	// +kubebuilder:validation:Enum=All;RouterFirst;RouterOnly
+	RoutePolicy string `json:"routePolicy,omitempty"`
+	// The child routes of the router, each item is a Route.
+	// The alerts which match the router are matched against the child routes in order,
+	// the alerts will be sent to the receivers of the router only if they match none of the child routes.
+	//
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Routes []apiextensionsv1.JSON `json:"routes,omitempty"`
+}
+
+// Route is a node of the routing tree, it inherits the unset settings from its parent.
+type Route struct {
+	// The alerts matched by the route, a route without alertSelector matches all alerts.
+	AlertSelector *LabelSelector `json:"alertSelector,omitempty"`
+	// Receivers which need to receive the matched alert, it inherits the receivers of the parent if not set.
+	Receivers *ReceiverSelector `json:"receivers,omitempty"`
+	// Labels for grouping the notifications sent to the receivers of this route.
+	GroupLabels []string `json:"groupLabels,omitempty"`
+	// How long to wait before sending the first notification of a group.
+	GroupWait *metav1.Duration `json:"groupWait,omitempty"`
+	// How long to wait before sending the next notification of a group.
+	GroupInterval *metav1.Duration `json:"groupInterval,omitempty"`
+	// The name of the EscalationPolicy used to escalate the firing alerts sent to the receivers of this route.
+	EscalationPolicy string `json:"escalationPolicy,omitempty"`
+	// Whether to continue matching the subsequent sibling routes after the alert matches this route.
+	// The alert is sent to the receivers of all the matched routes by default,
+	// set it to false to stop matching at this route.
+	Continue *bool `json:"continue,omitempty"`
+	// The child routes, each item is a Route.
+	Routes []apiextensionsv1.JSON `json:"routes,omitempty"`
+}
+
+// Continues returns whether to continue the evaluation after the alert matches the router or route
+// whose continue field is c, nil means true.
+func Continues(c *bool) bool {
+	return c == nil || *c
+}
+
+// ParseRoutes parses the child routes.
+func ParseRoutes(raw []apiextensionsv1.JSON) ([]Route, error) {
+
+	var routes []Route
+	for _, item := range raw {
+		route := Route{}
+		if err := json.Unmarshal(item.Raw, &route); err != nil {
+			return nil, err
+		}
+		routes = append(routes, route)
+	}
+
+	return routes, nil
 }
 
 // RouterStatus defines the observed state of Router
 type RouterStatus struct {
+	// The number of alerts matched by the router.
+	MatchedAlerts int64 `json:"matchedAlerts,omitempty"`
+	// The last time an alert matched the router.
+	LastMatchTime *metav1.Time `json:"lastMatchTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Cluster,categories=notification-manager
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Matched",type=integer,JSONPath=`.status.matchedAlerts`
+// +kubebuilder:printcolumn:name="Last Match",type=date,JSONPath=`.status.lastMatchTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Router is the Schema for the router API
 type Router struct {
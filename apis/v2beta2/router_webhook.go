@@ -17,8 +17,11 @@ limitations under the License.
 package v2beta2
 
 import (
+	"fmt"
+	"reflect"
 	"regexp"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -28,6 +31,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
+// The max depth of the routing tree.
+const maxRouteDepth = 10
+
 func (r *Router) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
@@ -60,21 +66,94 @@ func (r *Router) validateRouter() (warnings admission.Warnings, err error) {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "alertSelector"), r.Spec.AlertSelector, err.Error()))
 	}
 
-	if err := validateSelector(r.Spec.Receivers.Selector); err != nil {
-		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "receivers", "alertSelector"), r.Spec.Receivers.Selector, err.Error()))
+	allErrs = append(allErrs, validateReceiverSelector(field.NewPath("spec", "receivers"), r.Spec.Receivers)...)
+
+	if r.Spec.ActiveTimeIntervals != nil {
+		allErrs = append(allErrs, validateTimePeriods(field.NewPath("spec", "activeTimeIntervals", "periods"), r.Spec.ActiveTimeIntervals.Periods)...)
 	}
 
-	if r.Spec.Receivers.RegexName != "" {
-		if _, err := regexp.Compile(r.Spec.Receivers.RegexName); err != nil {
-			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "receivers", "regexName"), r.Spec.Receivers.RegexName, err.Error()))
-		}
+	if r.Spec.InactiveTimeIntervals != nil {
+		allErrs = append(allErrs, validateTimePeriods(field.NewPath("spec", "inactiveTimeIntervals", "periods"), r.Spec.InactiveTimeIntervals.Periods)...)
 	}
 
+	allErrs = append(allErrs, validateRoutes(field.NewPath("spec", "routes"), r.Spec.Routes, 1)...)
+
 	if allErrs == nil || len(allErrs) == 0 {
 		return admission.Warnings{}, nil
 	}
 
 	return admission.Warnings{}, errors.NewInvalid(
-		schema.GroupKind{Group: "notification.kubesphere.io", Kind: "Receiver"},
+		schema.GroupKind{Group: "notification.kubesphere.io", Kind: "Router"},
 		r.Name, allErrs)
 }
+
+func validateReceiverSelector(path *field.Path, rs ReceiverSelector) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if err := validateSelector(rs.Selector); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("selector"), rs.Selector, err.Error()))
+	}
+
+	if rs.RegexName != "" {
+		if _, err := regexp.Compile(rs.RegexName); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("regexName"), rs.RegexName, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// validateRoutes validates the child routes recursively, and checks whether there are unreachable routes.
+// A route is unreachable if a previous sibling route which does not continue matches all the alerts it matches.
+func validateRoutes(path *field.Path, raw []apiextensionsv1.JSON, depth int) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(raw) == 0 {
+		return allErrs
+	}
+
+	if depth > maxRouteDepth {
+		return append(allErrs, field.Invalid(path, depth, fmt.Sprintf("the routing tree must not be deeper than %d", maxRouteDepth)))
+	}
+
+	var previous []Route
+	for index, item := range raw {
+		routePath := path.Index(index)
+		routes, err := ParseRoutes([]apiextensionsv1.JSON{item})
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(routePath, string(item.Raw), err.Error()))
+			continue
+		}
+		route := routes[0]
+
+		if err := validateSelector(route.AlertSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(routePath.Child("alertSelector"), route.AlertSelector, err.Error()))
+		}
+
+		if route.Receivers != nil {
+			allErrs = append(allErrs, validateReceiverSelector(routePath.Child("receivers"), *route.Receivers)...)
+		}
+
+		for i, p := range previous {
+			if Continues(p.Continue) {
+				continue
+			}
+
+			if matchesAll(p.AlertSelector) || reflect.DeepEqual(p.AlertSelector, route.AlertSelector) {
+				allErrs = append(allErrs, field.Invalid(routePath, string(item.Raw),
+					fmt.Sprintf("unreachable, the alerts it matches will be matched by %s which does not continue", path.Index(i))))
+				break
+			}
+		}
+		previous = append(previous, route)
+
+		allErrs = append(allErrs, validateRoutes(routePath.Child("routes"), route.Routes, depth+1)...)
+	}
+
+	return allErrs
+}
+
+// matchesAll returns true if the selector matches all the alerts.
+func matchesAll(selector *LabelSelector) bool {
+	return selector == nil || (len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0)
+}
@@ -24,6 +24,24 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// SilenceArchivedLabel is the label of the silences archived after they expired, the archived silences are ignored.
+	SilenceArchivedLabel = "notification.kubesphere.io/archived"
+)
+
+// SilenceReceiverSelector selects the receivers to which the silence is applied.
+// All the fields set must match, and the silence is applied to all receivers if none is set.
+type SilenceReceiverSelector struct {
+	// The names of the receivers.
+	Name []string `json:"name,omitempty"`
+	// The regular expression to match the names of the receivers.
+	RegexName string `json:"regexName,omitempty"`
+	// The label selector to match the labels of the receivers.
+	Selector *LabelSelector `json:"selector,omitempty"`
+	// Receiver types, known values are dingtalk, discord, email, feishu, pushover, slack, sms, telegram, webhook, wechat.
+	Type []string `json:"type,omitempty"`
+}
+
 // SilenceSpec defines the desired state of Silence
 type SilenceSpec struct {
 	// whether the silence is enabled
@@ -35,20 +53,48 @@ type SilenceSpec struct {
 	StartsAt *metav1.Time `json:"startsAt,omitempty"`
 	// The schedule in Cron format.
 	// If set the silence will be active periodicity, and the startsAt will be invalid.
+	// If both the schedule and the timeWindows are set, the silence is active only when both of them match.
 	Schedule string `json:"schedule,omitempty"`
 	// The time range during which the silence is active.
 	// If not set, the silence will be active ever.
 	Duration *metav1.Duration `json:"duration,omitempty"`
+	// The time zone in which the schedule and the time windows are evaluated, such as `Asia/Shanghai`,
+	// default is the local time zone of Notification Manager.
+	TimeZone string `json:"timeZone,omitempty"`
+	// The time windows during which the silence is active, the silence is active if any of the time windows matches.
+	// The time window without location is evaluated in the timeZone.
+	TimeWindows []TimePeriod `json:"timeWindows,omitempty"`
+	// The time after which the silence is not active anymore, it takes precedence over all the other fields.
+	//
+	// +kubebuilder:validation:Format: date-time
+	EndsAt *metav1.Time `json:"endsAt,omitempty"`
+	// The receivers to which the silence is applied.
+	// If not set, the silence is applied to all the receivers of the tenant, or all the receivers if it is a global silence.
+	Receivers *SilenceReceiverSelector `json:"receivers,omitempty"`
+	// The author of the silence, a notification will be sent to the receivers of the author before the silence expires.
+	CreatedBy string `json:"createdBy,omitempty"`
+	// The comment of the silence.
+	Comment string `json:"comment,omitempty"`
 }
 
 // SilenceStatus defines the observed state of Silence
 type SilenceStatus struct {
+	// Whether the silence is active.
+	Active bool `json:"active,omitempty"`
+	// The number of alerts suppressed by the silence.
+	SuppressedAlerts int64 `json:"suppressedAlerts,omitempty"`
+	// The last time an alert was suppressed by the silence.
+	LastSuppressedTime *metav1.Time `json:"lastSuppressedTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Cluster,categories=notification-manager
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Active",type=boolean,JSONPath=`.status.active`
+// +kubebuilder:printcolumn:name="Suppressed",type=integer,JSONPath=`.status.suppressedAlerts`
+// +kubebuilder:printcolumn:name="CreatedBy",type=string,JSONPath=`.spec.createdBy`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Silence is the Schema for the Silence API
 type Silence struct {
@@ -73,25 +119,26 @@ func init() {
 }
 
 func (s *Silence) IsActive() bool {
+	return s.IsActiveAt(time.Now())
+}
+
+// IsActiveAt returns true if the silence is active at the time.
+func (s *Silence) IsActiveAt(now time.Time) bool {
 
 	if s.Spec.Enabled != nil && !*s.Spec.Enabled {
 		return false
 	}
 
-	if !utils.StringIsNil(s.Spec.Schedule) {
-
-		if s.Spec.Duration == nil {
-			return true
-		}
+	if s.Spec.EndsAt != nil && !s.Spec.EndsAt.After(now) {
+		return false
+	}
 
-		schedule, _ := cron.ParseStandard(s.Spec.Schedule)
-		if schedule.Next(time.Now()) == schedule.Next(time.Now().Add(-(*s.Spec.Duration).Duration)) {
-			return false
-		} else {
-			return true
-		}
+	if !utils.StringIsNil(s.Spec.Schedule) || len(s.Spec.TimeWindows) > 0 {
+		// Both the schedule and the time windows must match if they are set.
+		return (utils.StringIsNil(s.Spec.Schedule) || s.inSchedule(now)) &&
+			(len(s.Spec.TimeWindows) == 0 || s.inTimeWindows(now))
 	} else if s.Spec.StartsAt != nil {
-		if s.Spec.StartsAt.After(time.Now()) {
+		if s.Spec.StartsAt.After(now) {
 			return false
 		}
 
@@ -99,7 +146,7 @@ func (s *Silence) IsActive() bool {
 			return true
 		}
 
-		if s.Spec.StartsAt.Add((*s.Spec.Duration).Duration).After(time.Now()) {
+		if s.Spec.StartsAt.Add((*s.Spec.Duration).Duration).After(now) {
 			return true
 		}
 
@@ -108,3 +155,106 @@ func (s *Silence) IsActive() bool {
 		return true
 	}
 }
+
+// ExpiresAt returns the time after which the silence will never be active, nil will be returned if the silence never expires.
+// The duration is only valid for the silence which is not scheduled periodically.
+func (s *Silence) ExpiresAt() *time.Time {
+
+	var expiresAt *time.Time
+	if s.Spec.StartsAt != nil && s.Spec.Duration != nil && utils.StringIsNil(s.Spec.Schedule) && len(s.Spec.TimeWindows) == 0 {
+		t := s.Spec.StartsAt.Add(s.Spec.Duration.Duration)
+		expiresAt = &t
+	}
+
+	if s.Spec.EndsAt != nil && (expiresAt == nil || s.Spec.EndsAt.Time.Before(*expiresAt)) {
+		t := s.Spec.EndsAt.Time
+		expiresAt = &t
+	}
+
+	return expiresAt
+}
+
+// Location returns the time zone of the silence, the local time zone is returned if it is not set.
+func (s *Silence) Location() (*time.Location, error) {
+	if utils.StringIsNil(s.Spec.TimeZone) {
+		return time.Local, nil
+	}
+
+	return time.LoadLocation(s.Spec.TimeZone)
+}
+
+// inSchedule returns true if the time is in the duration after the time the cron schedule is activated,
+// the silence scheduled without a duration is always in the schedule.
+func (s *Silence) inSchedule(now time.Time) bool {
+
+	if s.Spec.Duration == nil {
+		return true
+	}
+
+	loc, err := s.Location()
+	if err != nil {
+		return false
+	}
+
+	schedule, err := cron.ParseStandard(s.Spec.Schedule)
+	if err != nil {
+		return false
+	}
+
+	// The schedule is evaluated in the time zone of the silence, so that it follows the daylight saving time changes.
+	now = now.In(loc)
+	return schedule.Next(now) != schedule.Next(now.Add(-(*s.Spec.Duration).Duration))
+}
+
+// inTimeWindows returns true if the time is in any of the time windows, the time windows are only valid after startsAt.
+func (s *Silence) inTimeWindows(now time.Time) bool {
+
+	if s.Spec.StartsAt != nil && s.Spec.StartsAt.After(now) {
+		return false
+	}
+
+	loc, err := s.Location()
+	if err != nil {
+		return false
+	}
+
+	for _, window := range s.Spec.TimeWindows {
+		w := window
+		if utils.StringIsNil(w.Location) {
+			// The name of the local time zone is "Local", which is loaded as the local time zone as well.
+			w.Location = loc.String()
+		}
+
+		if ok, err := w.Contains(now); err == nil && ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchReceiver returns true if the receiver with the name, type and labels is selected by the silence.
+// The selector is the compiled label selector of the receivers, such as the one cached by the matcher cache,
+// the receivers are not selected if the label selector is set but the selector is nil.
+func (s *Silence) MatchReceiver(name, receiverType string, labels map[string]string, selector *Matcher) bool {
+
+	rs := s.Spec.Receivers
+	if rs == nil {
+		return true
+	}
+
+	if (len(rs.Name) > 0 || !utils.StringIsNil(rs.RegexName)) &&
+		!utils.StringInList(name, rs.Name) && !utils.RegularMatch(rs.RegexName, name) {
+		return false
+	}
+
+	if len(rs.Type) > 0 && !utils.StringInList(receiverType, rs.Type) {
+		return false
+	}
+
+	if rs.Selector != nil && (selector == nil || !selector.Matches(labels)) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,104 @@
+package v2beta2
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSilenceIsActiveAt(t *testing.T) {
+	hour := &metav1.Duration{Duration: time.Hour}
+	threeHours := &metav1.Duration{Duration: 3 * time.Hour}
+	halfHour := &metav1.Duration{Duration: 30 * time.Minute}
+	disabled := false
+
+	tests := []struct {
+		name     string
+		spec     SilenceSpec
+		at       time.Time
+		expected bool
+	}{
+		{"no time limit", SilenceSpec{}, date(2023, 6, 1, 0, 0), true},
+		{"disabled", SilenceSpec{Enabled: &disabled}, date(2023, 6, 1, 0, 0), false},
+		{"before startsAt", SilenceSpec{StartsAt: &metav1.Time{Time: date(2023, 6, 1, 1, 0)}}, date(2023, 6, 1, 0, 0), false},
+		{"in duration", SilenceSpec{StartsAt: &metav1.Time{Time: date(2023, 6, 1, 0, 0)}, Duration: hour}, date(2023, 6, 1, 0, 59), true},
+		{"after duration", SilenceSpec{StartsAt: &metav1.Time{Time: date(2023, 6, 1, 0, 0)}, Duration: hour}, date(2023, 6, 1, 1, 0), false},
+		{"endsAt takes precedence", SilenceSpec{Schedule: "0 0 * * *", EndsAt: &metav1.Time{Time: date(2023, 6, 1, 0, 0)}}, date(2023, 6, 1, 0, 0), false},
+
+		// 09:00 in Shanghai is 01:00 UTC.
+		{"schedule in time zone", SilenceSpec{Schedule: "0 9 * * *", Duration: hour, TimeZone: "Asia/Shanghai"}, date(2023, 6, 1, 1, 30), true},
+		{"schedule out of duration", SilenceSpec{Schedule: "0 9 * * *", Duration: hour, TimeZone: "Asia/Shanghai"}, date(2023, 6, 1, 2, 30), false},
+		{"schedule without duration", SilenceSpec{Schedule: "0 9 * * *", TimeZone: "Asia/Shanghai"}, date(2023, 6, 1, 12, 0), true},
+		{"schedule ignores startsAt", SilenceSpec{Schedule: "0 9 * * *", Duration: hour, TimeZone: "Asia/Shanghai", StartsAt: &metav1.Time{Time: date(2023, 7, 1, 0, 0)}}, date(2023, 6, 1, 1, 30), true},
+
+		// The clocks in New York spring forward from 02:00 EST to 03:00 EDT on 2023-03-12, the duration is the elapsed time,
+		// the silence scheduled at 01:00 EST (06:00 UTC) for 3 hours ends at 05:00 EDT (09:00 UTC).
+		{"spring forward before schedule", SilenceSpec{Schedule: "0 1 * * *", Duration: threeHours, TimeZone: "America/New_York"}, date(2023, 3, 12, 5, 59), false},
+		{"spring forward in duration", SilenceSpec{Schedule: "0 1 * * *", Duration: threeHours, TimeZone: "America/New_York"}, date(2023, 3, 12, 8, 59), true},
+		{"spring forward after duration", SilenceSpec{Schedule: "0 1 * * *", Duration: threeHours, TimeZone: "America/New_York"}, date(2023, 3, 12, 9, 0), false},
+		// The silence scheduled at 09:00 follows the time zone, 09:00 EDT is 13:00 UTC after the change.
+		{"spring forward follows time zone", SilenceSpec{Schedule: "0 9 * * *", Duration: hour, TimeZone: "America/New_York"}, date(2023, 3, 12, 13, 30), true},
+		{"spring forward old offset", SilenceSpec{Schedule: "0 9 * * *", Duration: hour, TimeZone: "America/New_York"}, date(2023, 3, 12, 14, 30), false},
+
+		// The clocks in New York fall back from 02:00 EDT to 01:00 EST on 2023-11-05,
+		// the silence scheduled at 09:00 EST is active from 14:00 UTC after the change.
+		{"fall back follows time zone", SilenceSpec{Schedule: "0 9 * * *", Duration: hour, TimeZone: "America/New_York"}, date(2023, 11, 5, 14, 30), true},
+		{"fall back old offset", SilenceSpec{Schedule: "0 9 * * *", Duration: hour, TimeZone: "America/New_York"}, date(2023, 11, 5, 13, 30), false},
+		// The silence scheduled at 00:00 EDT (04:00 UTC) for 3 hours ends at 02:00 EST (07:00 UTC).
+		{"fall back in duration", SilenceSpec{Schedule: "0 0 * * *", Duration: threeHours, TimeZone: "America/New_York"}, date(2023, 11, 5, 6, 59), true},
+		{"fall back after duration", SilenceSpec{Schedule: "0 0 * * *", Duration: threeHours, TimeZone: "America/New_York"}, date(2023, 11, 5, 7, 0), false},
+
+		// The time windows crossing midnight, 22:00 to 06:00 in Shanghai is 14:00 to 22:00 UTC.
+		{"window before midnight", SilenceSpec{TimeZone: "Asia/Shanghai", TimeWindows: []TimePeriod{{Times: []TimeRange{{"22:00", "06:00"}}}}}, date(2023, 6, 1, 15, 0), true},
+		{"window after midnight", SilenceSpec{TimeZone: "Asia/Shanghai", TimeWindows: []TimePeriod{{Times: []TimeRange{{"22:00", "06:00"}}}}}, date(2023, 6, 1, 21, 59), true},
+		{"out of window", SilenceSpec{TimeZone: "Asia/Shanghai", TimeWindows: []TimePeriod{{Times: []TimeRange{{"22:00", "06:00"}}}}}, date(2023, 6, 1, 22, 0), false},
+		// Friday 23:00 to Saturday 06:00 in Shanghai, the weekday is matched against the day on which the window starts.
+		{"window crossing midnight weekday", SilenceSpec{TimeZone: "Asia/Shanghai", TimeWindows: []TimePeriod{{Times: []TimeRange{{"23:00", "06:00"}}, Weekdays: []string{"friday"}}}}, date(2023, 6, 2, 20, 0), true},
+		{"window crossing midnight other weekday", SilenceSpec{TimeZone: "Asia/Shanghai", TimeWindows: []TimePeriod{{Times: []TimeRange{{"23:00", "06:00"}}, Weekdays: []string{"friday"}}}}, date(2023, 6, 3, 20, 0), false},
+		// 09:00 EDT is 13:00 UTC, 09:00 EST is 14:00 UTC.
+		{"window after spring forward", SilenceSpec{TimeZone: "America/New_York", TimeWindows: []TimePeriod{{Times: []TimeRange{{"09:00", "17:00"}}}}}, date(2023, 3, 13, 13, 0), true},
+		{"window before spring forward", SilenceSpec{TimeZone: "America/New_York", TimeWindows: []TimePeriod{{Times: []TimeRange{{"09:00", "17:00"}}}}}, date(2023, 3, 10, 13, 0), false},
+		{"window location", SilenceSpec{TimeZone: "America/New_York", TimeWindows: []TimePeriod{{Times: []TimeRange{{"09:00", "17:00"}}, Location: "UTC"}}}, date(2023, 6, 1, 9, 0), true},
+		{"window before startsAt", SilenceSpec{StartsAt: &metav1.Time{Time: date(2023, 6, 2, 0, 0)}, TimeWindows: []TimePeriod{{Location: "UTC"}}}, date(2023, 6, 1, 0, 0), false},
+
+		// Both the schedule and the time windows must match, the first half hour of every hour from 09:00 to 17:00 UTC.
+		{"schedule and window", SilenceSpec{Schedule: "0 * * * *", Duration: halfHour, TimeZone: "UTC", TimeWindows: []TimePeriod{{Times: []TimeRange{{"09:00", "17:00"}}}}}, date(2023, 6, 1, 10, 15), true},
+		{"window without schedule", SilenceSpec{Schedule: "0 * * * *", Duration: halfHour, TimeZone: "UTC", TimeWindows: []TimePeriod{{Times: []TimeRange{{"09:00", "17:00"}}}}}, date(2023, 6, 1, 10, 45), false},
+		{"schedule without window", SilenceSpec{Schedule: "0 * * * *", Duration: halfHour, TimeZone: "UTC", TimeWindows: []TimePeriod{{Times: []TimeRange{{"09:00", "17:00"}}}}}, date(2023, 6, 1, 20, 15), false},
+		{"schedule without duration and window", SilenceSpec{Schedule: "0 * * * *", TimeZone: "UTC", TimeWindows: []TimePeriod{{Times: []TimeRange{{"09:00", "17:00"}}}}}, date(2023, 6, 1, 20, 15), false},
+		{"invalid time zone", SilenceSpec{Schedule: "0 * * * *", Duration: halfHour, TimeZone: "Invalid/Zone"}, date(2023, 6, 1, 10, 15), false},
+	}
+
+	for _, test := range tests {
+		s := &Silence{Spec: test.spec}
+		if ok := s.IsActiveAt(test.at); ok != test.expected {
+			t.Fatalf("%s: expected %v, got %v", test.name, test.expected, ok)
+		}
+	}
+}
+
+func TestSilenceLocalTimeZone(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local := time.Local
+	time.Local = shanghai
+	defer func() {
+		time.Local = local
+	}()
+
+	// The schedule and the time windows are evaluated in the local time zone if the time zone is not set.
+	hour := &metav1.Duration{Duration: time.Hour}
+	scheduled := &Silence{Spec: SilenceSpec{Schedule: "0 9 * * *", Duration: hour}}
+	if !scheduled.IsActiveAt(date(2023, 6, 1, 1, 30)) || scheduled.IsActiveAt(date(2023, 6, 1, 9, 30)) {
+		t.Fatal("expected the schedule to be evaluated in the local time zone")
+	}
+
+	windowed := &Silence{Spec: SilenceSpec{TimeWindows: []TimePeriod{{Times: []TimeRange{{"09:00", "10:00"}}}}}}
+	if !windowed.IsActiveAt(date(2023, 6, 1, 1, 30)) || windowed.IsActiveAt(date(2023, 6, 1, 9, 30)) {
+		t.Fatal("expected the time windows to be evaluated in the local time zone")
+	}
+}
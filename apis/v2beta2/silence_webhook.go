@@ -17,6 +17,8 @@ limitations under the License.
 package v2beta2
 
 import (
+	"regexp"
+
 	"github.com/robfig/cron/v3"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -65,11 +67,39 @@ func (s *Silence) validateSilence() (warnings admission.Warnings, err error) {
 		}
 	}
 
+	if _, err := s.Location(); err != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "timeZone"), s.Spec.TimeZone, err.Error()))
+	}
+
+	for index, window := range s.Spec.TimeWindows {
+		if err := window.Validate(); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "timeWindows").Index(index), window, err.Error()))
+		}
+	}
+
+	if rs := s.Spec.Receivers; rs != nil {
+		if rs.RegexName != "" {
+			if _, err := regexp.Compile(rs.RegexName); err != nil {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "receivers", "regexName"), rs.RegexName, err.Error()))
+			}
+		}
+
+		if rs.Selector != nil {
+			if err := validateSelector(rs.Selector); err != nil {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "receivers", "selector"), rs.Selector, err.Error()))
+			}
+		}
+	}
+
+	if s.Spec.EndsAt != nil && s.Spec.StartsAt != nil && !s.Spec.EndsAt.After(s.Spec.StartsAt.Time) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "endsAt"), s.Spec.EndsAt, "endsAt must be after startsAt"))
+	}
+
 	if allErrs == nil || len(allErrs) == 0 {
 		return admission.Warnings{}, nil
 	}
 
 	return admission.Warnings{}, errors.NewInvalid(
-		schema.GroupKind{Group: "notification.kubesphere.io", Kind: "Receiver"},
+		schema.GroupKind{Group: "notification.kubesphere.io", Kind: "Silence"},
 		s.Name, allErrs)
 }
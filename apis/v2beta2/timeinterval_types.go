@@ -0,0 +1,433 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	// Embed the time zone database, so that the time zones can be loaded in the images without it.
+	_ "time/tzdata"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	weekdays = map[string]int{
+		"sunday":    0,
+		"monday":    1,
+		"tuesday":   2,
+		"wednesday": 3,
+		"thursday":  4,
+		"friday":    5,
+		"saturday":  6,
+	}
+
+	months = map[string]int{
+		"january":   1,
+		"february":  2,
+		"march":     3,
+		"april":     4,
+		"may":       5,
+		"june":      6,
+		"july":      7,
+		"august":    8,
+		"september": 9,
+		"october":   10,
+		"november":  11,
+		"december":  12,
+	}
+)
+
+// TimeRange is a time of day range, the start time is inclusive and the end time is exclusive.
+// If the end time is before the start time, the time range crosses midnight and ends on the next day.
+type TimeRange struct {
+	// The start time in 24 hour format, such as `09:00`.
+	StartTime string `json:"startTime"`
+	// The end time in 24 hour format, such as `17:00`, `24:00` means the end of the day.
+	EndTime string `json:"endTime"`
+}
+
+// TimePeriod defines a recurring period of time, like the time_intervals of Alertmanager.
+// A time matches the period if it matches all the fields which are set, an empty period matches any time.
+type TimePeriod struct {
+	// The time of day ranges.
+	Times []TimeRange `json:"times,omitempty"`
+	// The days of the week, such as `monday` or `monday:friday`.
+	Weekdays []string `json:"weekdays,omitempty"`
+	// The days of the month, such as `1`, `1:5` or `-3:-1`, negative values count from the end of the month.
+	DaysOfMonth []string `json:"daysOfMonth,omitempty"`
+	// The months of the year, such as `january`, `1` or `january:march`.
+	Months []string `json:"months,omitempty"`
+	// The years, such as `2023` or `2023:2025`.
+	Years []string `json:"years,omitempty"`
+	// The time zone in which the period is evaluated, such as `Asia/Shanghai`, default is `UTC`.
+	Location string `json:"location,omitempty"`
+}
+
+// TimeIntervals selects the time periods defined in the TimeInterval resources or inline.
+type TimeIntervals struct {
+	// The names of the TimeInterval resources.
+	Names []string `json:"names,omitempty"`
+	// The inline time periods.
+	Periods []TimePeriod `json:"periods,omitempty"`
+}
+
+// TimeIntervalSpec defines the desired state of TimeInterval
+type TimeIntervalSpec struct {
+	// A time matches the TimeInterval if it matches any of the time periods.
+	//
+	// +kubebuilder:validation:MinItems=1
+	TimePeriods []TimePeriod `json:"timePeriods"`
+}
+
+// TimeIntervalStatus defines the observed state of TimeInterval
+type TimeIntervalStatus struct {
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories=notification-manager
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+
+// TimeInterval is the Schema for the TimeInterval API
+type TimeInterval struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TimeIntervalSpec   `json:"spec,omitempty"`
+	Status TimeIntervalStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TimeIntervalList contains a list of TimeInterval
+type TimeIntervalList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TimeInterval `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TimeInterval{}, &TimeIntervalList{})
+}
+
+// Contains returns true if the time matches any of the time periods.
+func (t *TimeInterval) Contains(at time.Time) (bool, error) {
+	for _, p := range t.Spec.TimePeriods {
+		ok, err := p.Contains(at)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Contains returns true if the time matches the time period.
+func (p *TimePeriod) Contains(at time.Time) (bool, error) {
+
+	loc := time.UTC
+	if p.Location != "" {
+		var err error
+		if loc, err = time.LoadLocation(p.Location); err != nil {
+			return false, err
+		}
+	}
+	at = at.In(loc)
+
+	if len(p.Times) == 0 {
+		return p.containsDay(at)
+	}
+
+	minutes := at.Hour()*60 + at.Minute()
+	for _, tr := range p.Times {
+		start, end, err := tr.parse()
+		if err != nil {
+			return false, err
+		}
+
+		// The day on which the time range starts.
+		day := at
+		switch {
+		case start < end && minutes >= start && minutes < end:
+		case start > end && minutes >= start:
+		case start > end && minutes < end:
+			// The time range crossing midnight starts on the previous day.
+			// Noon is used so that the date is not affected by the daylight saving time changes.
+			day = time.Date(at.Year(), at.Month(), at.Day()-1, 12, 0, 0, 0, loc)
+		default:
+			continue
+		}
+
+		if ok, err := p.containsDay(day); err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
+}
+
+// containsDay returns true if the day matches the weekdays, days of month, months and years of the time period.
+func (p *TimePeriod) containsDay(day time.Time) (bool, error) {
+
+	if ok, err := matchRanges(p.Weekdays, int(day.Weekday()), parseWeekday); err != nil || !ok {
+		return false, err
+	}
+
+	if len(p.DaysOfMonth) > 0 {
+		// The number of days in the month.
+		days := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+		ok, err := matchDaysOfMonth(p.DaysOfMonth, day.Day(), days)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	if ok, err := matchRanges(p.Months, int(day.Month()), parseMonth); err != nil || !ok {
+		return false, err
+	}
+
+	if ok, err := matchRanges(p.Years, day.Year(), parseYear); err != nil || !ok {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Validate checks whether the time period can be parsed.
+func (p *TimePeriod) Validate() error {
+
+	if p.Location != "" {
+		if _, err := time.LoadLocation(p.Location); err != nil {
+			return err
+		}
+	}
+
+	for _, tr := range p.Times {
+		if _, _, err := tr.parse(); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range []struct {
+		values []string
+		parse  func(string) (int, error)
+	}{
+		{p.Weekdays, parseWeekday},
+		{p.Months, parseMonth},
+		{p.Years, parseYear},
+	} {
+		if _, err := matchRanges(r.values, 0, r.parse); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range p.DaysOfMonth {
+		if _, _, err := parseDayOfMonthRange(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parse returns the minutes of the day of the start time and the end time.
+func (tr *TimeRange) parse() (int, int, error) {
+	start, err := parseClock(tr.StartTime)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	end, err := parseClock(tr.EndTime)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if start == end {
+		return 0, 0, fmt.Errorf("start time %s must not be equal to end time %s", tr.StartTime, tr.EndTime)
+	}
+
+	return start, end, nil
+}
+
+func parseClock(s string) (int, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid time %s, must be in the format HH:MM", s)
+	}
+
+	h, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %s, must be in the format HH:MM", s)
+	}
+	m, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %s, must be in the format HH:MM", s)
+	}
+
+	if h < 0 || h > 24 || m < 0 || m > 59 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time %s, must be between 00:00 and 24:00", s)
+	}
+
+	return h*60 + m, nil
+}
+
+// matchRanges returns true if the value is in any of the ranges, or the ranges are empty.
+// A range is a single value or two values separated by a colon, the bounds are inclusive.
+func matchRanges(ranges []string, value int, parse func(string) (int, error)) (bool, error) {
+
+	if len(ranges) == 0 {
+		return true, nil
+	}
+
+	matched := false
+	for _, r := range ranges {
+		bounds := strings.SplitN(r, ":", 2)
+		start, err := parse(bounds[0])
+		if err != nil {
+			return false, err
+		}
+
+		end := start
+		if len(bounds) == 2 {
+			if end, err = parse(bounds[1]); err != nil {
+				return false, err
+			}
+		}
+
+		if start > end {
+			return false, fmt.Errorf("invalid range %s, the start must not be after the end", r)
+		}
+
+		if value >= start && value <= end {
+			matched = true
+		}
+	}
+
+	return matched, nil
+}
+
+func parseWeekday(s string) (int, error) {
+	if v, ok := weekdays[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return v, nil
+	}
+
+	return 0, fmt.Errorf("invalid weekday %s", s)
+}
+
+func parseMonth(s string) (int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if v, ok := months[s]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 1 || v > 12 {
+		return 0, fmt.Errorf("invalid month %s", s)
+	}
+
+	return v, nil
+}
+
+func parseDayOfMonth(s string) (int, error) {
+	v, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || v == 0 || v < -31 || v > 31 {
+		return 0, fmt.Errorf("invalid day of month %s", s)
+	}
+
+	return v, nil
+}
+
+// parseDayOfMonthRange returns the bounds of the range of the days of month, a negative bound counts from the end of the month.
+// The bounds with the same sign must be in order, the bounds with different signs are checked against the month.
+func parseDayOfMonthRange(r string) (int, int, error) {
+	bounds := strings.SplitN(r, ":", 2)
+	start, err := parseDayOfMonth(bounds[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	end := start
+	if len(bounds) == 2 {
+		if end, err = parseDayOfMonth(bounds[1]); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if (start > 0) == (end > 0) && start > end {
+		return 0, 0, fmt.Errorf("invalid range %s, the start must not be after the end", r)
+	}
+
+	return start, end, nil
+}
+
+// matchDaysOfMonth returns true if the day is in any of the ranges of the days of month, the same as Alertmanager.
+// The range which starts after the end of the month is skipped, and the bounds are clamped to the month,
+// so that a range such as `25:31` does not cross into the next month.
+func matchDaysOfMonth(ranges []string, day, days int) (bool, error) {
+
+	matched := false
+	for _, r := range ranges {
+		start, end, err := parseDayOfMonthRange(r)
+		if err != nil {
+			return false, err
+		}
+
+		if start < 0 {
+			start = days + start + 1
+		}
+		if end < 0 {
+			end = days + end + 1
+		}
+
+		if start > days {
+			continue
+		}
+
+		start = clamp(start, -days, days)
+		end = clamp(end, -days, days)
+		if day >= start && day <= end {
+			matched = true
+		}
+	}
+
+	return matched, nil
+}
+
+func clamp(n, min, max int) int {
+	if n < min {
+		return min
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
+
+func parseYear(s string) (int, error) {
+	v, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid year %s", s)
+	}
+
+	return v, nil
+}
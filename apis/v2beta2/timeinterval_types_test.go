@@ -0,0 +1,107 @@
+package v2beta2
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestTimePeriodContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		period   TimePeriod
+		at       time.Time
+		expected bool
+	}{
+		{"empty period", TimePeriod{}, date(2023, 6, 1, 0, 0), true},
+		{"in time range", TimePeriod{Times: []TimeRange{{"09:00", "17:00"}}}, date(2023, 6, 1, 9, 0), true},
+		{"end time is exclusive", TimePeriod{Times: []TimeRange{{"09:00", "17:00"}}}, date(2023, 6, 1, 17, 0), false},
+		{"end of day", TimePeriod{Times: []TimeRange{{"22:00", "24:00"}}}, date(2023, 6, 1, 23, 59), true},
+		// Thursday 23:00 to Friday 06:00, the weekday is matched against the day on which the time range starts.
+		{"cross midnight before midnight", TimePeriod{Times: []TimeRange{{"23:00", "06:00"}}, Weekdays: []string{"thursday"}}, date(2023, 6, 1, 23, 30), true},
+		{"cross midnight after midnight", TimePeriod{Times: []TimeRange{{"23:00", "06:00"}}, Weekdays: []string{"thursday"}}, date(2023, 6, 2, 5, 59), true},
+		{"cross midnight next range", TimePeriod{Times: []TimeRange{{"23:00", "06:00"}}, Weekdays: []string{"thursday"}}, date(2023, 6, 3, 1, 0), false},
+		{"weekday range", TimePeriod{Weekdays: []string{"monday:friday"}}, date(2023, 6, 3, 12, 0), false},
+		{"month name", TimePeriod{Months: []string{"june"}}, date(2023, 6, 3, 12, 0), true},
+		{"month range", TimePeriod{Months: []string{"1:3"}}, date(2023, 6, 3, 12, 0), false},
+		{"year range", TimePeriod{Years: []string{"2022:2024"}}, date(2023, 6, 3, 12, 0), true},
+		// 2023-06-01 01:00 UTC is 2023-06-01 09:00 in Shanghai.
+		{"location", TimePeriod{Times: []TimeRange{{"09:00", "10:00"}}, Location: "Asia/Shanghai"}, date(2023, 6, 1, 1, 0), true},
+	}
+
+	for _, test := range tests {
+		ok, err := test.period.Contains(test.at)
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		if ok != test.expected {
+			t.Fatalf("%s: expected %v, got %v", test.name, test.expected, ok)
+		}
+	}
+}
+
+func TestDaysOfMonth(t *testing.T) {
+	tests := []struct {
+		ranges   []string
+		at       time.Time
+		expected bool
+	}{
+		{[]string{"1:5"}, date(2023, 6, 5, 0, 0), true},
+		{[]string{"1:5"}, date(2023, 6, 6, 0, 0), false},
+		{[]string{"-1"}, date(2023, 2, 28, 0, 0), true},
+		{[]string{"-1"}, date(2024, 2, 28, 0, 0), false},
+		{[]string{"-3:-1"}, date(2023, 6, 28, 0, 0), true},
+		{[]string{"1:-1"}, date(2023, 2, 28, 0, 0), true},
+		// The range is clamped to the end of the month.
+		{[]string{"25:31"}, date(2023, 2, 28, 0, 0), true},
+		{[]string{"25:31"}, date(2023, 3, 1, 0, 0), false},
+		// The range which starts after the end of the month is skipped.
+		{[]string{"30:31"}, date(2023, 2, 28, 0, 0), false},
+		{[]string{"30:-1"}, date(2023, 2, 28, 0, 0), false},
+		{[]string{"30:31", "1"}, date(2023, 2, 1, 0, 0), true},
+		// -31 is before the first day of February, the range is clamped to the start of the month.
+		{[]string{"-31:5"}, date(2023, 2, 1, 0, 0), true},
+		{[]string{"1:-30"}, date(2023, 2, 1, 0, 0), false},
+	}
+
+	for _, test := range tests {
+		p := TimePeriod{DaysOfMonth: test.ranges}
+		if err := p.Validate(); err != nil {
+			t.Fatalf("%v: %s", test.ranges, err)
+		}
+
+		ok, err := p.Contains(test.at)
+		if err != nil {
+			t.Fatalf("%v at %s: %s", test.ranges, test.at, err)
+		}
+		if ok != test.expected {
+			t.Fatalf("%v at %s: expected %v, got %v", test.ranges, test.at, test.expected, ok)
+		}
+	}
+}
+
+func TestTimePeriodValidate(t *testing.T) {
+	invalid := []TimePeriod{
+		{Location: "Mars/Olympus"},
+		{Times: []TimeRange{{"09:00", "09:00"}}},
+		{Times: []TimeRange{{"9", "10:00"}}},
+		{Times: []TimeRange{{"24:01", "10:00"}}},
+		{Weekdays: []string{"someday"}},
+		{Weekdays: []string{"friday:monday"}},
+		{DaysOfMonth: []string{"0"}},
+		{DaysOfMonth: []string{"32"}},
+		{DaysOfMonth: []string{"5:1"}},
+		{DaysOfMonth: []string{"-1:-3"}},
+		{Months: []string{"13"}},
+		{Years: []string{"2025:2023"}},
+	}
+
+	for _, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Fatalf("expected %+v to be invalid", p)
+		}
+	}
+}
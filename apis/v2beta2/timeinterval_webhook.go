@@ -0,0 +1,82 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2beta2
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func (t *TimeInterval) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(t).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,mutating=false,failurePolicy=fail,groups=notification.kubesphere.io,resources=timeintervals,versions=v2beta2
+var _ webhook.Validator = &TimeInterval{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (t *TimeInterval) ValidateCreate() (warnings admission.Warnings, err error) {
+
+	return t.validateTimeInterval()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (t *TimeInterval) ValidateUpdate(_ runtime.Object) (warnings admission.Warnings, err error) {
+	return t.validateTimeInterval()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (t *TimeInterval) ValidateDelete() (warnings admission.Warnings, err error) {
+	return admission.Warnings{}, nil
+}
+
+func (t *TimeInterval) validateTimeInterval() (warnings admission.Warnings, err error) {
+	var allErrs field.ErrorList
+
+	if len(t.Spec.TimePeriods) == 0 {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec", "timePeriods"), "must have at least one time period"))
+	}
+
+	allErrs = append(allErrs, validateTimePeriods(field.NewPath("spec", "timePeriods"), t.Spec.TimePeriods)...)
+
+	if allErrs == nil || len(allErrs) == 0 {
+		return admission.Warnings{}, nil
+	}
+
+	return admission.Warnings{}, errors.NewInvalid(
+		schema.GroupKind{Group: "notification.kubesphere.io", Kind: "TimeInterval"},
+		t.Name, allErrs)
+}
+
+func validateTimePeriods(path *field.Path, periods []TimePeriod) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for index, period := range periods {
+		if err := period.Validate(); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Index(index), period, err.Error()))
+		}
+	}
+
+	return allErrs
+}
@@ -22,6 +22,7 @@ package v2beta2
 
 import (
 	"k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -147,7 +148,7 @@ func (in *Config) DeepCopyInto(out *Config) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Config.
@@ -263,6 +264,11 @@ func (in *ConfigSpec) DeepCopy() *ConfigSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigStatus) DeepCopyInto(out *ConfigStatus) {
 	*out = *in
+	if in.Receivers != nil {
+		in, out := &in.Receivers, &out.Receivers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigStatus.
@@ -440,6 +446,11 @@ func (in *DingTalkOptions) DeepCopyInto(out *DingTalkOptions) {
 		*out = new(Throttle)
 		**out = **in
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DingTalkOptions.
@@ -470,6 +481,11 @@ func (in *DingTalkReceiver) DeepCopyInto(out *DingTalkReceiver) {
 		*out = new(LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GroupLabels != nil {
+		in, out := &in.GroupLabels, &out.GroupLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ChatBot != nil {
 		in, out := &in.ChatBot, &out.ChatBot
 		*out = new(DingTalkChatBot)
@@ -520,6 +536,11 @@ func (in *DiscordOptions) DeepCopyInto(out *DiscordOptions) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscordOptions.
@@ -575,6 +596,11 @@ func (in *DiscordReceiver) DeepCopyInto(out *DiscordReceiver) {
 		*out = new(LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GroupLabels != nil {
+		in, out := &in.GroupLabels, &out.GroupLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscordReceiver.
@@ -653,6 +679,11 @@ func (in *EmailOptions) DeepCopyInto(out *EmailOptions) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailOptions.
@@ -688,6 +719,11 @@ func (in *EmailReceiver) DeepCopyInto(out *EmailReceiver) {
 		*out = new(LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GroupLabels != nil {
+		in, out := &in.GroupLabels, &out.GroupLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Template != nil {
 		in, out := &in.Template, &out.Template
 		*out = new(string)
@@ -720,6 +756,154 @@ func (in *EmailReceiver) DeepCopy() *EmailReceiver {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Enrichment) DeepCopyInto(out *Enrichment) {
+	*out = *in
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(MetadataEnrichment)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(MetadataEnrichment)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Workload != nil {
+		in, out := &in.Workload, &out.Workload
+		*out = new(MetadataEnrichment)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Enrichment.
+func (in *Enrichment) DeepCopy() *Enrichment {
+	if in == nil {
+		return nil
+	}
+	out := new(Enrichment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EscalationPolicy) DeepCopyInto(out *EscalationPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EscalationPolicy.
+func (in *EscalationPolicy) DeepCopy() *EscalationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(EscalationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EscalationPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EscalationPolicyList) DeepCopyInto(out *EscalationPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EscalationPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EscalationPolicyList.
+func (in *EscalationPolicyList) DeepCopy() *EscalationPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(EscalationPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EscalationPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EscalationPolicySpec) DeepCopyInto(out *EscalationPolicySpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]EscalationStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EscalationPolicySpec.
+func (in *EscalationPolicySpec) DeepCopy() *EscalationPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EscalationPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EscalationPolicyStatus) DeepCopyInto(out *EscalationPolicyStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EscalationPolicyStatus.
+func (in *EscalationPolicyStatus) DeepCopy() *EscalationPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EscalationPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EscalationStep) DeepCopyInto(out *EscalationStep) {
+	*out = *in
+	out.Delay = in.Delay
+	in.Receivers.DeepCopyInto(&out.Receivers)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EscalationStep.
+func (in *EscalationStep) DeepCopy() *EscalationStep {
+	if in == nil {
+		return nil
+	}
+	out := new(EscalationStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FeishuChatBot) DeepCopyInto(out *FeishuChatBot) {
 	*out = *in
@@ -783,6 +967,11 @@ func (in *FeishuOptions) DeepCopyInto(out *FeishuOptions) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeishuOptions.
@@ -813,6 +1002,11 @@ func (in *FeishuReceiver) DeepCopyInto(out *FeishuReceiver) {
 		*out = new(LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GroupLabels != nil {
+		in, out := &in.GroupLabels, &out.GroupLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.User != nil {
 		in, out := &in.User, &out.User
 		*out = make([]string, len(*in))
@@ -863,6 +1057,11 @@ func (in *GlobalOptions) DeepCopyInto(out *GlobalOptions) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalOptions.
@@ -965,6 +1164,115 @@ func (in *HuaweiSMS) DeepCopy() *HuaweiSMS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Inhibitor) DeepCopyInto(out *Inhibitor) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Inhibitor.
+func (in *Inhibitor) DeepCopy() *Inhibitor {
+	if in == nil {
+		return nil
+	}
+	out := new(Inhibitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Inhibitor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InhibitorList) DeepCopyInto(out *InhibitorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Inhibitor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InhibitorList.
+func (in *InhibitorList) DeepCopy() *InhibitorList {
+	if in == nil {
+		return nil
+	}
+	out := new(InhibitorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InhibitorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InhibitorSpec) DeepCopyInto(out *InhibitorSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Equal != nil {
+		in, out := &in.Equal, &out.Equal
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InhibitorSpec.
+func (in *InhibitorSpec) DeepCopy() *InhibitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InhibitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InhibitorStatus) DeepCopyInto(out *InhibitorStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InhibitorStatus.
+func (in *InhibitorStatus) DeepCopy() *InhibitorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InhibitorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LabelSelector) DeepCopyInto(out *LabelSelector) {
 	*out = *in
@@ -1014,13 +1322,38 @@ func (in *LabelSelectorRequirement) DeepCopy() *LabelSelectorRequirement {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataEnrichment) DeepCopyInto(out *MetadataEnrichment) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataEnrichment.
+func (in *MetadataEnrichment) DeepCopy() *MetadataEnrichment {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataEnrichment)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NotificationManager) DeepCopyInto(out *NotificationManager) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationManager.
@@ -1173,7 +1506,34 @@ func (in *NotificationManagerSpec) DeepCopyInto(out *NotificationManagerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.GroupWait != nil {
+		in, out := &in.GroupWait, &out.GroupWait
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.GroupInterval != nil {
+		in, out := &in.GroupInterval, &out.GroupInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	out.BatchMaxWait = in.BatchMaxWait
+	if in.RepeatInterval != nil {
+		in, out := &in.RepeatInterval, &out.RepeatInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RelabelConfigs != nil {
+		in, out := &in.RelabelConfigs, &out.RelabelConfigs
+		*out = make([]RelabelConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Enrichment != nil {
+		in, out := &in.Enrichment, &out.Enrichment
+		*out = new(Enrichment)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Template != nil {
 		in, out := &in.Template, &out.Template
 		*out = new(Template)
@@ -1208,6 +1568,14 @@ func (in *NotificationManagerSpec) DeepCopy() *NotificationManagerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NotificationManagerStatus) DeepCopyInto(out *NotificationManagerStatus) {
 	*out = *in
+	if in.LastSuccessTime != nil {
+		in, out := &in.LastSuccessTime, &out.LastSuccessTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastErrorTime != nil {
+		in, out := &in.LastErrorTime, &out.LastErrorTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationManagerStatus.
@@ -1220,6 +1588,170 @@ func (in *NotificationManagerStatus) DeepCopy() *NotificationManagerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnCallLayer) DeepCopyInto(out *OnCallLayer) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = (*in).DeepCopy()
+	}
+	out.Rotation = in.Rotation
+	if in.Participants != nil {
+		in, out := &in.Participants, &out.Participants
+		*out = make([]ReceiverSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnCallLayer.
+func (in *OnCallLayer) DeepCopy() *OnCallLayer {
+	if in == nil {
+		return nil
+	}
+	out := new(OnCallLayer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnCallOverride) DeepCopyInto(out *OnCallOverride) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+	in.Receivers.DeepCopyInto(&out.Receivers)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnCallOverride.
+func (in *OnCallOverride) DeepCopy() *OnCallOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(OnCallOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnCallRotation) DeepCopyInto(out *OnCallRotation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnCallRotation.
+func (in *OnCallRotation) DeepCopy() *OnCallRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(OnCallRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnCallSchedule) DeepCopyInto(out *OnCallSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnCallSchedule.
+func (in *OnCallSchedule) DeepCopy() *OnCallSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(OnCallSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OnCallSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnCallScheduleList) DeepCopyInto(out *OnCallScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OnCallSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnCallScheduleList.
+func (in *OnCallScheduleList) DeepCopy() *OnCallScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(OnCallScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OnCallScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnCallScheduleSpec) DeepCopyInto(out *OnCallScheduleSpec) {
+	*out = *in
+	if in.Layers != nil {
+		in, out := &in.Layers, &out.Layers
+		*out = make([]OnCallLayer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]OnCallOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnCallScheduleSpec.
+func (in *OnCallScheduleSpec) DeepCopy() *OnCallScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OnCallScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnCallScheduleStatus) DeepCopyInto(out *OnCallScheduleStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnCallScheduleStatus.
+func (in *OnCallScheduleStatus) DeepCopy() *OnCallScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OnCallScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Options) DeepCopyInto(out *Options) {
 	*out = *in
@@ -1360,6 +1892,11 @@ func (in *PushoverOptions) DeepCopyInto(out *PushoverOptions) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushoverOptions.
@@ -1390,6 +1927,11 @@ func (in *PushoverReceiver) DeepCopyInto(out *PushoverReceiver) {
 		*out = new(LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GroupLabels != nil {
+		in, out := &in.GroupLabels, &out.GroupLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Template != nil {
 		in, out := &in.Template, &out.Template
 		*out = new(string)
@@ -1469,7 +2011,7 @@ func (in *Receiver) DeepCopyInto(out *Receiver) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Receiver.
@@ -1622,6 +2164,14 @@ func (in *ReceiverSpec) DeepCopy() *ReceiverSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReceiverStatus) DeepCopyInto(out *ReceiverStatus) {
 	*out = *in
+	if in.LastSuccessTime != nil {
+		in, out := &in.LastSuccessTime, &out.LastSuccessTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastErrorTime != nil {
+		in, out := &in.LastErrorTime, &out.LastErrorTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReceiverStatus.
@@ -1664,13 +2214,120 @@ func (in *ReceiversSpec) DeepCopy() *ReceiversSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RelabelConfig) DeepCopyInto(out *RelabelConfig) {
+	*out = *in
+	if in.SourceLabels != nil {
+		in, out := &in.SourceLabels, &out.SourceLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Separator != nil {
+		in, out := &in.Separator, &out.Separator
+		*out = new(string)
+		**out = **in
+	}
+	if in.Replacement != nil {
+		in, out := &in.Replacement, &out.Replacement
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RelabelConfig.
+func (in *RelabelConfig) DeepCopy() *RelabelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RelabelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.InitialBackoff != nil {
+		in, out := &in.InitialBackoff, &out.InitialBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Route) DeepCopyInto(out *Route) {
+	*out = *in
+	if in.AlertSelector != nil {
+		in, out := &in.AlertSelector, &out.AlertSelector
+		*out = new(LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Receivers != nil {
+		in, out := &in.Receivers, &out.Receivers
+		*out = new(ReceiverSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GroupLabels != nil {
+		in, out := &in.GroupLabels, &out.GroupLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GroupWait != nil {
+		in, out := &in.GroupWait, &out.GroupWait
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.GroupInterval != nil {
+		in, out := &in.GroupInterval, &out.GroupInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Continue != nil {
+		in, out := &in.Continue, &out.Continue
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]apiextensionsv1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Route.
+func (in *Route) DeepCopy() *Route {
+	if in == nil {
+		return nil
+	}
+	out := new(Route)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Router) DeepCopyInto(out *Router) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Router.
@@ -1737,6 +2394,43 @@ func (in *RouterSpec) DeepCopyInto(out *RouterSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.Receivers.DeepCopyInto(&out.Receivers)
+	if in.GroupLabels != nil {
+		in, out := &in.GroupLabels, &out.GroupLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GroupWait != nil {
+		in, out := &in.GroupWait, &out.GroupWait
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.GroupInterval != nil {
+		in, out := &in.GroupInterval, &out.GroupInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ActiveTimeIntervals != nil {
+		in, out := &in.ActiveTimeIntervals, &out.ActiveTimeIntervals
+		*out = new(TimeIntervals)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InactiveTimeIntervals != nil {
+		in, out := &in.InactiveTimeIntervals, &out.InactiveTimeIntervals
+		*out = new(TimeIntervals)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Continue != nil {
+		in, out := &in.Continue, &out.Continue
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]apiextensionsv1.JSON, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouterSpec.
@@ -1752,6 +2446,10 @@ func (in *RouterSpec) DeepCopy() *RouterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouterStatus) DeepCopyInto(out *RouterStatus) {
 	*out = *in
+	if in.LastMatchTime != nil {
+		in, out := &in.LastMatchTime, &out.LastMatchTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouterStatus.
@@ -1835,7 +2533,7 @@ func (in *Silence) DeepCopyInto(out *Silence) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Silence.
@@ -1888,6 +2586,36 @@ func (in *SilenceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SilenceReceiverSelector) DeepCopyInto(out *SilenceReceiverSelector) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SilenceReceiverSelector.
+func (in *SilenceReceiverSelector) DeepCopy() *SilenceReceiverSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(SilenceReceiverSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SilenceSpec) DeepCopyInto(out *SilenceSpec) {
 	*out = *in
@@ -1910,6 +2638,22 @@ func (in *SilenceSpec) DeepCopyInto(out *SilenceSpec) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.TimeWindows != nil {
+		in, out := &in.TimeWindows, &out.TimeWindows
+		*out = make([]TimePeriod, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EndsAt != nil {
+		in, out := &in.EndsAt, &out.EndsAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Receivers != nil {
+		in, out := &in.Receivers, &out.Receivers
+		*out = new(SilenceReceiverSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SilenceSpec.
@@ -1925,6 +2669,10 @@ func (in *SilenceSpec) DeepCopy() *SilenceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SilenceStatus) DeepCopyInto(out *SilenceStatus) {
 	*out = *in
+	if in.LastSuppressedTime != nil {
+		in, out := &in.LastSuppressedTime, &out.LastSuppressedTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SilenceStatus.
@@ -1972,6 +2720,11 @@ func (in *SlackOptions) DeepCopyInto(out *SlackOptions) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlackOptions.
@@ -2002,6 +2755,11 @@ func (in *SlackReceiver) DeepCopyInto(out *SlackReceiver) {
 		*out = new(LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GroupLabels != nil {
+		in, out := &in.GroupLabels, &out.GroupLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Channels != nil {
 		in, out := &in.Channels, &out.Channels
 		*out = make([]string, len(*in))
@@ -2057,6 +2815,11 @@ func (in *SmsOptions) DeepCopyInto(out *SmsOptions) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SmsOptions.
@@ -2087,6 +2850,11 @@ func (in *SmsReceiver) DeepCopyInto(out *SmsReceiver) {
 		*out = new(LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GroupLabels != nil {
+		in, out := &in.GroupLabels, &out.GroupLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.PhoneNumbers != nil {
 		in, out := &in.PhoneNumbers, &out.PhoneNumbers
 		*out = make([]string, len(*in))
@@ -2174,6 +2942,11 @@ func (in *TelegramOptions) DeepCopyInto(out *TelegramOptions) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TelegramOptions.
@@ -2204,6 +2977,11 @@ func (in *TelegramReceiver) DeepCopyInto(out *TelegramReceiver) {
 		*out = new(LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GroupLabels != nil {
+		in, out := &in.GroupLabels, &out.GroupLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Channels != nil {
 		in, out := &in.Channels, &out.Channels
 		*out = make([]string, len(*in))
@@ -2308,6 +3086,184 @@ func (in *Throttle) DeepCopy() *Throttle {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeInterval) DeepCopyInto(out *TimeInterval) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeInterval.
+func (in *TimeInterval) DeepCopy() *TimeInterval {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeInterval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TimeInterval) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeIntervalList) DeepCopyInto(out *TimeIntervalList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TimeInterval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeIntervalList.
+func (in *TimeIntervalList) DeepCopy() *TimeIntervalList {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeIntervalList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TimeIntervalList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeIntervalSpec) DeepCopyInto(out *TimeIntervalSpec) {
+	*out = *in
+	if in.TimePeriods != nil {
+		in, out := &in.TimePeriods, &out.TimePeriods
+		*out = make([]TimePeriod, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeIntervalSpec.
+func (in *TimeIntervalSpec) DeepCopy() *TimeIntervalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeIntervalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeIntervalStatus) DeepCopyInto(out *TimeIntervalStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeIntervalStatus.
+func (in *TimeIntervalStatus) DeepCopy() *TimeIntervalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeIntervalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeIntervals) DeepCopyInto(out *TimeIntervals) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Periods != nil {
+		in, out := &in.Periods, &out.Periods
+		*out = make([]TimePeriod, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeIntervals.
+func (in *TimeIntervals) DeepCopy() *TimeIntervals {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeIntervals)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimePeriod) DeepCopyInto(out *TimePeriod) {
+	*out = *in
+	if in.Times != nil {
+		in, out := &in.Times, &out.Times
+		*out = make([]TimeRange, len(*in))
+		copy(*out, *in)
+	}
+	if in.Weekdays != nil {
+		in, out := &in.Weekdays, &out.Weekdays
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DaysOfMonth != nil {
+		in, out := &in.DaysOfMonth, &out.DaysOfMonth
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Months != nil {
+		in, out := &in.Months, &out.Months
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Years != nil {
+		in, out := &in.Years, &out.Years
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimePeriod.
+func (in *TimePeriod) DeepCopy() *TimePeriod {
+	if in == nil {
+		return nil
+	}
+	out := new(TimePeriod)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeRange) DeepCopyInto(out *TimeRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeRange.
+func (in *TimeRange) DeepCopy() *TimeRange {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ValueSource) DeepCopyInto(out *ValueSource) {
 	*out = *in
@@ -2358,6 +3314,11 @@ func (in *WebhookOptions) DeepCopyInto(out *WebhookOptions) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookOptions.
@@ -2388,6 +3349,11 @@ func (in *WebhookReceiver) DeepCopyInto(out *WebhookReceiver) {
 		*out = new(LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GroupLabels != nil {
+		in, out := &in.GroupLabels, &out.GroupLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.URL != nil {
 		in, out := &in.URL, &out.URL
 		*out = new(string)
@@ -2490,6 +3456,11 @@ func (in *WechatOptions) DeepCopyInto(out *WechatOptions) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WechatOptions.
@@ -2520,6 +3491,11 @@ func (in *WechatReceiver) DeepCopyInto(out *WechatReceiver) {
 		*out = new(LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GroupLabels != nil {
+		in, out := &in.GroupLabels, &out.GroupLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ToUser != nil {
 		in, out := &in.ToUser, &out.ToUser
 		*out = make([]string, len(*in))
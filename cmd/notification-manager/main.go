@@ -10,8 +10,12 @@ import (
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/ack"
 	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/deadletter"
 	"github.com/kubesphere/notification-manager/pkg/dispatcher"
+	"github.com/kubesphere/notification-manager/pkg/nflog"
+	"github.com/kubesphere/notification-manager/pkg/status"
 	"github.com/kubesphere/notification-manager/pkg/store"
 	wh "github.com/kubesphere/notification-manager/pkg/webhook"
 	"gopkg.in/alecthomas/kingpin.v2"
@@ -52,7 +56,12 @@ var (
 
 	storeType = kingpin.Flag(
 		"store.type",
-		"Type of store which used to cache the alerts",
+		"Type of store which used to cache the alerts. Possible values: memory, file",
+	).Default("memory").String()
+
+	ackStoreType = kingpin.Flag(
+		"ack.type",
+		"Type of store which used to keep the acknowledgements of the alerts. Possible values: memory, configmap",
 	).Default("memory").String()
 
 	logLevels = []string{
@@ -116,13 +125,37 @@ func Main() int {
 		return -1
 	}
 
-	alerts := store.NewAlertStore(*storeType)
+	alerts, err := store.NewAlertStore(*storeType)
+	if err != nil {
+		_ = level.Error(logger).Log("msg", "Failed to create alert store", "type", *storeType, "err", err)
+		return -1
+	}
+
+	// Notifications which failed to be sent after all retries will be put into the dead-letter queue.
+	deadLetters := deadletter.NewQueue()
+	// The notification log is used to suppress the repeated notifications.
+	notificationLog := nflog.New(logger)
+	// The acknowledgements are used to stop the repeated notifications and the escalations of the acknowledged alerts.
+	acks, err := ack.NewStore(*ackStoreType)
+	if err != nil {
+		_ = level.Error(logger).Log("msg", "Failed to create ack store", "type", *ackStoreType, "err", err)
+		return -1
+	}
+
+	// The recorder writes the status of the notification manager, routers, receivers, silences and configs periodically.
+	recorder := status.NewRecorder(ctlCtx, logger, ctl)
+
+	// The recently routed alerts are kept for the silence preview.
+	recentAlerts := dispatcher.NewRecentAlerts()
 
 	// Setup webhook to receive alert/notification msg
 	webhook := wh.New(
 		logger,
 		ctl,
 		alerts,
+		deadLetters,
+		acks,
+		recentAlerts,
 		&wh.Options{
 			ListenAddress:  *listenAddress,
 			WebhookTimeout: *webhookTimeout,
@@ -138,7 +171,7 @@ func Main() int {
 	}()
 
 	dispCh := make(chan error, 1)
-	disp := dispatcher.New(logger, ctl, alerts, *webhookTimeout, *wkrTimeout, *wkrQueue)
+	disp := dispatcher.New(logger, ctl, alerts, deadLetters, notificationLog, acks, recorder, recentAlerts, *webhookTimeout, *wkrTimeout, *wkrQueue)
 	go func() {
 		dispCh <- disp.Run()
 	}()
@@ -159,6 +192,9 @@ func Main() int {
 			_ = level.Info(logger).Log("msg", "Store closed")
 		case <-dispCh:
 			_ = level.Info(logger).Log("msg", "Dispatcher closed")
+			if err := notificationLog.Snapshot(); err != nil {
+				_ = level.Error(logger).Log("msg", "Failed to write notification log snapshot", "error", err.Error())
+			}
 			return 0
 		}
 	}
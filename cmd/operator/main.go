@@ -101,6 +101,26 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&v2beta2.Inhibitor{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "inhibitor")
+		os.Exit(1)
+	}
+
+	if err = (&v2beta2.EscalationPolicy{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "escalationpolicy")
+		os.Exit(1)
+	}
+
+	if err = (&v2beta2.OnCallSchedule{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "oncallschedule")
+		os.Exit(1)
+	}
+
+	if err = (&v2beta2.TimeInterval{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "timeinterval")
+		os.Exit(1)
+	}
+
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
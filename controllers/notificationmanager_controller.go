@@ -60,14 +60,18 @@ type NotificationManagerReconciler struct {
 
 // Reconcile reads that state of NotificationManager objects and makes changes based on the state read
 // and what is in the NotificationManagerSpec
-// +kubebuilder:rbac:groups=notification.kubesphere.io,resources=notificationmanagers;receivers;configs;routers;silences,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=notification.kubesphere.io,resources=notificationmanagers;receivers;configs;routers;silences;inhibitors;escalationpolicies;oncallschedules;timeintervals,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=notification.kubesphere.io,resources=notificationmanagers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=notification.kubesphere.io,resources=configs/status;receivers/status;routers/status;silences/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=notification.kubesphere.io,resources=notificationmanagers/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
-// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update
 // +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=replicasets;statefulsets;daemonsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=get;list;watch
 
 func (r *NotificationManagerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 
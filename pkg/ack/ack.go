@@ -0,0 +1,108 @@
+package ack
+
+import (
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/ack/provider"
+	"github.com/kubesphere/notification-manager/pkg/ack/provider/configmap"
+	"github.com/kubesphere/notification-manager/pkg/ack/provider/memory"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const (
+	providerMemory    = "memory"
+	providerConfigMap = "configmap"
+)
+
+var (
+	ttl *time.Duration
+)
+
+func init() {
+	ttl = kingpin.Flag(
+		"ack.ttl",
+		"How long to keep an acknowledgement, the alert will be notified again if it is still firing after that, 0 means keeping the acknowledgements until the alerts are resolved",
+	).Default("24h").Duration()
+}
+
+// Store keeps the acknowledgements of the alerts.
+// An acknowledged alert will not be notified repeatedly, and its escalation will be stopped.
+// The acknowledgement is removed when the alert is resolved or after the TTL,
+// so that the acknowledgements of the alerts whose resolved notifications are lost will not be kept forever.
+type Store struct {
+	provider.Provider
+	ttl time.Duration
+}
+
+func NewStore(provider string) (*Store, error) {
+
+	s := &Store{
+		ttl: *ttl,
+	}
+
+	switch provider {
+	case providerMemory:
+		s.Provider = memory.NewProvider()
+	case providerConfigMap:
+		p, err := configmap.NewProvider()
+		if err != nil {
+			return nil, err
+		}
+		s.Provider = p
+	default:
+		return nil, utils.Errorf("unknown ack store type %s", provider)
+	}
+
+	go s.gc()
+	return s, nil
+}
+
+func (s *Store) gc() {
+	if s.ttl <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.ttl / 10)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		_ = s.expire(time.Now())
+	}
+}
+
+// expire removes the acknowledgements which are older than the TTL.
+func (s *Store) expire(now time.Time) error {
+
+	acks, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	for fingerprint, ack := range acks {
+		if now.Sub(ack.Time) > s.ttl {
+			if err := s.Delete(fingerprint); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Acknowledge records that the alert with the fingerprint has been acknowledged by someone.
+func (s *Store) Acknowledge(fingerprint, by, comment string) (*template.Acknowledgement, error) {
+
+	ack := &template.Acknowledgement{
+		By:      by,
+		Comment: comment,
+		Time:    time.Now(),
+	}
+
+	if err := s.Set(fingerprint, ack); err != nil {
+		return nil, err
+	}
+
+	return ack, nil
+}
@@ -0,0 +1,39 @@
+package ack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/ack/provider/memory"
+	"github.com/kubesphere/notification-manager/pkg/template"
+)
+
+func TestExpire(t *testing.T) {
+	s := &Store{
+		Provider: memory.NewProvider(),
+		ttl:      time.Hour,
+	}
+
+	now := time.Now()
+	if err := s.Set("old", &template.Acknowledgement{By: "admin", Time: now.Add(-2 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Acknowledge("new", "admin", "working on it"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.expire(now); err != nil {
+		t.Fatal(err)
+	}
+
+	if ack, _ := s.Get("old"); ack != nil {
+		t.Fatal("expected the acknowledgement older than the TTL to be removed")
+	}
+	ack, _ := s.Get("new")
+	if ack == nil {
+		t.Fatal("expected the acknowledgement within the TTL to be kept")
+	}
+	if ack.By != "admin" || ack.Comment != "working on it" {
+		t.Fatalf("unexpected acknowledgement %v", ack)
+	}
+}
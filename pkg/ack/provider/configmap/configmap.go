@@ -0,0 +1,210 @@
+package configmap
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/ack/provider"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	kconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+const (
+	nsEnvironment = "NAMESPACE"
+)
+
+var (
+	name    *string
+	timeout *time.Duration
+)
+
+func init() {
+	name = kingpin.Flag(
+		"ack.configmap.name",
+		"Name of the ConfigMap used to store the acknowledgements, it is in the namespace of the Notification Manager",
+	).Default("notification-manager-acks").String()
+	timeout = kingpin.Flag(
+		"ack.configmap.timeout",
+		"Timeout for reading and writing the ConfigMap",
+	).Default("5s").Duration()
+}
+
+// configmapProvider keeps the acknowledgements in a ConfigMap, so that they can be shared between the replicas.
+// Each acknowledgement is stored as a JSON value keyed by the fingerprint of the alert.
+// The acknowledgements are read from an informer cache which only watches the ConfigMap, because they are read for every
+// batch of alerts, the updates read the ConfigMap from the Kubernetes API to avoid conflicts caused by a stale cache.
+type configmapProvider struct {
+	client    client.Client
+	reader    client.Reader
+	namespace string
+	name      string
+}
+
+func NewProvider() (provider.Provider, error) {
+
+	cfg, err := kconfig.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := client.New(cfg, client.Options{})
+	if err != nil {
+		return nil, err
+	}
+
+	ns := os.Getenv(nsEnvironment)
+	if utils.StringIsNil(ns) {
+		return nil, utils.Error("namespace is empty")
+	}
+
+	informerCache, err := cache.New(cfg, cache.Options{
+		Namespaces: []string{ns},
+		ByObject: map[client.Object]cache.ByObject{
+			&v1.ConfigMap{}: {Field: fields.OneTermEqualSelector("metadata.name", *name)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		_ = informerCache.Start(context.Background())
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	if _, err := informerCache.GetInformer(ctx, &v1.ConfigMap{}); err != nil {
+		return nil, err
+	}
+	if !informerCache.WaitForCacheSync(ctx) {
+		return nil, utils.Error("ConfigMap cache failed to sync")
+	}
+
+	return &configmapProvider{
+		client:    c,
+		reader:    informerCache,
+		namespace: ns,
+		name:      *name,
+	}, nil
+}
+
+// get returns the ConfigMap read by the reader, nil will be returned if the ConfigMap does not exist.
+func (p *configmapProvider) get(ctx context.Context, reader client.Reader) (*v1.ConfigMap, error) {
+
+	cm := &v1.ConfigMap{}
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: p.namespace, Name: p.name}, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return cm, nil
+}
+
+func (p *configmapProvider) Get(fingerprint string) (*template.Acknowledgement, error) {
+
+	acks, err := p.List()
+	if err != nil {
+		return nil, err
+	}
+
+	return acks[fingerprint], nil
+}
+
+func (p *configmapProvider) List() (map[string]*template.Acknowledgement, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	cm, err := p.get(ctx, p.reader)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]*template.Acknowledgement)
+	if cm == nil {
+		return res, nil
+	}
+
+	for k, v := range cm.Data {
+		ack := &template.Acknowledgement{}
+		if err := json.Unmarshal([]byte(v), ack); err != nil {
+			// Ignore the invalid value, it may be modified by others.
+			continue
+		}
+		res[k] = ack
+	}
+
+	return res, nil
+}
+
+func (p *configmapProvider) Set(fingerprint string, ack *template.Acknowledgement) error {
+
+	bs, err := json.Marshal(ack)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	// The ConfigMap may be created by another replica after it is read, then it will be updated in the next retry.
+	return retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return errors.IsConflict(err) || errors.IsAlreadyExists(err)
+	}, func() error {
+		cm, err := p.get(ctx, p.client)
+		if err != nil {
+			return err
+		}
+
+		if cm == nil {
+			cm = &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: p.namespace,
+					Name:      p.name,
+				},
+				Data: map[string]string{fingerprint: string(bs)},
+			}
+			return p.client.Create(ctx, cm)
+		}
+
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		cm.Data[fingerprint] = string(bs)
+		return p.client.Update(ctx, cm)
+	})
+}
+
+func (p *configmapProvider) Delete(fingerprint string) error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := p.get(ctx, p.client)
+		if err != nil || cm == nil {
+			return err
+		}
+
+		if _, ok := cm.Data[fingerprint]; !ok {
+			return nil
+		}
+
+		delete(cm.Data, fingerprint)
+		return p.client.Update(ctx, cm)
+	})
+}
@@ -0,0 +1,131 @@
+package configmap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/template"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestProvider(t *testing.T, objs ...client.Object) *configmapProvider {
+	t.Helper()
+
+	*timeout = time.Second
+	c := fake.NewClientBuilder().WithObjects(objs...).Build()
+	return &configmapProvider{
+		client:    c,
+		reader:    c,
+		namespace: "kubesphere-monitoring-system",
+		name:      "notification-manager-acks",
+	}
+}
+
+func TestProvider(t *testing.T) {
+	p := newTestProvider(t)
+
+	// The ConfigMap does not exist.
+	acks, err := p.List()
+	if err != nil || len(acks) != 0 {
+		t.Fatalf("expected no acknowledgements, got %v, %v", acks, err)
+	}
+	if err := p.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	for _, fingerprint := range []string{"a", "b"} {
+		if err := p.Set(fingerprint, &template.Acknowledgement{By: "admin", Comment: fingerprint, Time: now}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ack, err := p.Get("b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ack == nil || ack.By != "admin" || ack.Comment != "b" || !ack.Time.Equal(now) {
+		t.Fatalf("unexpected acknowledgement %v", ack)
+	}
+
+	if err := p.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	acks, err = p.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(acks) != 1 || acks["b"] == nil {
+		t.Fatalf("expected only b to be kept, got %v", acks)
+	}
+}
+
+func TestListIgnoresInvalidValues(t *testing.T) {
+	p := newTestProvider(t, &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kubesphere-monitoring-system", Name: "notification-manager-acks"},
+		Data: map[string]string{
+			"a": `{"by":"admin"}`,
+			"b": "modified by others",
+		},
+	})
+
+	acks, err := p.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(acks) != 1 || acks["a"] == nil || acks["a"].By != "admin" {
+		t.Fatalf("expected only the valid acknowledgement, got %v", acks)
+	}
+
+	// Setting an acknowledgement keeps the other values.
+	if err := p.Set("c", &template.Acknowledgement{By: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+	cm := &v1.ConfigMap{}
+	if err := p.client.Get(context.Background(), types.NamespacedName{Namespace: p.namespace, Name: p.name}, cm); err != nil {
+		t.Fatal(err)
+	}
+	if len(cm.Data) != 3 {
+		t.Fatalf("expected 3 values, got %v", cm.Data)
+	}
+}
+
+// staleClient returns NotFound for the first Get, as if the ConfigMap had been created by another replica after it was read.
+type staleClient struct {
+	client.Client
+	stale bool
+}
+
+func (c *staleClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if c.stale {
+		c.stale = false
+		return errors.NewNotFound(v1.Resource("configmaps"), key.Name)
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func TestSetAlreadyExists(t *testing.T) {
+	p := newTestProvider(t, &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kubesphere-monitoring-system", Name: "notification-manager-acks"},
+		Data:       map[string]string{"a": `{"by":"admin","time":"2023-06-18T07:05:04Z"}`},
+	})
+	p.client = &staleClient{Client: p.client, stale: true}
+
+	if err := p.Set("b", &template.Acknowledgement{By: "admin", Time: time.Now()}); err != nil {
+		t.Fatalf("expected the existing ConfigMap to be updated, got %v", err)
+	}
+
+	acks, err := p.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acks["a"] == nil || acks["b"] == nil {
+		t.Fatalf("expected both acknowledgements to be kept, got %v", acks)
+	}
+}
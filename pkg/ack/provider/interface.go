@@ -0,0 +1,15 @@
+package provider
+
+import (
+	"github.com/kubesphere/notification-manager/pkg/template"
+)
+
+// Provider persists the acknowledgements of the alerts, which are keyed by the fingerprints of the alerts.
+type Provider interface {
+	// Get returns the acknowledgement of the alert, nil will be returned if the alert has not been acknowledged.
+	Get(fingerprint string) (*template.Acknowledgement, error)
+	// List returns all acknowledgements keyed by the fingerprints of the alerts.
+	List() (map[string]*template.Acknowledgement, error)
+	Set(fingerprint string, ack *template.Acknowledgement) error
+	Delete(fingerprint string) error
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/kubesphere/notification-manager/pkg/ack/provider"
+	"github.com/kubesphere/notification-manager/pkg/template"
+)
+
+// memProvider keeps the acknowledgements in memory, they are lost when the Notification Manager restarts,
+// and are not shared between the replicas.
+type memProvider struct {
+	mutex sync.RWMutex
+	acks  map[string]*template.Acknowledgement
+}
+
+func NewProvider() provider.Provider {
+	return &memProvider{
+		acks: make(map[string]*template.Acknowledgement),
+	}
+}
+
+func (p *memProvider) Get(fingerprint string) (*template.Acknowledgement, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.acks[fingerprint], nil
+}
+
+func (p *memProvider) List() (map[string]*template.Acknowledgement, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	res := make(map[string]*template.Acknowledgement, len(p.acks))
+	for k, v := range p.acks {
+		res[k] = v
+	}
+
+	return res, nil
+}
+
+func (p *memProvider) Set(fingerprint string, ack *template.Acknowledgement) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.acks[fingerprint] = ack
+	return nil
+}
+
+func (p *memProvider) Delete(fingerprint string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	delete(p.acks, fingerprint)
+	return nil
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/template"
+)
+
+func TestProvider(t *testing.T) {
+	p := NewProvider()
+
+	if ack, err := p.Get("a"); err != nil || ack != nil {
+		t.Fatalf("expected no acknowledgement, got %v, %v", ack, err)
+	}
+
+	ack := &template.Acknowledgement{By: "admin", Time: time.Now()}
+	if err := p.Set("a", ack); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := p.Get("a"); got != ack {
+		t.Fatalf("expected %v, got %v", ack, got)
+	}
+
+	acks, _ := p.List()
+	if len(acks) != 1 || acks["a"] != ack {
+		t.Fatalf("expected the acknowledgement to be listed, got %v", acks)
+	}
+	// The listed map is a copy.
+	delete(acks, "a")
+	if got, _ := p.Get("a"); got == nil {
+		t.Fatal("expected the acknowledgement not to be changed by the listed map")
+	}
+
+	if err := p.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := p.Get("a"); got != nil {
+		t.Fatal("expected the acknowledgement to be deleted")
+	}
+	if err := p.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+}
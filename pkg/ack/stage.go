@@ -0,0 +1,64 @@
+package ack
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/constants"
+	"github.com/kubesphere/notification-manager/pkg/stage"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/modern-go/reflect2"
+)
+
+type ackStage struct {
+	acks *Store
+}
+
+// NewStage returns a stage which attaches the acknowledgements to the alerts,
+// and removes the acknowledgements of the resolved alerts.
+func NewStage(acks *Store) stage.Stage {
+	return &ackStage{
+		acks: acks,
+	}
+}
+
+func (s *ackStage) Exec(ctx context.Context, l log.Logger, data interface{}) (context.Context, interface{}, error) {
+
+	if reflect2.IsNil(data) {
+		return ctx, nil, nil
+	}
+
+	input := data.([]*template.Alert)
+
+	_ = level.Debug(l).Log("msg", "Start ack stage", "seq", ctx.Value("seq"), "alert", len(input))
+
+	acks, err := s.acks.List()
+	if err != nil {
+		// The alerts will be treated as not acknowledged.
+		_ = level.Error(l).Log("msg", "List acknowledgements failed", "stage", "Ack", "seq", ctx.Value("seq"), "error", err.Error())
+		return ctx, input, nil
+	}
+
+	if len(acks) == 0 {
+		return ctx, input, nil
+	}
+
+	for _, alert := range input {
+		fingerprint := alert.Fingerprint()
+		ack, ok := acks[fingerprint]
+		if !ok {
+			continue
+		}
+
+		alert.Acknowledgement = ack
+		// The alert will be notified again if it fires again after resolved.
+		if alert.Status == constants.AlertResolved {
+			if err := s.acks.Delete(fingerprint); err != nil {
+				_ = level.Error(l).Log("msg", "Delete acknowledgement failed", "stage", "Ack", "seq", ctx.Value("seq"), "fingerprint", fingerprint, "error", err.Error())
+			}
+		}
+	}
+
+	return ctx, input, nil
+}
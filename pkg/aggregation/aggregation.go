@@ -30,34 +30,48 @@ func (s *aggregationStage) Exec(ctx context.Context, l log.Logger, data interfac
 		return ctx, nil, nil
 	}
 
-	groupLabel := s.notifierCtl.GetGroupLabels()
-	_ = level.Debug(l).Log("msg", "Start aggregation stage", "seq", ctx.Value("seq"), "group by", utils.ArrayToString(groupLabel, ","))
+	_ = level.Debug(l).Log("msg", "Start aggregation stage", "seq", ctx.Value("seq"))
 
 	alertMap := data.(map[internal.Receiver][]*template.Alert)
 
 	res := make(map[internal.Receiver][]*template.Data)
 	for receiver, alerts := range alertMap {
-		m := make(map[string][]*template.Alert)
-		for _, alert := range alerts {
-			group := labelToGroupKey(groupLabel, alert)
-			as := m[group]
-			as = append(as, alert)
-			m[group] = as
-		}
+		res[receiver] = groupAlerts(groupLabels(s.notifierCtl, receiver), alerts)
+	}
 
-		var ds []*template.Data
-		for k, v := range m {
-			d := &template.Data{
-				GroupLabels: groupKeyToLabel(k),
-				Alerts:      v,
-			}
-			ds = append(ds, d.Format())
-		}
+	return ctx, res, nil
+}
 
-		res[receiver] = ds
+// groupLabels returns the labels used to group the alerts sent to the receiver.
+// The group labels of the receiver take precedence over the router, and then the NotificationManager.
+func groupLabels(notifierCtl *controller.Controller, receiver internal.Receiver) []string {
+	if opts := receiver.GetGroupOptions(); opts != nil && opts.GroupLabels != nil {
+		return opts.GroupLabels
 	}
 
-	return ctx, res, nil
+	return notifierCtl.GetGroupLabels()
+}
+
+func groupAlerts(groupLabel []string, alerts []*template.Alert) []*template.Data {
+
+	m := make(map[string][]*template.Alert)
+	for _, alert := range alerts {
+		group := labelToGroupKey(groupLabel, alert)
+		as := m[group]
+		as = append(as, alert)
+		m[group] = as
+	}
+
+	var ds []*template.Data
+	for k, v := range m {
+		d := &template.Data{
+			GroupLabels: groupKeyToLabel(k),
+			Alerts:      v,
+		}
+		ds = append(ds, d.Format())
+	}
+
+	return ds
 }
 
 func labelToGroupKey(groupLabel []string, alert *template.Alert) string {
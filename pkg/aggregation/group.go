@@ -0,0 +1,229 @@
+package aggregation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/store"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/modern-go/reflect2"
+)
+
+// FlushFunc sends the notifications of the groups which need to be flushed, and calls release once the notifications
+// have been sent or failed after all retries, so that the alerts can be acknowledged by the store. The release can be nil.
+type FlushFunc func(data map[internal.Receiver][]*template.Data, release func())
+
+// aggrGroup buffers the alerts which will be sent to the same receiver with the same group labels.
+type aggrGroup struct {
+	receiver internal.Receiver
+	groupKey string
+	interval time.Duration
+	timer    *time.Timer
+	// The alerts in the order they arrived, an alert will be replaced by the one with the same fingerprint.
+	fingerprints []string
+	alerts       map[string]*template.Alert
+	// The functions to release the batches of the buffered alerts, so that the alerts can be acknowledged by the store.
+	releases map[string]func()
+}
+
+func (g *aggrGroup) add(alert *template.Alert, release func()) {
+	fingerprint := alert.Fingerprint()
+	if _, ok := g.alerts[fingerprint]; !ok {
+		g.fingerprints = append(g.fingerprints, fingerprint)
+	}
+	// The replaced alert will never be sent, its batch can be released.
+	if r, ok := g.releases[fingerprint]; ok {
+		r()
+	}
+	g.alerts[fingerprint] = alert
+	g.releases[fingerprint] = release
+}
+
+// take returns the buffered alerts as a notification and the function to release their batches, and clears the buffer.
+func (g *aggrGroup) take() (*template.Data, func()) {
+	if len(g.fingerprints) == 0 {
+		return nil, nil
+	}
+
+	d := &template.Data{
+		GroupLabels: groupKeyToLabel(g.groupKey),
+	}
+	for _, fingerprint := range g.fingerprints {
+		d.Alerts = append(d.Alerts, g.alerts[fingerprint])
+	}
+
+	releases := g.releases
+	g.fingerprints = nil
+	g.alerts = make(map[string]*template.Alert)
+	g.releases = make(map[string]func())
+	return d.Format(), func() {
+		for _, r := range releases {
+			r()
+		}
+	}
+}
+
+// Aggregator is a stateful aggregation stage. It groups the alerts sent to the same receiver by the group labels,
+// the alerts of a new group will be buffered for the group wait before they are sent together,
+// after that, the alerts arrived later will be buffered and sent every group interval.
+// A group will be removed if no alert arrives in a group interval.
+// The alerts whose group wait and group interval are both zero will be sent immediately.
+type Aggregator struct {
+	mutex       sync.Mutex
+	notifierCtl *controller.Controller
+	logger      log.Logger
+	groups      map[string]*aggrGroup
+	flush       FlushFunc
+	closed      bool
+}
+
+func NewAggregator(notifierCtl *controller.Controller, logger log.Logger, flush FlushFunc) *Aggregator {
+	return &Aggregator{
+		notifierCtl: notifierCtl,
+		logger:      logger,
+		groups:      make(map[string]*aggrGroup),
+		flush:       flush,
+	}
+}
+
+func (a *Aggregator) Exec(ctx context.Context, l log.Logger, data interface{}) (context.Context, interface{}, error) {
+
+	if reflect2.IsNil(data) {
+		return ctx, nil, nil
+	}
+
+	_ = level.Debug(l).Log("msg", "Start aggregation stage", "seq", ctx.Value("seq"))
+
+	alertMap := data.(map[internal.Receiver][]*template.Alert)
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	res := make(map[internal.Receiver][]*template.Data)
+	for receiver, alerts := range alertMap {
+		groupLabel := groupLabels(a.notifierCtl, receiver)
+		wait, interval := a.timing(receiver)
+		// Send the alerts immediately if they need not wait, or the aggregator has been closed.
+		if (wait <= 0 && interval <= 0) || a.closed {
+			res[receiver] = groupAlerts(groupLabel, alerts)
+			continue
+		}
+
+		for _, alert := range alerts {
+			// Hold the batch of the alert until the alert is sent.
+			a.insert(receiver, labelToGroupKey(groupLabel, alert), alert, store.Hold(ctx), wait, interval)
+		}
+	}
+
+	if len(res) == 0 {
+		return ctx, nil, nil
+	}
+
+	return ctx, res, nil
+}
+
+// timing returns the group wait and group interval of the receiver,
+// the options set by the router take precedence over the global options.
+func (a *Aggregator) timing(receiver internal.Receiver) (time.Duration, time.Duration) {
+	wait := a.notifierCtl.GetGroupWait()
+	interval := a.notifierCtl.GetGroupInterval()
+	if opts := receiver.GetGroupOptions(); opts != nil {
+		if opts.GroupWait != nil {
+			wait = *opts.GroupWait
+		}
+		if opts.GroupInterval != nil {
+			interval = *opts.GroupInterval
+		}
+	}
+
+	return wait, interval
+}
+
+// insert adds the alert to the group, it must be called with the lock held.
+func (a *Aggregator) insert(receiver internal.Receiver, groupKey string, alert *template.Alert, release func(), wait, interval time.Duration) {
+
+	// The alerts sent to the same receiver with different group options are put into different groups.
+	key := fmt.Sprintf("%s/%s", internal.RouteKey(receiver), groupKey)
+	g, ok := a.groups[key]
+	if !ok {
+		g = &aggrGroup{
+			groupKey: groupKey,
+			alerts:   make(map[string]*template.Alert),
+			releases: make(map[string]func()),
+		}
+		g.timer = time.AfterFunc(wait, func() {
+			a.flushGroup(key)
+		})
+		a.groups[key] = g
+	}
+
+	// Use the latest receiver, so that the changes of the receiver will take effect.
+	g.receiver = receiver
+	g.interval = interval
+	g.add(alert, release)
+}
+
+func (a *Aggregator) flushGroup(key string) {
+
+	a.mutex.Lock()
+	g, ok := a.groups[key]
+	if !ok || a.closed {
+		a.mutex.Unlock()
+		return
+	}
+
+	d, release := g.take()
+	if d == nil {
+		delete(a.groups, key)
+		a.mutex.Unlock()
+		return
+	}
+
+	g.timer.Reset(g.interval)
+	receiver := g.receiver
+	a.mutex.Unlock()
+
+	_ = level.Debug(a.logger).Log("msg", "Aggregator: flush group", "receiver", receiver.GetName(), "alerts", len(d.Alerts))
+	a.flush(map[internal.Receiver][]*template.Data{receiver: {d}}, release)
+}
+
+// Close stops all groups, and sends the alerts buffered in the groups.
+// The alerts received after closing will be sent immediately.
+func (a *Aggregator) Close() {
+
+	a.mutex.Lock()
+	if a.closed {
+		a.mutex.Unlock()
+		return
+	}
+	a.closed = true
+
+	res := make(map[internal.Receiver][]*template.Data)
+	var releases []func()
+	for _, g := range a.groups {
+		g.timer.Stop()
+		if d, release := g.take(); d != nil {
+			res[g.receiver] = append(res[g.receiver], d)
+			releases = append(releases, release)
+		}
+	}
+	a.groups = make(map[string]*aggrGroup)
+	a.mutex.Unlock()
+
+	release := func() {
+		for _, r := range releases {
+			r()
+		}
+	}
+	if len(res) == 0 {
+		release()
+		return
+	}
+	a.flush(res, release)
+}
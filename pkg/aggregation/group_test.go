@@ -0,0 +1,64 @@
+package aggregation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/internal/webhook"
+	"github.com/kubesphere/notification-manager/pkg/template"
+)
+
+func newReceiver(hash string, opts *internal.GroupOptions) internal.Receiver {
+	return &webhook.Receiver{Common: &internal.Common{Hash: hash, GroupOptions: opts}}
+}
+
+func newAlert(name string) *template.Alert {
+	return &template.Alert{
+		Status: "firing",
+		Labels: template.KV{"alertname": name},
+	}
+}
+
+func TestGroupsSeparatedByOptions(t *testing.T) {
+	var flushed map[internal.Receiver][]*template.Data
+	a := NewAggregator(nil, nil, func(data map[internal.Receiver][]*template.Data, release func()) {
+		flushed = data
+		release()
+	})
+
+	wait := time.Hour
+	r1 := newReceiver("receiver", nil)
+	r2 := newReceiver("receiver", &internal.GroupOptions{GroupWait: &wait})
+	released := 0
+	release := func() {
+		released++
+	}
+
+	a.mutex.Lock()
+	a.insert(r1, "{}", newAlert("a"), release, time.Hour, time.Hour)
+	a.insert(r2, "{}", newAlert("b"), release, time.Hour, time.Hour)
+	// The alert with the same fingerprint replaces the buffered one, whose batch is released.
+	a.insert(r2, "{}", newAlert("b"), release, time.Hour, time.Hour)
+	a.mutex.Unlock()
+
+	if len(a.groups) != 2 {
+		t.Fatalf("expected the alerts with different group options to be in 2 groups, got %d", len(a.groups))
+	}
+	if released != 1 {
+		t.Fatalf("expected the replaced alert to be released, got %d", released)
+	}
+
+	a.Close()
+	if len(flushed) != 2 {
+		t.Fatalf("expected 2 receivers flushed, got %d", len(flushed))
+	}
+	for rcv, ds := range flushed {
+		if len(ds) != 1 || len(ds[0].Alerts) != 1 {
+			t.Fatalf("expected 1 alert sent to receiver with options %s", rcv.GetGroupOptions())
+		}
+	}
+	if released != 3 {
+		t.Fatalf("expected all alerts released after flushing, got %d", released)
+	}
+}
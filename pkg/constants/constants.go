@@ -28,6 +28,7 @@ const (
 
 	Cluster   = "cluster"
 	Namespace = "namespace"
+	Pod       = "pod"
 
 	AlertFiring   = "firing"
 	AlertResolved = "resolved"
@@ -4,9 +4,9 @@ import (
 	"context"
 	"fmt"
 	"math"
-	"net/http"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -15,8 +15,11 @@ import (
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/modern-go/reflect2"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -30,6 +33,7 @@ import (
 	"github.com/kubesphere/notification-manager/apis/v2beta2"
 	"github.com/kubesphere/notification-manager/pkg/constants"
 	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/oncall"
 	"github.com/kubesphere/notification-manager/pkg/template"
 	"github.com/kubesphere/notification-manager/pkg/utils"
 )
@@ -45,6 +49,8 @@ const (
 	nsEnvironment = "NAMESPACE"
 
 	tenantSidecarURL = "http://localhost:19094/api/v2/tenant"
+	// The batch API of the sidecar to get the tenants of many namespaces at once.
+	tenantsSidecarURL = "http://localhost:19094/api/v2/tenants"
 )
 
 var (
@@ -55,12 +61,16 @@ type Controller struct {
 	logger log.Logger
 	ctx    context.Context
 	cache  cache.Cache
+	// Client used to write the resources, the resources are read from the cache.
+	client client.Client
 	// Default config selector
 	defaultConfigSelector *metav1.LabelSelector
 	// Label key used to distinguish different user
 	tenantKey string
 	// Whether to use sidecar to get tenant list.
 	tenantSidecar bool
+	// The tenants of the namespaces resolved by the sidecar.
+	tenants *tenantCache
 	// Label selector to filter valid global Receiver CR
 	globalReceiverSelector *metav1.LabelSelector
 	// Label selector to filter valid tenant Receiver CR
@@ -70,6 +80,17 @@ type Controller struct {
 	// Config for each tenant user, in form of map[tenantID]map[type/name]Config
 	configs      map[string]map[string]internal.Config
 	ReceiverOpts *v2beta2.Options
+	// Guards the silences and the inhibitors, they are read on the hot path of the pipeline,
+	// so they are read under the lock rather than through the task channel.
+	indexMutex sync.RWMutex
+	// All silences, in form of map[name]Silence
+	silences map[string]*v2beta2.Silence
+	// Silences for each tenant, in form of map[tenantID]map[name]Silence, the tenantID of global silences is empty.
+	tenantSilences map[string]map[string]*v2beta2.Silence
+	// All inhibitors, in form of map[name]Inhibitor
+	inhibitors map[string]*v2beta2.Inhibitor
+	// Inhibitors for each tenant, in form of map[tenantID]map[name]Inhibitor, the tenantID of global inhibitors is empty.
+	tenantInhibitors map[string]map[string]*v2beta2.Inhibitor
 	// Channel to receive receiver create/update/delete operations and then update receivers
 	ch chan *task
 	// The pod's namespace
@@ -78,11 +99,16 @@ type Controller struct {
 	// Dose the notification manager crd add.
 	nmAdd bool
 
-	groupLabels  []string
-	batchMaxSize int
-	batchMaxWait metav1.Duration
+	groupLabels   []string
+	groupWait     time.Duration
+	groupInterval time.Duration
+	batchMaxSize  int
+	batchMaxWait  metav1.Duration
 
-	routePolicy string
+	routePolicy    string
+	repeatInterval time.Duration
+	relabelConfigs []v2beta2.RelabelConfig
+	enrichment     *v2beta2.Enrichment
 
 	// Global template.
 	template  *v2beta2.Template
@@ -102,6 +128,8 @@ func New(ctx context.Context, logger log.Logger) (*Controller, error) {
 	_ = v2beta2.AddToScheme(scheme)
 	_ = v1.AddToScheme(scheme)
 	_ = rbacv1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = batchv1.AddToScheme(scheme)
 
 	cfg, err := kconfig.GetConfig()
 	if err != nil {
@@ -118,6 +146,14 @@ func New(ctx context.Context, logger log.Logger) (*Controller, error) {
 		return nil, err
 	}
 
+	kubeClient, err := client.New(cfg, client.Options{
+		Scheme: scheme,
+	})
+	if err != nil {
+		_ = level.Error(logger).Log("msg", "Failed to create client", "err", err)
+		return nil, err
+	}
+
 	ns := os.Getenv(nsEnvironment)
 	if len(ns) == 0 {
 		return nil, level.Error(logger).Log("msg", "namespace is empty")
@@ -127,12 +163,18 @@ func New(ctx context.Context, logger log.Logger) (*Controller, error) {
 		ctx:                    ctx,
 		logger:                 logger,
 		cache:                  informerCache,
+		client:                 kubeClient,
 		tenantKey:              defaultTenantKey,
+		tenants:                newTenantCache(),
 		defaultConfigSelector:  nil,
 		tenantReceiverSelector: nil,
 		globalReceiverSelector: nil,
 		receivers:              make(map[string]map[string]internal.Receiver),
 		configs:                make(map[string]map[string]internal.Config),
+		silences:               make(map[string]*v2beta2.Silence),
+		tenantSilences:         make(map[string]map[string]*v2beta2.Silence),
+		inhibitors:             make(map[string]*v2beta2.Inhibitor),
+		tenantInhibitors:       make(map[string]map[string]*v2beta2.Inhibitor),
 		ReceiverOpts:           nil,
 		ch:                     make(chan *task, ChannelCapacity),
 		namespace:              ns,
@@ -173,6 +215,9 @@ func (c *Controller) Run() error {
 			c.onResourceChange(Obj, opAdd, c.nmChange)
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
+			if !specChanged(oldObj, newObj) {
+				return
+			}
 			c.onResourceChange(newObj, opUpdate, c.nmChange)
 		},
 		DeleteFunc: func(Obj interface{}) {
@@ -190,6 +235,9 @@ func (c *Controller) Run() error {
 			c.onResourceChange(obj, opAdd, c.receiverChanged)
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
+			if !specChanged(oldObj, newObj) {
+				return
+			}
 			c.onResourceChange(newObj, opUpdate, c.receiverChanged)
 		},
 		DeleteFunc: func(obj interface{}) {
@@ -207,6 +255,9 @@ func (c *Controller) Run() error {
 			c.onResourceChange(obj, opAdd, c.configChanged)
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
+			if !specChanged(oldObj, newObj) {
+				return
+			}
 			c.onResourceChange(newObj, opUpdate, c.configChanged)
 		},
 		DeleteFunc: func(obj interface{}) {
@@ -214,9 +265,67 @@ func (c *Controller) Run() error {
 		},
 	})
 
+	silenceInformer, err := c.cache.GetInformer(c.ctx, &v2beta2.Silence{})
+	if err != nil {
+		_ = level.Error(c.logger).Log("msg", "Failed to get silence informer", "err", err)
+		return err
+	}
+	silenceInformer.AddEventHandler(kcache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.onResourceChange(obj, opAdd, c.silenceChanged)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if !specChanged(oldObj, newObj) {
+				return
+			}
+			c.onResourceChange(newObj, opUpdate, c.silenceChanged)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.onResourceChange(obj, opDel, c.silenceChanged)
+		},
+	})
+
+	inhibitorInformer, err := c.cache.GetInformer(c.ctx, &v2beta2.Inhibitor{})
+	if err != nil {
+		_ = level.Error(c.logger).Log("msg", "Failed to get inhibitor informer", "err", err)
+		return err
+	}
+	inhibitorInformer.AddEventHandler(kcache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.onResourceChange(obj, opAdd, c.inhibitorChanged)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if !specChanged(oldObj, newObj) {
+				return
+			}
+			c.onResourceChange(newObj, opUpdate, c.inhibitorChanged)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.onResourceChange(obj, opDel, c.inhibitorChanged)
+		},
+	})
+
 	return c.ctx.Err()
 }
 
+// specChanged returns false if only the status of the object has been written, so that the receivers, silences and
+// inhibitors are not rebuilt, and the compiled matchers are kept when the status is written periodically.
+func specChanged(oldObj, newObj interface{}) bool {
+	o, ok := oldObj.(client.Object)
+	if !ok {
+		return true
+	}
+	n, ok := newObj.(client.Object)
+	if !ok {
+		return true
+	}
+
+	return o.GetGeneration() == 0 ||
+		o.GetGeneration() != n.GetGeneration() ||
+		!reflect.DeepEqual(o.GetLabels(), n.GetLabels()) ||
+		!reflect.DeepEqual(o.GetAnnotations(), n.GetAnnotations())
+}
+
 func (c *Controller) onResourceChange(obj interface{}, op string, run func(t *task)) {
 	t := &task{
 		op:   op,
@@ -241,6 +350,8 @@ func (c *Controller) nmChange(t *task) {
 		c.defaultConfigSelector = nil
 		c.ReceiverOpts = nil
 		c.nmAdd = false
+		c.reindexSilences()
+		c.reindexInhibitors()
 
 		return
 	}
@@ -270,6 +381,12 @@ func (c *Controller) nmChange(t *task) {
 		needToReloadReceiver = true
 	}
 
+	// The tenant of a silence or an inhibitor depends on the tenant key and the selectors.
+	if needToReloadReceiver {
+		c.reindexSilences()
+		c.reindexInhibitors()
+	}
+
 	c.ReceiverOpts = spec.Receivers.Options
 	c.tenantSidecar = false
 	if spec.Sidecars != nil {
@@ -280,9 +397,23 @@ func (c *Controller) nmChange(t *task) {
 
 	c.history = spec.History
 	c.groupLabels = spec.GroupLabels
+	c.groupWait = 0
+	if spec.GroupWait != nil {
+		c.groupWait = spec.GroupWait.Duration
+	}
+	c.groupInterval = 0
+	if spec.GroupInterval != nil {
+		c.groupInterval = spec.GroupInterval.Duration
+	}
 	c.batchMaxSize = spec.BatchMaxSize
 	c.batchMaxWait = spec.BatchMaxWait
 	c.routePolicy = spec.RoutePolicy
+	c.repeatInterval = 0
+	if spec.RepeatInterval != nil {
+		c.repeatInterval = spec.RepeatInterval.Duration
+	}
+	c.relabelConfigs = spec.RelabelConfigs
+	c.enrichment = spec.Enrichment
 	c.template = spec.Template
 	c.nmAdd = true
 
@@ -506,49 +637,197 @@ func (c *Controller) receiverChanged(t *task) {
 	_ = level.Info(c.logger).Log("msg", "Receiver changed", "op", t.op, "name", receiver.Name)
 }
 
-func (c *Controller) tenantIDFromNs(cluster, namespace string) ([]string, error) {
-	tenantIDs := make([]string, 0)
-	// Use namespace as TenantID directly if tenantSidecar not provided.
-	if !c.tenantSidecar {
-		tenantIDs = append(tenantIDs, namespace)
-		return tenantIDs, nil
+func (c *Controller) silenceChanged(t *task) {
+	defer close(t.done)
+
+	obj := t.obj
+	if tombstone, ok := obj.(kcache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
 	}
 
-	p := make(map[string]string)
-	p[constants.Cluster] = cluster
-	p[constants.Namespace] = namespace
-	u, err := utils.UrlWithParameters(tenantSidecarURL, p)
-	if err != nil {
-		return nil, err
+	silence, ok := obj.(*v2beta2.Silence)
+	if !ok {
+		_ = level.Warn(c.logger).Log("msg", "not a silence object")
+		return
 	}
 
-	request, err := http.NewRequest(http.MethodGet, u, nil)
-	if err != nil {
-		return nil, err
+	c.indexMutex.Lock()
+	defer c.indexMutex.Unlock()
+
+	if old, ok := c.silences[silence.Name]; ok {
+		delete(c.silences, silence.Name)
+		if id, ok := c.tenantIDOfLabels(old.Labels); ok && c.tenantSilences[id] != nil {
+			delete(c.tenantSilences[id], old.Name)
+			if len(c.tenantSilences[id]) == 0 {
+				delete(c.tenantSilences, id)
+			}
+		}
+	}
+
+	if t.op == opDel {
+		_ = level.Debug(c.logger).Log("msg", "Silence changed", "op", t.op, "name", silence.Name)
+		return
+	}
+
+	c.silences[silence.Name] = silence
+	c.indexSilence(silence)
+
+	_ = level.Debug(c.logger).Log("msg", "Silence changed", "op", t.op, "name", silence.Name)
+}
+
+// reindexSilences rebuilds the silences of each tenant, it must be called after the tenant key or the selectors changed.
+func (c *Controller) reindexSilences() {
+
+	c.indexMutex.Lock()
+	defer c.indexMutex.Unlock()
+
+	c.tenantSilences = make(map[string]map[string]*v2beta2.Silence)
+	for _, silence := range c.silences {
+		c.indexSilence(silence)
+	}
+}
+
+func (c *Controller) indexSilence(silence *v2beta2.Silence) {
+
+	// The archived silences are ignored.
+	if silence.Labels[v2beta2.SilenceArchivedLabel] == "true" {
+		return
+	}
+
+	id, ok := c.tenantIDOfLabels(silence.Labels)
+	if !ok {
+		return
+	}
+
+	if _, ok := c.tenantSilences[id]; !ok {
+		c.tenantSilences[id] = make(map[string]*v2beta2.Silence)
+	}
+	c.tenantSilences[id][silence.Name] = silence
+}
+
+// tenantIDOfLabels returns the tenant which the object with the labels, such as a silence or an inhibitor, belongs to,
+// the tenant of a global object is empty. False will be returned if the object belongs to no tenant.
+func (c *Controller) tenantIDOfLabels(lbs map[string]string) (string, bool) {
+
+	set := labels.Set(lbs)
+	if selectorMatches(c.globalReceiverSelector, set) {
+		return "", true
+	}
+
+	if selectorMatches(c.tenantReceiverSelector, set) {
+		if v, exists := set[c.tenantKey]; exists && !utils.StringIsNil(v) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// selectorMatches returns true if the labels match the label selector, a nil or invalid selector matches nothing.
+func selectorMatches(selector *metav1.LabelSelector, set labels.Set) bool {
+
+	if selector == nil {
+		return false
 	}
-	request.Header.Set("Content-Type", "application/json")
 
-	body, err := utils.DoHttpRequest(context.Background(), nil, request)
+	sel, err := metav1.LabelSelectorAsSelector(selector)
 	if err != nil {
-		return nil, err
+		return false
 	}
 
-	res := make([]string, 0)
-	if err := utils.JsonUnmarshal(body, &res); err != nil {
-		return nil, err
+	return sel.Matches(set)
+}
+
+func (c *Controller) inhibitorChanged(t *task) {
+	defer close(t.done)
+
+	obj := t.obj
+	if tombstone, ok := obj.(kcache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
 	}
 
-	_ = level.Debug(c.logger).Log("msg", "get tenants from namespace", "cluster", cluster, "namespace", namespace, "tenant", utils.ArrayToString(res, ","))
+	inhibitor, ok := obj.(*v2beta2.Inhibitor)
+	if !ok {
+		_ = level.Warn(c.logger).Log("msg", "not an inhibitor object")
+		return
+	}
 
-	return res, nil
+	c.indexMutex.Lock()
+	defer c.indexMutex.Unlock()
+
+	if old, ok := c.inhibitors[inhibitor.Name]; ok {
+		delete(c.inhibitors, inhibitor.Name)
+		if id, ok := c.tenantIDOfLabels(old.Labels); ok && c.tenantInhibitors[id] != nil {
+			delete(c.tenantInhibitors[id], old.Name)
+			if len(c.tenantInhibitors[id]) == 0 {
+				delete(c.tenantInhibitors, id)
+			}
+		}
+	}
+
+	if t.op == opDel {
+		_ = level.Debug(c.logger).Log("msg", "Inhibitor changed", "op", t.op, "name", inhibitor.Name)
+		return
+	}
+
+	c.inhibitors[inhibitor.Name] = inhibitor
+	c.indexInhibitor(inhibitor)
+
+	_ = level.Debug(c.logger).Log("msg", "Inhibitor changed", "op", t.op, "name", inhibitor.Name)
+}
+
+// reindexInhibitors rebuilds the inhibitors of each tenant, it must be called after the tenant key or the selectors changed.
+func (c *Controller) reindexInhibitors() {
+
+	c.indexMutex.Lock()
+	defer c.indexMutex.Unlock()
+
+	c.tenantInhibitors = make(map[string]map[string]*v2beta2.Inhibitor)
+	for _, inhibitor := range c.inhibitors {
+		c.indexInhibitor(inhibitor)
+	}
+}
+
+func (c *Controller) indexInhibitor(inhibitor *v2beta2.Inhibitor) {
+
+	id, ok := c.tenantIDOfLabels(inhibitor.Labels)
+	if !ok {
+		return
+	}
+
+	if _, ok := c.tenantInhibitors[id]; !ok {
+		c.tenantInhibitors[id] = make(map[string]*v2beta2.Inhibitor)
+	}
+	c.tenantInhibitors[id][inhibitor.Name] = inhibitor
+}
+
+// silencesOfTenant returns the silences belonging to the tenant sorted by name, the tenant of global silences is empty.
+func (c *Controller) silencesOfTenant(tenant string, filter func(silence *v2beta2.Silence) bool) []v2beta2.Silence {
+
+	c.indexMutex.RLock()
+	defer c.indexMutex.RUnlock()
+
+	var ss []v2beta2.Silence
+	for _, silence := range c.tenantSilences[tenant] {
+		if filter == nil || filter(silence) {
+			ss = append(ss, *silence)
+		}
+	}
+
+	sort.Slice(ss, func(i, j int) bool {
+		return ss[i].Name < ss[j].Name
+	})
+
+	return ss
 }
 
 // `matchingConfig` used to get a matched config for a receiver.
-// It will return true when config is found.
-func getMatchedConfig(r internal.Receiver, configs map[string]map[string]internal.Config) bool {
+// It will return the name of the config when config is found.
+func getMatchedConfig(r internal.Receiver, configs map[string]map[string]internal.Config) string {
 
-	match := func(configs map[string]internal.Config, selector *v2beta2.LabelSelector) bool {
+	match := func(configs map[string]internal.Config, selector *v2beta2.LabelSelector) string {
 		p := math.MaxInt32
+		name := ""
 		for k, v := range configs {
 			if strings.HasPrefix(k, r.GetType()) {
 				if v2beta2.LabelMatchSelector(v.GetLabels(), selector) {
@@ -556,17 +835,14 @@ func getMatchedConfig(r internal.Receiver, configs map[string]map[string]interna
 						if v.GetPriority() < p {
 							r.SetConfig(v.Clone())
 							p = v.GetPriority()
+							name = strings.TrimPrefix(k, fmt.Sprintf("%s/", r.GetType()))
 						}
 					}
 				}
 			}
 		}
 
-		if p < math.MaxInt32 {
-			return true
-		}
-
-		return false
+		return name
 	}
 
 	tenantID := r.GetTenantID()
@@ -574,49 +850,50 @@ func getMatchedConfig(r internal.Receiver, configs map[string]map[string]interna
 	if tenantID == globalTenantID {
 		return match(configs[defaultConfig], configSelector)
 	} else {
-		if found := match(configs[tenantID], configSelector); !found {
+		if name := match(configs[tenantID], configSelector); name == "" {
 			return match(configs[defaultConfig], nil)
 		} else {
-			return true
+			return name
 		}
 	}
 }
 
-func (c *Controller) RcvsFromNs(cluster string, namespace *string) []internal.Receiver {
-
-	// Global receiver should receive all notifications.
-	tenants := []string{globalTenantID}
-	if namespace != nil && len(*namespace) > 0 {
-		// Get all tenants which need to receive the notifications in this namespace.
-		tenantIDs, err := c.tenantIDFromNs(cluster, *namespace)
-		if err != nil {
-			_ = level.Error(c.logger).Log("msg", "get tenantID error", "err", err, "namespace", *namespace)
-		} else {
-			tenants = append(tenants, tenantIDs...)
-		}
-	}
+// ConfigBindings returns the receivers bound to each config, in form of map[configName][]type/name.
+func (c *Controller) ConfigBindings() map[string][]string {
 
 	t := &task{
 		run: func(t *task) {
-			var rcvs []internal.Receiver
-			for _, tenant := range tenants {
-				for _, rcv := range c.receivers[tenant] {
-					if rcv.Enabled() {
-						rcv = rcv.Clone()
-						getMatchedConfig(rcv, c.configs)
-						rcvs = append(rcvs, rcv)
+			m := make(map[string][]string)
+			for tenant := range c.receivers {
+				for k, v := range c.receivers[tenant] {
+					if !v.Enabled() {
+						continue
+					}
+
+					if name := getMatchedConfig(v.Clone(), c.configs); name != "" {
+						m[name] = append(m[name], k)
 					}
 				}
 			}
 
-			t.done <- rcvs
+			t.done <- m
 		},
 		done: make(chan interface{}, 1),
 	}
 
 	c.ch <- t
 	val := <-t.done
-	return val.([]internal.Receiver)
+	return val.(map[string][]string)
+}
+
+func (c *Controller) RcvsFromNs(cluster string, namespace *string) []internal.Receiver {
+
+	ns := ClusterNamespace{Cluster: cluster}
+	if namespace != nil {
+		ns.Namespace = *namespace
+	}
+
+	return c.RcvsFromNamespaces([]ClusterNamespace{ns})[ns]
 }
 
 func (c *Controller) RcvsFromName(names []string, regexName, receiverType string) []internal.Receiver {
@@ -652,6 +929,16 @@ func (c *Controller) RcvsFromName(names []string, regexName, receiverType string
 
 func (c *Controller) RcvsFromSelector(selector *v2beta2.LabelSelector, receiverType string) []internal.Receiver {
 
+	// The selector is compiled once, rather than for every receiver.
+	var m *v2beta2.Matcher
+	if selector != nil {
+		var err error
+		if m, err = selector.Compile(); err != nil {
+			_ = level.Error(c.logger).Log("msg", "Failed to compile receiver selector", "err", err)
+			return nil
+		}
+	}
+
 	t := &task{
 		run: func(t *task) {
 			var rcvs []internal.Receiver
@@ -661,7 +948,7 @@ func (c *Controller) RcvsFromSelector(selector *v2beta2.LabelSelector, receiverT
 						continue
 					}
 
-					if v2beta2.LabelMatchSelector(v.GetLabels(), selector) {
+					if m == nil || m.Matches(v.GetLabels()) {
 						if v.Enabled() {
 							rcv := v.Clone()
 							getMatchedConfig(rcv, c.configs)
@@ -681,6 +968,67 @@ func (c *Controller) RcvsFromSelector(selector *v2beta2.LabelSelector, receiverT
 	return val.([]internal.Receiver)
 }
 
+// RcvsFromReceiverSelector returns the receivers selected by the names, the label selector and the channels of the receiver selector.
+func (c *Controller) RcvsFromReceiverSelector(rs v2beta2.ReceiverSelector) []internal.Receiver {
+
+	var rcvs []internal.Receiver
+	if len(rs.Name) > 0 || !utils.StringIsNil(rs.RegexName) {
+		rcvs = append(rcvs, c.RcvsFromName(rs.Name, rs.RegexName, rs.Type)...)
+	}
+	if rs.Selector != nil {
+		rcvs = append(rcvs, c.RcvsFromSelector(rs.Selector, rs.Type)...)
+	}
+	rcvs = append(rcvs, c.RcvsFromTenant(rs.Channels)...)
+	if !utils.StringIsNil(rs.Schedule) {
+		rcvs = append(rcvs, c.rcvsFromSchedule(rs.Schedule, time.Now())...)
+	}
+
+	return rcvs
+}
+
+// rcvsFromSchedule returns the receivers which are on call at the time in the on-call schedule.
+func (c *Controller) rcvsFromSchedule(name string, at time.Time) []internal.Receiver {
+
+	schedule, err := c.GetOnCallSchedule(c.ctx, name)
+	if err != nil {
+		_ = level.Error(c.logger).Log("msg", "Failed to get on-call schedule", "schedule", name, "err", err)
+		return nil
+	}
+
+	if schedule == nil {
+		return nil
+	}
+
+	rs, err := oncall.OnCall(schedule, at)
+	if err != nil {
+		_ = level.Error(c.logger).Log("msg", "Failed to resolve on-call schedule", "schedule", name, "err", err)
+		return nil
+	}
+
+	if rs == nil {
+		return nil
+	}
+
+	// A participant can not reference another schedule.
+	participant := *rs
+	participant.Schedule = ""
+	return c.RcvsFromReceiverSelector(participant)
+}
+
+// GetOnCallSchedule returns the on-call schedule with the name, nil will be returned if the schedule does not exist.
+func (c *Controller) GetOnCallSchedule(ctx context.Context, name string) (*v2beta2.OnCallSchedule, error) {
+
+	schedule := &v2beta2.OnCallSchedule{}
+	if err := c.cache.Get(ctx, types.NamespacedName{Name: name}, schedule); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
 func (c *Controller) RcvsFromTenant(channels []v2beta2.Channel) []internal.Receiver {
 
 	t := &task{
@@ -949,6 +1297,14 @@ func (c *Controller) GetGroupLabels() []string {
 	return c.groupLabels
 }
 
+func (c *Controller) GetGroupWait() time.Duration {
+	return c.groupWait
+}
+
+func (c *Controller) GetGroupInterval() time.Duration {
+	return c.groupInterval
+}
+
 func (c *Controller) GetBatchMaxSize() int {
 	return c.batchMaxSize
 }
@@ -957,31 +1313,205 @@ func (c *Controller) GetBatchMaxWait() time.Duration {
 	return c.batchMaxWait.Duration
 }
 
-func (c *Controller) GetActiveSilences(ctx context.Context, tenant string) ([]v2beta2.Silence, error) {
+// GetActiveSilences returns the active silences of the tenant, the global silences will be returned if the tenant is empty.
+// The silences are read from the index maintained by the silence informer, and must not be modified.
+func (c *Controller) GetActiveSilences(_ context.Context, tenant string) ([]v2beta2.Silence, error) {
+
+	return c.silencesOfTenant(tenant, func(silence *v2beta2.Silence) bool {
+		return silence.IsActive()
+	}), nil
+}
+
+// TenantSilenceLabels returns the labels of the silences belonging to the tenant, the labels must match the tenant receiver selector
+// and must not match the global receiver selector. A matchExpression of the tenant receiver selector is satisfied by setting the
+// label to its first value if the operator is In, and by not setting the label if the operator is NotIn or DoesNotExist.
+// An error will be returned if the notification manager has not been set up, or the tenant receiver selector can not be satisfied.
+func (c *Controller) TenantSilenceLabels(tenant string) (map[string]string, error) {
+
+	type result struct {
+		labels map[string]string
+		err    error
+	}
+
+	t := &task{
+		run: func(t *task) {
+			m, err := c.tenantSilenceLabels(tenant)
+			t.done <- result{m, err}
+		},
+		done: make(chan interface{}, 1),
+	}
+
+	c.ch <- t
+	res := (<-t.done).(result)
+	return res.labels, res.err
+}
+
+func (c *Controller) tenantSilenceLabels(tenant string) (map[string]string, error) {
+
+	if c.tenantReceiverSelector == nil {
+		return nil, utils.Error("notification manager has not been set up")
+	}
+
+	m := make(map[string]string)
+	for k, v := range c.tenantReceiverSelector.MatchLabels {
+		m[k] = v
+	}
+
+	for _, req := range c.tenantReceiverSelector.MatchExpressions {
+		if req.Key == c.tenantKey {
+			continue
+		}
+
+		switch req.Operator {
+		case metav1.LabelSelectorOpIn:
+			if _, ok := m[req.Key]; !ok && len(req.Values) > 0 {
+				m[req.Key] = req.Values[0]
+			}
+		case metav1.LabelSelectorOpNotIn, metav1.LabelSelectorOpDoesNotExist:
+		default:
+			return nil, utils.Errorf("the operator %s of the tenant receiver selector is not supported by the tenant silences", req.Operator)
+		}
+	}
+	m[c.tenantKey] = tenant
+
+	if id, ok := c.tenantIDOfLabels(m); !ok || id != tenant {
+		return nil, utils.Error("the labels of the tenant silences can not be generated from the tenant receiver selector")
+	}
+
+	return m, nil
+}
+
+// GetTenantSilences returns all the silences belonging to the tenant, whether they are active or not.
+func (c *Controller) GetTenantSilences(_ context.Context, tenant string) ([]v2beta2.Silence, error) {
 
-	var selector *metav1.LabelSelector
-	// Get global silence.
 	if utils.StringIsNil(tenant) {
-		selector = c.globalReceiverSelector.DeepCopy()
-	} else {
-		// Get tenant silence.
-		selector = c.tenantReceiverSelector.DeepCopy()
-		selector.MatchLabels[c.tenantKey] = tenant
+		return nil, nil
 	}
 
-	list := &v2beta2.SilenceList{}
-	if err := c.cache.List(ctx, list, &client.ListOptions{LabelSelector: labels.SelectorFromSet(selector.MatchLabels)}); err != nil {
-		return nil, err
+	ss := c.silencesOfTenant(tenant, nil)
+	for i := range ss {
+		ss[i] = *ss[i].DeepCopy()
 	}
 
+	return ss, nil
+}
+
+// GetTenantSilence returns the silence with the name belonging to the tenant,
+// nil will be returned if the silence does not exist or does not belong to the tenant.
+func (c *Controller) GetTenantSilence(_ context.Context, tenant, name string) (*v2beta2.Silence, error) {
+
+	if utils.StringIsNil(tenant) {
+		return nil, nil
+	}
+
+	ss := c.silencesOfTenant(tenant, func(silence *v2beta2.Silence) bool {
+		return silence.Name == name
+	})
+	if len(ss) == 0 {
+		return nil, nil
+	}
+
+	return ss[0].DeepCopy(), nil
+}
+
+// Client returns the client used to write the resources.
+func (c *Controller) Client() client.Client {
+	return c.client
+}
+
+// Reader returns the cache which the resources are read from.
+func (c *Controller) Reader() client.Reader {
+	return c.cache
+}
+
+// CreateSilence creates the silence through the Kubernetes API.
+func (c *Controller) CreateSilence(ctx context.Context, silence *v2beta2.Silence) error {
+	return c.client.Create(ctx, silence)
+}
+
+// UpdateSilence updates the silence through the Kubernetes API.
+func (c *Controller) UpdateSilence(ctx context.Context, silence *v2beta2.Silence) error {
+	return c.client.Update(ctx, silence)
+}
+
+// DeleteSilence deletes the silence through the Kubernetes API.
+func (c *Controller) DeleteSilence(ctx context.Context, silence *v2beta2.Silence) error {
+	return c.client.Delete(ctx, silence)
+}
+
+// ListSilences returns all the silences sorted by name, including the archived silences.
+func (c *Controller) ListSilences() []v2beta2.Silence {
+
+	c.indexMutex.RLock()
+	defer c.indexMutex.RUnlock()
+
 	var ss []v2beta2.Silence
-	for _, silence := range list.Items {
-		if silence.IsActive() {
-			ss = append(ss, silence)
+	for _, silence := range c.silences {
+		ss = append(ss, *silence.DeepCopy())
+	}
+
+	sort.Slice(ss, func(i, j int) bool {
+		return ss[i].Name < ss[j].Name
+	})
+
+	return ss
+}
+
+// SilenceTenants returns the tenant which each silence belongs to, the tenant of a global silence is empty.
+// The silences which belong to no tenant and the archived silences are not returned.
+func (c *Controller) SilenceTenants() map[string]string {
+
+	c.indexMutex.RLock()
+	defer c.indexMutex.RUnlock()
+
+	m := make(map[string]string)
+	for tenant, silences := range c.tenantSilences {
+		for name := range silences {
+			m[name] = tenant
 		}
 	}
 
-	return ss, nil
+	return m
+}
+
+// GetActiveInhibitors returns the active inhibitors of the tenant, the global inhibitors will be returned if the tenant is empty.
+// The inhibitors are read from the index maintained by the inhibitor informer, and must not be modified.
+func (c *Controller) GetActiveInhibitors(_ context.Context, tenant string) ([]v2beta2.Inhibitor, error) {
+
+	c.indexMutex.RLock()
+	defer c.indexMutex.RUnlock()
+
+	var is []v2beta2.Inhibitor
+	for _, inhibitor := range c.tenantInhibitors[tenant] {
+		if inhibitor.IsActive() {
+			is = append(is, *inhibitor)
+		}
+	}
+
+	sort.Slice(is, func(i, j int) bool {
+		return is[i].Name < is[j].Name
+	})
+
+	return is, nil
+}
+
+// GetActiveEscalationPolicy returns the escalation policy with the name, nil will be returned if the escalation policy
+// does not exist or is disabled.
+func (c *Controller) GetActiveEscalationPolicy(ctx context.Context, name string) (*v2beta2.EscalationPolicy, error) {
+
+	policy := &v2beta2.EscalationPolicy{}
+	if err := c.cache.Get(ctx, types.NamespacedName{Name: name}, policy); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if !policy.IsActive() {
+		return nil, nil
+	}
+
+	return policy, nil
 }
 
 func (c *Controller) GetActiveRouters(ctx context.Context) ([]v2beta2.Router, error) {
@@ -991,20 +1521,183 @@ func (c *Controller) GetActiveRouters(ctx context.Context) ([]v2beta2.Router, er
 		return nil, err
 	}
 
+	now := time.Now()
 	var rs []v2beta2.Router
 	for _, router := range list.Items {
-		if router.Spec.Enabled == nil || *router.Spec.Enabled {
-			rs = append(rs, router)
+		if router.Spec.Enabled != nil && !*router.Spec.Enabled {
+			continue
+		}
+
+		if router.Spec.ActiveTimeIntervals != nil {
+			active, err := c.inTimeIntervals(ctx, router.Spec.ActiveTimeIntervals, now)
+			if err != nil {
+				_ = level.Error(c.logger).Log("msg", "Failed to evaluate active time intervals", "router", router.Name, "err", err)
+				continue
+			}
+			if !active {
+				continue
+			}
+		}
+
+		if router.Spec.InactiveTimeIntervals != nil {
+			inactive, err := c.inTimeIntervals(ctx, router.Spec.InactiveTimeIntervals, now)
+			// The router stays active if the inactive time intervals can not be evaluated, the alerts should not be lost because of an error.
+			if err != nil {
+				_ = level.Error(c.logger).Log("msg", "Failed to evaluate inactive time intervals", "router", router.Name, "err", err)
+			} else if inactive {
+				continue
+			}
 		}
+
+		rs = append(rs, router)
 	}
 
+	sort.SliceStable(rs, func(i, j int) bool {
+		if rs[i].Spec.Priority != rs[j].Spec.Priority {
+			return rs[i].Spec.Priority > rs[j].Spec.Priority
+		}
+		return rs[i].Name < rs[j].Name
+	})
+
 	return rs, nil
 }
 
+// inTimeIntervals returns true if the time matches any of the inline time periods or the TimeInterval resources.
+func (c *Controller) inTimeIntervals(ctx context.Context, intervals *v2beta2.TimeIntervals, at time.Time) (bool, error) {
+
+	for _, p := range intervals.Periods {
+		ok, err := p.Contains(at)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+
+	for _, name := range intervals.Names {
+		ti := &v2beta2.TimeInterval{}
+		if err := c.cache.Get(ctx, types.NamespacedName{Name: name}, ti); err != nil {
+			if errors.IsNotFound(err) {
+				_ = level.Warn(c.logger).Log("msg", "TimeInterval not found", "name", name)
+				continue
+			}
+			return false, err
+		}
+
+		ok, err := ti.Contains(at)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func (c *Controller) GetRoutePolicy() string {
 	return c.routePolicy
 }
 
+func (c *Controller) GetRepeatInterval() time.Duration {
+	return c.repeatInterval
+}
+
+func (c *Controller) GetRelabelConfigs() []v2beta2.RelabelConfig {
+	return c.relabelConfigs
+}
+
+func (c *Controller) GetEnrichment() *v2beta2.Enrichment {
+	return c.enrichment
+}
+
+// GetNamespace returns the namespace from the cache, nil will be returned if the namespace does not exist.
+func (c *Controller) GetNamespace(ctx context.Context, name string) (*v1.Namespace, error) {
+
+	ns := &v1.Namespace{}
+	if err := c.cache.Get(ctx, types.NamespacedName{Name: name}, ns); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return ns, nil
+}
+
+// GetPod returns the pod from the cache, nil will be returned if the pod does not exist.
+func (c *Controller) GetPod(ctx context.Context, namespace, name string) (*v1.Pod, error) {
+
+	pod := &v1.Pod{}
+	if err := c.cache.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, pod); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return pod, nil
+}
+
+// GetWorkload returns the workload which the pod belongs to, it can be a Deployment, StatefulSet, DaemonSet, Job or CronJob.
+// The ReplicaSet which is not controlled by a Deployment and the Job which is not controlled by a CronJob will be treated as the workload.
+// Nil will be returned if the pod is not controlled by a workload.
+func (c *Controller) GetWorkload(ctx context.Context, pod *v1.Pod) (client.Object, error) {
+
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return nil, nil
+	}
+
+	var obj client.Object
+	switch owner.Kind {
+	case "ReplicaSet":
+		obj = &appsv1.ReplicaSet{}
+	case "StatefulSet":
+		obj = &appsv1.StatefulSet{}
+	case "DaemonSet":
+		obj = &appsv1.DaemonSet{}
+	case "Job":
+		obj = &batchv1.Job{}
+	default:
+		return nil, nil
+	}
+
+	if err := c.cache.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: owner.Name}, obj); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// Find the Deployment of the ReplicaSet, and the CronJob of the Job.
+	owner = metav1.GetControllerOf(obj)
+	if owner == nil {
+		return obj, nil
+	}
+
+	var parent client.Object
+	switch owner.Kind {
+	case "Deployment":
+		parent = &appsv1.Deployment{}
+	case "CronJob":
+		parent = &batchv1.CronJob{}
+	default:
+		return obj, nil
+	}
+
+	if err := c.cache.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: owner.Name}, parent); err != nil {
+		if errors.IsNotFound(err) {
+			return obj, nil
+		}
+		return nil, err
+	}
+
+	return parent, nil
+}
+
 func (c *Controller) GetConfigmap(configmaps ...*v2beta2.ConfigmapKeySelector) ([]string, error) {
 	if len(configmaps) == 0 {
 		return nil, nil
@@ -1138,6 +1831,65 @@ func (c *Controller) GetCluster() string {
 	return constants.DefaultClusterName
 }
 
+// GetRetryPolicy returns the retry policy of the receiver type.
+// If the receiver type dose not setup retry policy, the global retry policy will be returned.
+func (c *Controller) GetRetryPolicy(receiverType string) *v2beta2.RetryPolicy {
+	opts := c.ReceiverOpts
+	if opts == nil {
+		return nil
+	}
+
+	var policy *v2beta2.RetryPolicy
+	switch receiverType {
+	case constants.DingTalk:
+		if opts.DingTalk != nil {
+			policy = opts.DingTalk.Retry
+		}
+	case constants.Discord:
+		if opts.Discord != nil {
+			policy = opts.Discord.Retry
+		}
+	case constants.Email:
+		if opts.Email != nil {
+			policy = opts.Email.Retry
+		}
+	case constants.Feishu:
+		if opts.Feishu != nil {
+			policy = opts.Feishu.Retry
+		}
+	case constants.Pushover:
+		if opts.Pushover != nil {
+			policy = opts.Pushover.Retry
+		}
+	case constants.Slack:
+		if opts.Slack != nil {
+			policy = opts.Slack.Retry
+		}
+	case constants.SMS:
+		if opts.Sms != nil {
+			policy = opts.Sms.Retry
+		}
+	case constants.Telegram:
+		if opts.Telegram != nil {
+			policy = opts.Telegram.Retry
+		}
+	case constants.Webhook:
+		if opts.Webhook != nil {
+			policy = opts.Webhook.Retry
+		}
+	case constants.WeChat:
+		if opts.Wechat != nil {
+			policy = opts.Wechat.Retry
+		}
+	}
+
+	if policy == nil && opts.Global != nil {
+		policy = opts.Global.Retry
+	}
+
+	return policy
+}
+
 func (c *Controller) getClusterFromAnnotation() string {
 	ns := &v1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,394 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newTestController returns a controller which runs the tasks without watching the Kubernetes resources.
+func newTestController(t *testing.T) *Controller {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	c := &Controller{
+		logger:                 log.NewNopLogger(),
+		ctx:                    ctx,
+		tenantKey:              "user",
+		globalReceiverSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"type": "global"}},
+		tenantReceiverSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "type", Operator: metav1.LabelSelectorOpIn, Values: []string{"tenant", "user"}},
+			},
+		},
+		silences:         make(map[string]*v2beta2.Silence),
+		tenantSilences:   make(map[string]map[string]*v2beta2.Silence),
+		inhibitors:       make(map[string]*v2beta2.Inhibitor),
+		tenantInhibitors: make(map[string]map[string]*v2beta2.Inhibitor),
+		ch:               make(chan *task),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case t := <-c.ch:
+				t.run(t)
+			}
+		}
+	}()
+
+	return c
+}
+
+// change applies the change of the resource, and waits until it has been applied.
+func change(c *Controller, obj interface{}, op string, run func(t *task)) {
+	t := &task{
+		op:   op,
+		obj:  obj,
+		run:  run,
+		done: make(chan interface{}, 1),
+	}
+
+	c.ch <- t
+	<-t.done
+}
+
+func TestTenantIDOfLabels(t *testing.T) {
+	c := newTestController(t)
+
+	tests := []struct {
+		labels map[string]string
+		tenant string
+		ok     bool
+	}{
+		{map[string]string{"type": "global"}, "", true},
+		// The tenant selector only has matchExpressions.
+		{map[string]string{"type": "tenant", "user": "admin"}, "admin", true},
+		{map[string]string{"type": "user", "user": "admin"}, "admin", true},
+		{map[string]string{"type": "other", "user": "admin"}, "", false},
+		{map[string]string{"type": "tenant"}, "", false},
+	}
+
+	for _, test := range tests {
+		tenant, ok := c.tenantIDOfLabels(test.labels)
+		if tenant != test.tenant || ok != test.ok {
+			t.Fatalf("labels %v: expected (%q, %v), got (%q, %v)", test.labels, test.tenant, test.ok, tenant, ok)
+		}
+	}
+}
+
+func TestInhibitorIndex(t *testing.T) {
+	c := newTestController(t)
+	disabled := false
+
+	newInhibitor := func(name string, labels map[string]string, enabled *bool) *v2beta2.Inhibitor {
+		return &v2beta2.Inhibitor{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec:       v2beta2.InhibitorSpec{Enabled: enabled},
+		}
+	}
+
+	change(c, newInhibitor("global", map[string]string{"type": "global"}, nil), opAdd, c.inhibitorChanged)
+	change(c, newInhibitor("b", map[string]string{"type": "tenant", "user": "admin"}, nil), opAdd, c.inhibitorChanged)
+	change(c, newInhibitor("a", map[string]string{"type": "tenant", "user": "admin"}, nil), opAdd, c.inhibitorChanged)
+	change(c, newInhibitor("disabled", map[string]string{"type": "tenant", "user": "admin"}, &disabled), opAdd, c.inhibitorChanged)
+
+	is, _ := c.GetActiveInhibitors(context.Background(), "admin")
+	if len(is) != 2 || is[0].Name != "a" || is[1].Name != "b" {
+		t.Fatalf("expected the active inhibitors of admin sorted by name, got %v", is)
+	}
+
+	is, _ = c.GetActiveInhibitors(context.Background(), "")
+	if len(is) != 1 || is[0].Name != "global" {
+		t.Fatalf("expected the global inhibitor, got %v", is)
+	}
+
+	// The inhibitor moves to another tenant.
+	change(c, newInhibitor("a", map[string]string{"type": "tenant", "user": "test"}, nil), opUpdate, c.inhibitorChanged)
+	change(c, newInhibitor("b", nil, nil), opDel, c.inhibitorChanged)
+
+	if is, _ = c.GetActiveInhibitors(context.Background(), "admin"); len(is) != 0 {
+		t.Fatalf("expected no inhibitors of admin, got %v", is)
+	}
+	if is, _ = c.GetActiveInhibitors(context.Background(), "test"); len(is) != 1 {
+		t.Fatalf("expected the inhibitor of test, got %v", is)
+	}
+}
+
+func TestSilenceIndex(t *testing.T) {
+	c := newTestController(t)
+	disabled := false
+
+	newSilence := func(name string, labels map[string]string, enabled *bool) *v2beta2.Silence {
+		return &v2beta2.Silence{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec:       v2beta2.SilenceSpec{Enabled: enabled},
+		}
+	}
+
+	change(c, newSilence("global", map[string]string{"type": "global"}, nil), opAdd, c.silenceChanged)
+	change(c, newSilence("b", map[string]string{"type": "tenant", "user": "admin"}, nil), opAdd, c.silenceChanged)
+	change(c, newSilence("a", map[string]string{"type": "tenant", "user": "admin"}, nil), opAdd, c.silenceChanged)
+	change(c, newSilence("disabled", map[string]string{"type": "tenant", "user": "admin"}, &disabled), opAdd, c.silenceChanged)
+	change(c, newSilence("archived", map[string]string{"type": "tenant", "user": "admin", v2beta2.SilenceArchivedLabel: "true"}, nil), opAdd, c.silenceChanged)
+
+	ss, _ := c.GetActiveSilences(context.Background(), "admin")
+	if len(ss) != 2 || ss[0].Name != "a" || ss[1].Name != "b" {
+		t.Fatalf("expected the active silences of admin sorted by name, got %v", ss)
+	}
+	// The disabled silences belong to the tenant as well, the archived silences are ignored.
+	if ss, _ = c.GetTenantSilences(context.Background(), "admin"); len(ss) != 3 {
+		t.Fatalf("expected 3 silences of admin, got %v", ss)
+	}
+	if ss, _ = c.GetActiveSilences(context.Background(), ""); len(ss) != 1 || ss[0].Name != "global" {
+		t.Fatalf("expected the global silence, got %v", ss)
+	}
+	if ss = c.ListSilences(); len(ss) != 5 {
+		t.Fatalf("expected all the 5 silences, got %v", ss)
+	}
+
+	// The silence is updated in place.
+	change(c, newSilence("b", map[string]string{"type": "tenant", "user": "admin"}, &disabled), opUpdate, c.silenceChanged)
+	if ss, _ = c.GetActiveSilences(context.Background(), "admin"); len(ss) != 1 || ss[0].Name != "a" {
+		t.Fatalf("expected the updated silence to be inactive, got %v", ss)
+	}
+
+	// The silence moves to another tenant.
+	change(c, newSilence("a", map[string]string{"type": "tenant", "user": "test"}, nil), opUpdate, c.silenceChanged)
+	if s, _ := c.GetTenantSilence(context.Background(), "admin", "a"); s != nil {
+		t.Fatalf("expected the silence a not to belong to admin, got %v", s)
+	}
+	if s, _ := c.GetTenantSilence(context.Background(), "test", "a"); s == nil {
+		t.Fatal("expected the silence a to belong to test")
+	}
+
+	// The silence is deleted.
+	change(c, newSilence("b", nil, nil), opDel, c.silenceChanged)
+	if ss, _ = c.GetTenantSilences(context.Background(), "admin"); len(ss) != 1 || ss[0].Name != "disabled" {
+		t.Fatalf("expected the disabled silence of admin, got %v", ss)
+	}
+	if ss = c.ListSilences(); len(ss) != 4 {
+		t.Fatalf("expected 4 silences, got %v", ss)
+	}
+
+	// The silences are read without going through the task channel, so they can be read while a task is running.
+	done := make(chan struct{})
+	c.ch <- &task{run: func(_ *task) {
+		if ss, _ := c.GetActiveSilences(context.Background(), "test"); len(ss) != 1 {
+			t.Errorf("expected the active silence of test, got %v", ss)
+		}
+		close(done)
+	}}
+	<-done
+}
+
+func TestGetActiveRoutersTimeIntervals(t *testing.T) {
+	newRouter := func(name string, active, inactive *v2beta2.TimeIntervals) *v2beta2.Router {
+		return &v2beta2.Router{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v2beta2.RouterSpec{
+				ActiveTimeIntervals:   active,
+				InactiveTimeIntervals: inactive,
+			},
+		}
+	}
+	// An empty period matches any time.
+	always := &v2beta2.TimeIntervals{Periods: []v2beta2.TimePeriod{{}}}
+	broken := &v2beta2.TimeIntervals{Periods: []v2beta2.TimePeriod{{Location: "Mars/Olympus"}}}
+	missing := &v2beta2.TimeIntervals{Names: []string{"missing"}}
+
+	c := &Controller{
+		logger: log.NewNopLogger(),
+		cache: newTestCache(t,
+			newRouter("no-intervals", nil, nil),
+			newRouter("active", always, nil),
+			newRouter("inactive", nil, always),
+			newRouter("broken-active", broken, nil),
+			// The inactive time intervals fail open, the alerts should not be lost because of an error.
+			newRouter("broken-inactive", nil, broken),
+			newRouter("missing-inactive", nil, missing),
+		),
+	}
+
+	routers, err := c.GetActiveRouters(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, r := range routers {
+		names = append(names, r.Name)
+	}
+	expected := []string{"active", "broken-inactive", "missing-inactive", "no-intervals"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, names)
+		}
+	}
+}
+
+func TestGetActiveRoutersOrder(t *testing.T) {
+	newRouter := func(name string, priority int32) *v2beta2.Router {
+		return &v2beta2.Router{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v2beta2.RouterSpec{Priority: priority},
+		}
+	}
+
+	c := &Controller{
+		logger: log.NewNopLogger(),
+		cache: newTestCache(t,
+			newRouter("b", 0),
+			newRouter("low", -1),
+			newRouter("a", 0),
+			newRouter("high-b", 10),
+			newRouter("high-a", 10),
+		),
+	}
+
+	routers, err := c.GetActiveRouters(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The routers with higher priority come first, the routers with the same priority are sorted by name.
+	var names []string
+	for _, r := range routers {
+		names = append(names, r.Name)
+	}
+	expected := []string{"high-a", "high-b", "a", "b", "low"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, names)
+		}
+	}
+}
+
+func TestTenantSilenceLabels(t *testing.T) {
+	c := newTestController(t)
+
+	set, err := c.TenantSilenceLabels("admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 2 || set["type"] != "tenant" || set["user"] != "admin" {
+		t.Fatalf("unexpected labels %v", set)
+	}
+	if tenant, ok := c.tenantIDOfLabels(set); !ok || tenant != "admin" {
+		t.Fatalf("expected the labels to belong to the tenant, got (%q, %v)", tenant, ok)
+	}
+
+	tests := []struct {
+		name     string
+		selector *metav1.LabelSelector
+		labels   map[string]string
+	}{
+		{"not set up", nil, nil},
+		{
+			"match labels",
+			&metav1.LabelSelector{MatchLabels: map[string]string{"type": "tenant"}},
+			map[string]string{"type": "tenant", "user": "admin"},
+		},
+		{
+			"not in and does not exist",
+			&metav1.LabelSelector{
+				MatchLabels: map[string]string{"type": "tenant"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "scope", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"system"}},
+					{Key: "deprecated", Operator: metav1.LabelSelectorOpDoesNotExist},
+					{Key: "user", Operator: metav1.LabelSelectorOpExists},
+				},
+			},
+			map[string]string{"type": "tenant", "user": "admin"},
+		},
+		{
+			"exists can not be satisfied",
+			&metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "type", Operator: metav1.LabelSelectorOpExists},
+				},
+			},
+			nil,
+		},
+		{
+			"conflicts with the expressions",
+			&metav1.LabelSelector{
+				MatchLabels: map[string]string{"type": "tenant"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "type", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"tenant"}},
+				},
+			},
+			nil,
+		},
+		{
+			// The labels would be matched by the global receiver selector.
+			"matches the global selector",
+			&metav1.LabelSelector{MatchLabels: map[string]string{"type": "global"}},
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		c.tenantReceiverSelector = test.selector
+		set, err := c.TenantSilenceLabels("admin")
+		if test.labels == nil {
+			if err == nil {
+				t.Fatalf("%s: expected an error, got %v", test.name, set)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		if len(set) != len(test.labels) {
+			t.Fatalf("%s: expected %v, got %v", test.name, test.labels, set)
+		}
+		for k, v := range test.labels {
+			if set[k] != v {
+				t.Fatalf("%s: expected %v, got %v", test.name, test.labels, set)
+			}
+		}
+	}
+}
+
+func TestSpecChanged(t *testing.T) {
+	old := &v2beta2.Silence{ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 1, ResourceVersion: "1", Labels: map[string]string{"a": "b"}}}
+
+	written := old.DeepCopy()
+	written.ResourceVersion = "2"
+	written.Status.Active = true
+	if specChanged(old, written) {
+		t.Fatal("expected the status update to be ignored")
+	}
+
+	updated := written.DeepCopy()
+	updated.Generation = 2
+	if !specChanged(old, updated) {
+		t.Fatal("expected the spec update to be handled")
+	}
+
+	relabeled := written.DeepCopy()
+	relabeled.Labels = map[string]string{"a": "c"}
+	if !specChanged(old, relabeled) {
+		t.Fatal("expected the label update to be handled")
+	}
+
+	// The objects without generation are always handled.
+	if !specChanged(&v2beta2.Silence{}, &v2beta2.Silence{}) {
+		t.Fatal("expected the objects without generation to be handled")
+	}
+}
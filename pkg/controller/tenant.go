@@ -0,0 +1,379 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/constants"
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const (
+	// How long to look up the namespaces one by one before probing the batch API of the sidecar again,
+	// the sidecar may be upgraded to support the batch API.
+	batchProbeInterval = 10 * time.Minute
+)
+
+var (
+	tenantCacheTTL         *time.Duration
+	tenantNegativeCacheTTL *time.Duration
+	tenantTimeout          *time.Duration
+	tenantConcurrency      *int
+)
+
+func init() {
+	tenantCacheTTL = kingpin.Flag(
+		"tenant.cacheTTL",
+		"How long to cache the tenants of a namespace resolved by the tenant sidecar, 0 means never caching",
+	).Default("1m").Duration()
+	tenantNegativeCacheTTL = kingpin.Flag(
+		"tenant.negativeCacheTTL",
+		"How long to cache the namespace which has no tenants or failed to be resolved by the tenant sidecar, 0 means never caching",
+	).Default("10s").Duration()
+	tenantTimeout = kingpin.Flag(
+		"tenant.timeout",
+		"Timeout for each request to the tenant sidecar, 0 means no timeout",
+	).Default("5s").Duration()
+	tenantConcurrency = kingpin.Flag(
+		"tenant.concurrency",
+		"The maximum number of the concurrent requests to the tenant sidecar when the namespaces are looked up one by one, 0 means no limit",
+	).Default("10").Int()
+}
+
+// ClusterNamespace identifies a namespace in a cluster.
+type ClusterNamespace struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace"`
+}
+
+// tenantsOfNamespace is an item of the response of the batch tenant lookup.
+type tenantsOfNamespace struct {
+	ClusterNamespace
+	Tenants []string `json:"tenants"`
+}
+
+type tenantEntry struct {
+	tenants   []string
+	err       error
+	expiresAt time.Time
+}
+
+// tenantCall is a lookup in flight, the lookups of the same namespace wait for it rather than requesting the sidecar again.
+type tenantCall struct {
+	done    chan struct{}
+	tenants []string
+	err     error
+}
+
+// tenantCache caches the tenants of the namespaces resolved by the tenant sidecar, and coalesces the concurrent lookups.
+type tenantCache struct {
+	mutex   sync.Mutex
+	entries map[ClusterNamespace]*tenantEntry
+	calls   map[ClusterNamespace]*tenantCall
+	client  *http.Client
+	// The URLs of the lookup API and the batch lookup API of the sidecar.
+	url      string
+	batchURL string
+	// The semaphore limits the concurrent requests of all the lookups when the namespaces are looked up one by one,
+	// nil means no limit.
+	sem chan struct{}
+	// The batch API will not be used until the time, because the sidecar did not support it.
+	batchUnsupportedUntil time.Time
+}
+
+func newTenantCache() *tenantCache {
+	tc := &tenantCache{
+		entries:  make(map[ClusterNamespace]*tenantEntry),
+		calls:    make(map[ClusterNamespace]*tenantCall),
+		client:   &http.Client{Timeout: *tenantTimeout},
+		url:      tenantSidecarURL,
+		batchURL: tenantsSidecarURL,
+	}
+	if *tenantConcurrency > 0 {
+		tc.sem = make(chan struct{}, *tenantConcurrency)
+	}
+
+	return tc
+}
+
+// get returns the tenants of the namespaces, the namespaces which failed to be resolved are absent from the result,
+// and the first error is returned.
+func (tc *tenantCache) get(nss []ClusterNamespace) (map[ClusterNamespace][]string, error) {
+
+	res := make(map[ClusterNamespace][]string)
+	calls := make(map[ClusterNamespace]*tenantCall)
+	var misses []ClusterNamespace
+	var err error
+
+	now := time.Now()
+	tc.mutex.Lock()
+	for _, ns := range nss {
+		if _, ok := calls[ns]; ok {
+			continue
+		}
+
+		if e, ok := tc.entries[ns]; ok && now.Before(e.expiresAt) {
+			if e.err != nil {
+				err = e.err
+			} else {
+				res[ns] = e.tenants
+			}
+			continue
+		}
+
+		call, ok := tc.calls[ns]
+		if !ok {
+			call = &tenantCall{done: make(chan struct{})}
+			tc.calls[ns] = call
+			misses = append(misses, ns)
+		}
+		calls[ns] = call
+	}
+	tc.mutex.Unlock()
+
+	if len(misses) > 0 {
+		tc.fetch(misses, calls)
+	}
+
+	for ns, call := range calls {
+		<-call.done
+		if call.err != nil {
+			err = call.err
+			continue
+		}
+		res[ns] = call.tenants
+	}
+
+	return res, err
+}
+
+// fetch resolves the namespaces by the sidecar, caches the results and finishes the calls.
+func (tc *tenantCache) fetch(nss []ClusterNamespace, calls map[ClusterNamespace]*tenantCall) {
+
+	// The timeout of each request is set in the client.
+	tenants, errs := tc.request(context.Background(), nss)
+
+	now := time.Now()
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	for _, ns := range nss {
+		call := calls[ns]
+		call.tenants, call.err = tenants[ns], errs[ns]
+
+		ttl := *tenantCacheTTL
+		if call.err != nil || len(call.tenants) == 0 {
+			ttl = *tenantNegativeCacheTTL
+		}
+		if ttl > 0 {
+			tc.entries[ns] = &tenantEntry{
+				tenants:   call.tenants,
+				err:       call.err,
+				expiresAt: now.Add(ttl),
+			}
+		}
+
+		delete(tc.calls, ns)
+		close(call.done)
+	}
+
+	// Remove the expired entries.
+	for ns, e := range tc.entries {
+		if !now.Before(e.expiresAt) {
+			delete(tc.entries, ns)
+		}
+	}
+}
+
+// request looks up the tenants of the namespaces with the batch API of the sidecar,
+// and falls back to looking up the namespaces one by one if the sidecar does not support the batch API.
+// The batch API is probed again after the batchProbeInterval.
+func (tc *tenantCache) request(ctx context.Context, nss []ClusterNamespace) (map[ClusterNamespace][]string, map[ClusterNamespace]error) {
+
+	tenants := make(map[ClusterNamespace][]string)
+	errs := make(map[ClusterNamespace]error)
+
+	tc.mutex.Lock()
+	batchUnsupported := time.Now().Before(tc.batchUnsupportedUntil)
+	tc.mutex.Unlock()
+
+	if !batchUnsupported {
+		var items []tenantsOfNamespace
+		status, err := tc.do(ctx, http.MethodPost, tc.batchURL, nss, &items)
+		if err == nil {
+			for _, item := range items {
+				tenants[item.ClusterNamespace] = item.Tenants
+			}
+			return tenants, errs
+		}
+
+		if status != http.StatusNotFound && status != http.StatusMethodNotAllowed {
+			for _, ns := range nss {
+				errs[ns] = err
+			}
+			return tenants, errs
+		}
+
+		tc.mutex.Lock()
+		tc.batchUnsupportedUntil = time.Now().Add(batchProbeInterval)
+		tc.mutex.Unlock()
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, ns := range nss {
+		wg.Add(1)
+		go func(ns ClusterNamespace) {
+			defer wg.Done()
+
+			// Limit the concurrent requests, the sidecar may have to resolve many namespaces at once.
+			if tc.sem != nil {
+				tc.sem <- struct{}{}
+				defer func() {
+					<-tc.sem
+				}()
+			}
+
+			p := make(map[string]string)
+			p[constants.Cluster] = ns.Cluster
+			p[constants.Namespace] = ns.Namespace
+			u, err := utils.UrlWithParameters(tc.url, p)
+			if err != nil {
+				mutex.Lock()
+				errs[ns] = err
+				mutex.Unlock()
+				return
+			}
+
+			var res []string
+			status, err := tc.do(ctx, http.MethodGet, u, nil, &res)
+			mutex.Lock()
+			defer mutex.Unlock()
+			// The sidecar responds 404 if the namespace has no tenants.
+			if err != nil && status != http.StatusNotFound {
+				errs[ns] = err
+				return
+			}
+			tenants[ns] = res
+		}(ns)
+	}
+	wg.Wait()
+
+	return tenants, errs
+}
+
+// do sends the request to the sidecar, and decodes the response into res.
+func (tc *tenantCache) do(ctx context.Context, method, url string, body, res interface{}) (int, error) {
+
+	var reader io.Reader
+	if body != nil {
+		bs, err := utils.JsonMarshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(bs)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	resp, err := tc.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("%d, %s", resp.StatusCode, string(bs))
+	}
+
+	return resp.StatusCode, utils.JsonUnmarshal(bs, res)
+}
+
+// tenantsFromNamespaces returns the tenants which need to receive the notifications in the namespaces.
+// The namespaces which failed to be resolved are absent from the result.
+func (c *Controller) tenantsFromNamespaces(nss []ClusterNamespace) map[ClusterNamespace][]string {
+
+	// Use namespace as TenantID directly if tenantSidecar not provided.
+	if !c.tenantSidecar {
+		res := make(map[ClusterNamespace][]string)
+		for _, ns := range nss {
+			res[ns] = []string{ns.Namespace}
+		}
+		return res
+	}
+
+	res, err := c.tenants.get(nss)
+	if err != nil {
+		_ = level.Error(c.logger).Log("msg", "get tenantID error", "err", err)
+	}
+
+	for ns, tenants := range res {
+		_ = level.Debug(c.logger).Log("msg", "get tenants from namespace", "cluster", ns.Cluster, "namespace", ns.Namespace, "tenant", utils.ArrayToString(tenants, ","))
+	}
+
+	return res
+}
+
+// RcvsFromNamespaces returns the receivers which need to receive the notifications in each namespace,
+// the tenants of all the namespaces are resolved at once. Only the global receivers are returned for an empty namespace.
+func (c *Controller) RcvsFromNamespaces(nss []ClusterNamespace) map[ClusterNamespace][]internal.Receiver {
+
+	var lookup []ClusterNamespace
+	for _, ns := range nss {
+		if len(ns.Namespace) > 0 {
+			lookup = append(lookup, ns)
+		}
+	}
+
+	tenantIDs := make(map[ClusterNamespace][]string)
+	if len(lookup) > 0 {
+		tenantIDs = c.tenantsFromNamespaces(lookup)
+	}
+
+	t := &task{
+		run: func(t *task) {
+			res := make(map[ClusterNamespace][]internal.Receiver)
+			for _, ns := range nss {
+				// Global receiver should receive all notifications.
+				tenants := append([]string{globalTenantID}, tenantIDs[ns]...)
+				var rcvs []internal.Receiver
+				for _, tenant := range tenants {
+					for _, rcv := range c.receivers[tenant] {
+						if rcv.Enabled() {
+							rcv = rcv.Clone()
+							getMatchedConfig(rcv, c.configs)
+							rcvs = append(rcvs, rcv)
+						}
+					}
+				}
+				res[ns] = rcvs
+			}
+
+			t.done <- res
+		},
+		done: make(chan interface{}, 1),
+	}
+
+	c.ch <- t
+	val := <-t.done
+	return val.(map[ClusterNamespace][]internal.Receiver)
+}
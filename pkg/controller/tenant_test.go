@@ -0,0 +1,305 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeSidecar is a tenant sidecar, the tenant of a namespace is the namespace itself,
+// except the namespaces in noTenants.
+type fakeSidecar struct {
+	batchSupported int32
+	failed         int32
+	// The requests are blocked until the channel is closed if set.
+	block chan struct{}
+
+	noTenants map[string]bool
+
+	requests      int32
+	batchRequests int32
+	inFlight      int32
+	maxInFlight   int32
+}
+
+func (s *fakeSidecar) tenants(ns string) []string {
+	if s.noTenants[ns] {
+		return nil
+	}
+	return []string{ns}
+}
+
+func (s *fakeSidecar) serve(w http.ResponseWriter, r *http.Request) {
+	inFlight := atomic.AddInt32(&s.inFlight, 1)
+	defer atomic.AddInt32(&s.inFlight, -1)
+	for {
+		max := atomic.LoadInt32(&s.maxInFlight)
+		if inFlight <= max || atomic.CompareAndSwapInt32(&s.maxInFlight, max, inFlight) {
+			break
+		}
+	}
+
+	if r.URL.Path == "/api/v2/tenants" {
+		atomic.AddInt32(&s.batchRequests, 1)
+	} else {
+		atomic.AddInt32(&s.requests, 1)
+	}
+
+	if s.block != nil {
+		<-s.block
+	}
+
+	if atomic.LoadInt32(&s.failed) == 1 {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/api/v2/tenants":
+		if atomic.LoadInt32(&s.batchSupported) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var nss []ClusterNamespace
+		_ = json.NewDecoder(r.Body).Decode(&nss)
+		res := make([]tenantsOfNamespace, 0)
+		for _, ns := range nss {
+			res = append(res, tenantsOfNamespace{ClusterNamespace: ns, Tenants: s.tenants(ns.Namespace)})
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	case "/api/v2/tenant":
+		tenants := s.tenants(r.URL.Query().Get("namespace"))
+		if len(tenants) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(tenants)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestTenantCache(t *testing.T, s *fakeSidecar, ttl, negativeTTL time.Duration) *tenantCache {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(server.Close)
+
+	cacheTTL, negativeCacheTTL := *tenantCacheTTL, *tenantNegativeCacheTTL
+	*tenantCacheTTL, *tenantNegativeCacheTTL = ttl, negativeTTL
+	t.Cleanup(func() {
+		*tenantCacheTTL, *tenantNegativeCacheTTL = cacheTTL, negativeCacheTTL
+	})
+
+	return &tenantCache{
+		entries:  make(map[ClusterNamespace]*tenantEntry),
+		calls:    make(map[ClusterNamespace]*tenantCall),
+		client:   server.Client(),
+		url:      server.URL + "/api/v2/tenant",
+		batchURL: server.URL + "/api/v2/tenants",
+		sem:      make(chan struct{}, 2),
+	}
+}
+
+func namespaces(names ...string) []ClusterNamespace {
+	var nss []ClusterNamespace
+	for _, name := range names {
+		nss = append(nss, ClusterNamespace{Cluster: "host", Namespace: name})
+	}
+	return nss
+}
+
+func TestTenantCacheTTL(t *testing.T) {
+	s := &fakeSidecar{batchSupported: 1}
+	tc := newTestTenantCache(t, s, time.Hour, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		res, err := tc.get(namespaces("a", "b"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != 2 || res[ClusterNamespace{"host", "a"}][0] != "a" || res[ClusterNamespace{"host", "b"}][0] != "b" {
+			t.Fatalf("unexpected tenants %v", res)
+		}
+	}
+	if n := atomic.LoadInt32(&s.batchRequests); n != 1 {
+		t.Fatalf("expected 1 request within the TTL, got %d", n)
+	}
+
+	// Only the expired namespace is looked up again.
+	tc.mutex.Lock()
+	tc.entries[ClusterNamespace{"host", "a"}].expiresAt = time.Now().Add(-time.Second)
+	tc.mutex.Unlock()
+	if _, err := tc.get(namespaces("a", "b")); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&s.batchRequests); n != 2 {
+		t.Fatalf("expected the expired namespace to be looked up again, got %d requests", n)
+	}
+
+	// Nothing is cached if the TTL is 0.
+	*tenantCacheTTL = 0
+	tc.entries = make(map[ClusterNamespace]*tenantEntry)
+	for i := 0; i < 2; i++ {
+		if _, err := tc.get(namespaces("c")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(&s.batchRequests); n != 4 {
+		t.Fatalf("expected every lookup to request the sidecar, got %d requests", n)
+	}
+}
+
+func TestTenantCacheNegative(t *testing.T) {
+	s := &fakeSidecar{batchSupported: 1, noTenants: map[string]bool{"empty": true}}
+	tc := newTestTenantCache(t, s, time.Hour, time.Minute)
+
+	res, err := tc.get(namespaces("empty"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenants, ok := res[ClusterNamespace{"host", "empty"}]; !ok || len(tenants) != 0 {
+		t.Fatalf("expected no tenants, got %v", res)
+	}
+	e := tc.entries[ClusterNamespace{"host", "empty"}]
+	if e == nil || e.expiresAt.After(time.Now().Add(time.Minute)) {
+		t.Fatal("expected the namespace without tenants to be cached for the negative TTL")
+	}
+
+	// The failed lookups are cached for the negative TTL, and the cached error is returned.
+	atomic.StoreInt32(&s.failed, 1)
+	for i := 0; i < 2; i++ {
+		res, err := tc.get(namespaces("failed"))
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if _, ok := res[ClusterNamespace{"host", "failed"}]; ok {
+			t.Fatal("expected the failed namespace to be absent from the result")
+		}
+	}
+	if n := atomic.LoadInt32(&s.batchRequests); n != 2 {
+		t.Fatalf("expected the failed lookup to be cached, got %d requests", n)
+	}
+	e = tc.entries[ClusterNamespace{"host", "failed"}]
+	if e == nil || e.expiresAt.After(time.Now().Add(time.Minute)) {
+		t.Fatal("expected the failed namespace to be cached for the negative TTL")
+	}
+
+	// The failed lookups are not cached if the negative TTL is 0.
+	*tenantNegativeCacheTTL = 0
+	if _, err := tc.get(namespaces("failed2")); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, ok := tc.entries[ClusterNamespace{"host", "failed2"}]; ok {
+		t.Fatal("expected the failed namespace not to be cached")
+	}
+}
+
+func TestTenantCacheCoalesce(t *testing.T) {
+	s := &fakeSidecar{batchSupported: 1, block: make(chan struct{})}
+	tc := newTestTenantCache(t, s, time.Hour, time.Hour)
+
+	var wg sync.WaitGroup
+	results := make([]map[ClusterNamespace][]string, 10)
+	get := func(i int) {
+		defer wg.Done()
+		res, err := tc.get(namespaces("a"))
+		if err != nil {
+			t.Error(err)
+		}
+		results[i] = res
+	}
+
+	wg.Add(1)
+	go get(0)
+	// Wait for the first lookup to be in flight.
+	for atomic.LoadInt32(&s.inFlight) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	for i := 1; i < len(results); i++ {
+		wg.Add(1)
+		go get(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(s.block)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&s.batchRequests); n != 1 {
+		t.Fatalf("expected the concurrent lookups to share 1 request, got %d", n)
+	}
+	for i, res := range results {
+		if tenants := res[ClusterNamespace{"host", "a"}]; len(tenants) != 1 || tenants[0] != "a" {
+			t.Fatalf("lookup %d: unexpected tenants %v", i, res)
+		}
+	}
+}
+
+func TestTenantCacheFallback(t *testing.T) {
+	s := &fakeSidecar{noTenants: map[string]bool{"empty": true}}
+	tc := newTestTenantCache(t, s, 0, 0)
+
+	names := []string{"a", "b", "c", "d", "e", "f", "g", "h", "empty"}
+	res, err := tc.get(namespaces(names...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != len(names) {
+		t.Fatalf("unexpected tenants %v", res)
+	}
+	for ns, tenants := range res {
+		if ns.Namespace == "empty" && len(tenants) != 0 || ns.Namespace != "empty" && (len(tenants) != 1 || tenants[0] != ns.Namespace) {
+			t.Fatalf("namespace %s: unexpected tenants %v", ns.Namespace, tenants)
+		}
+	}
+
+	if n := atomic.LoadInt32(&s.requests); n != int32(len(names)) {
+		t.Fatalf("expected %d requests, got %d", len(names), n)
+	}
+	if n := atomic.LoadInt32(&s.maxInFlight); n > int32(cap(tc.sem)) {
+		t.Fatalf("expected at most %d concurrent requests, got %d", cap(tc.sem), n)
+	}
+
+	// The limit is shared by the concurrent lookups.
+	var wg sync.WaitGroup
+	for _, names := range [][]string{{"i", "j", "k", "l"}, {"m", "n", "o", "p"}} {
+		wg.Add(1)
+		go func(names []string) {
+			defer wg.Done()
+			_, _ = tc.get(namespaces(names...))
+		}(names)
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&s.maxInFlight); n > int32(cap(tc.sem)) {
+		t.Fatalf("expected at most %d concurrent requests of the concurrent lookups, got %d", cap(tc.sem), n)
+	}
+
+	// The batch API is not probed again within the probe interval.
+	if _, err := tc.get(namespaces("a")); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&s.batchRequests); n != 1 {
+		t.Fatalf("expected the batch API to be probed once, got %d", n)
+	}
+
+	// The batch API is probed again after the probe interval.
+	atomic.StoreInt32(&s.batchSupported, 1)
+	tc.mutex.Lock()
+	tc.batchUnsupportedUntil = time.Now().Add(-time.Second)
+	tc.mutex.Unlock()
+	requests := atomic.LoadInt32(&s.requests)
+	if _, err := tc.get(namespaces("a", "b")); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&s.batchRequests); n != 2 {
+		t.Fatalf("expected the batch API to be probed again, got %d batch requests", n)
+	}
+	if n := atomic.LoadInt32(&s.requests); n != requests {
+		t.Fatalf("expected no more single lookups, got %d", n-requests)
+	}
+}
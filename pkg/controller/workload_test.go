@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// readerCache serves the reads of the cache from a client.
+type readerCache struct {
+	cache.Cache
+	reader client.Reader
+}
+
+func (c *readerCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	return c.reader.Get(ctx, key, obj, opts...)
+}
+
+func (c *readerCache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return c.reader.List(ctx, list, opts...)
+}
+
+func newTestCache(t *testing.T, objs ...client.Object) cache.Cache {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{v1.AddToScheme, appsv1.AddToScheme, batchv1.AddToScheme, v2beta2.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return &readerCache{reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()}
+}
+
+func controlledBy(kind, name string) []metav1.OwnerReference {
+	return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: pointer.Bool(true)}}
+}
+
+func TestGetWorkload(t *testing.T) {
+	c := &Controller{
+		cache: newTestCache(t,
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"}},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-1", OwnerReferences: controlledBy("Deployment", "web")}},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bare"}},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "orphan", OwnerReferences: controlledBy("Deployment", "deleted")}},
+			&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "backup"}},
+			&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "backup-1", OwnerReferences: controlledBy("CronJob", "backup")}},
+			&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db"}},
+		),
+	}
+
+	tests := []struct {
+		owner []metav1.OwnerReference
+		kind  string
+		name  string
+	}{
+		{nil, "", ""},
+		{controlledBy("ReplicaSet", "web-1"), "Deployment", "web"},
+		{controlledBy("ReplicaSet", "bare"), "ReplicaSet", "bare"},
+		// The Deployment has been deleted, the ReplicaSet is treated as the workload.
+		{controlledBy("ReplicaSet", "orphan"), "ReplicaSet", "orphan"},
+		{controlledBy("Job", "backup-1"), "CronJob", "backup"},
+		{controlledBy("StatefulSet", "db"), "StatefulSet", "db"},
+		{controlledBy("StatefulSet", "missing"), "", ""},
+		{controlledBy("Node", "node-1"), "", ""},
+	}
+
+	for _, test := range tests {
+		pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", OwnerReferences: test.owner}}
+		workload, err := c.GetWorkload(context.Background(), pod)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if workload == nil {
+			if test.name != "" {
+				t.Fatalf("owner %v: expected %s %s, got nil", test.owner, test.kind, test.name)
+			}
+			continue
+		}
+
+		kind := ""
+		switch workload.(type) {
+		case *appsv1.Deployment:
+			kind = "Deployment"
+		case *appsv1.ReplicaSet:
+			kind = "ReplicaSet"
+		case *appsv1.StatefulSet:
+			kind = "StatefulSet"
+		case *batchv1.CronJob:
+			kind = "CronJob"
+		case *batchv1.Job:
+			kind = "Job"
+		}
+		if kind != test.kind || workload.GetName() != test.name {
+			t.Fatalf("owner %v: expected %s %s, got %s %s", test.owner, test.kind, test.name, kind, workload.GetName())
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package deadletter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+var (
+	capacity *int
+)
+
+func init() {
+	capacity = kingpin.Flag(
+		"deadletter.capacity",
+		"The maximum number of notifications kept in the dead-letter queue, the oldest one will be dropped when the queue is full",
+	).Default("1000").Int()
+}
+
+// Entry is a notification which still failed to be sent after all retries.
+type Entry struct {
+	ID           string            `json:"id"`
+	Tenant       string            `json:"tenant,omitempty"`
+	ReceiverType string            `json:"receiverType"`
+	ReceiverName string            `json:"receiverName,omitempty"`
+	Receiver     internal.Receiver `json:"-"`
+	// The targets which have received the notification, they will be skipped when replaying.
+	Attempt  *notifier.Attempt `json:"-"`
+	Data     *template.Data    `json:"data"`
+	Error    string            `json:"error,omitempty"`
+	Attempts int               `json:"attempts"`
+	Time     time.Time         `json:"time"`
+}
+
+// Queue is a bounded in-memory queue which holds the notifications failed to be sent.
+type Queue struct {
+	mutex    sync.Mutex
+	capacity int
+	entries  []*Entry
+}
+
+func NewQueue() *Queue {
+	q := &Queue{
+		capacity: *capacity,
+	}
+
+	registerMetrics(q)
+	return q
+}
+
+// Add puts a failed notification into the queue, the oldest notification will be dropped if the queue is full.
+func (q *Queue) Add(receiver internal.Receiver, data *template.Data, attempt *notifier.Attempt, attempts int, err error) *Entry {
+
+	e := &Entry{
+		ID:           string(uuid.NewUUID()),
+		Tenant:       receiver.GetTenantID(),
+		ReceiverType: receiver.GetType(),
+		ReceiverName: receiver.GetName(),
+		Receiver:     receiver,
+		Attempt:      attempt,
+		Data:         data,
+		Attempts:     attempts,
+		Time:         time.Now(),
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	deadLetterTotal.WithLabelValues(e.ReceiverType).Inc()
+	if q.capacity <= 0 {
+		deadLetterDropped.Inc()
+		return e
+	}
+
+	if len(q.entries) >= q.capacity {
+		q.entries = q.entries[len(q.entries)-q.capacity+1:]
+		deadLetterDropped.Inc()
+	}
+	q.entries = append(q.entries, e)
+
+	return e
+}
+
+// Len returns the number of notifications in the queue.
+func (q *Queue) Len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return len(q.entries)
+}
+
+// Get returns the notification with the given id, nil will be returned if not found.
+func (q *Queue) Get(id string) *Entry {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, e := range q.entries {
+		if e.ID == id {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// List returns the notifications matched the tenant, receiver type and receiver name, an empty value matches all.
+func (q *Queue) List(tenant, receiverType, receiverName string) []*Entry {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	es := make([]*Entry, 0)
+	for _, e := range q.entries {
+		if e.match(tenant, receiverType, receiverName) {
+			es = append(es, e)
+		}
+	}
+
+	return es
+}
+
+// Remove removes the notification with the given id, it returns false if the notification is not found.
+func (q *Queue) Remove(id string) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, e := range q.entries {
+		if e.ID == id {
+			q.entries = append(q.entries[:i], q.entries[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Purge removes the notifications matched the tenant, receiver type and receiver name,
+// and returns the number of removed notifications.
+func (q *Queue) Purge(tenant, receiverType, receiverName string) int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var es []*Entry
+	for _, e := range q.entries {
+		if !e.match(tenant, receiverType, receiverName) {
+			es = append(es, e)
+		}
+	}
+
+	n := len(q.entries) - len(es)
+	q.entries = es
+	return n
+}
+
+func (e *Entry) match(tenant, receiverType, receiverName string) bool {
+	if tenant != "" && e.Tenant != tenant {
+		return false
+	}
+
+	if receiverType != "" && e.ReceiverType != receiverType {
+		return false
+	}
+
+	if receiverName != "" && e.ReceiverName != receiverName {
+		return false
+	}
+
+	return true
+}
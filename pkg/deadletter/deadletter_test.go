@@ -0,0 +1,76 @@
+package deadletter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/internal/webhook"
+	"github.com/kubesphere/notification-manager/pkg/template"
+)
+
+func newReceiver(tenant, name string) internal.Receiver {
+	return &webhook.Receiver{Common: &internal.Common{Name: name, TenantID: tenant, Type: "webhook"}}
+}
+
+func TestQueue(t *testing.T) {
+	q := &Queue{capacity: 3}
+
+	admin := q.Add(newReceiver("admin", "a"), &template.Data{}, nil, 3, fmt.Errorf("timeout"))
+	user := q.Add(newReceiver("user", "b"), &template.Data{}, nil, 3, nil)
+	q.Add(newReceiver("user", "c"), &template.Data{}, nil, 3, nil)
+
+	if admin.Tenant != "admin" || admin.ReceiverType != "webhook" || admin.ReceiverName != "a" || admin.Error != "timeout" {
+		t.Fatalf("unexpected entry %+v", admin)
+	}
+	if e := q.Get(user.ID); e != user {
+		t.Fatalf("expected the entry %s, got %v", user.ID, e)
+	}
+
+	tests := []struct {
+		tenant, receiverType, receiverName string
+		n                                  int
+	}{
+		{"", "", "", 3},
+		{"admin", "", "", 1},
+		{"user", "", "", 2},
+		{"user", "webhook", "c", 1},
+		{"user", "email", "", 0},
+		{"other", "", "", 0},
+	}
+	for _, test := range tests {
+		if es := q.List(test.tenant, test.receiverType, test.receiverName); len(es) != test.n {
+			t.Fatalf("list %q/%q/%q: expected %d entries, got %d", test.tenant, test.receiverType, test.receiverName, test.n, len(es))
+		}
+	}
+
+	// The oldest entry is dropped when the queue is full.
+	q.Add(newReceiver("user", "d"), &template.Data{}, nil, 3, nil)
+	if q.Len() != 3 || q.Get(admin.ID) != nil {
+		t.Fatal("expected the oldest entry to be dropped")
+	}
+
+	if !q.Remove(user.ID) || q.Remove(user.ID) {
+		t.Fatal("expected the entry to be removed once")
+	}
+
+	q.Add(newReceiver("admin", "a"), &template.Data{}, nil, 3, nil)
+	if n := q.Purge("user", "", ""); n != 2 {
+		t.Fatalf("expected 2 entries of the tenant user purged, got %d", n)
+	}
+	if es := q.List("", "", ""); len(es) != 1 || es[0].Tenant != "admin" {
+		t.Fatalf("expected only the entry of the tenant admin kept, got %v", es)
+	}
+	if n := q.Purge("", "", ""); n != 1 || q.Len() != 0 {
+		t.Fatalf("expected all entries purged, got %d", n)
+	}
+}
+
+func TestQueueDisabled(t *testing.T) {
+	q := &Queue{}
+
+	e := q.Add(newReceiver("admin", "a"), &template.Data{}, nil, 3, nil)
+	if q.Len() != 0 || q.Get(e.ID) != nil {
+		t.Fatal("expected the entry to be dropped when the capacity is 0")
+	}
+}
@@ -0,0 +1,39 @@
+package deadletter
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	deadLetterTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "notification_manager",
+		Subsystem: "deadletter",
+		Name:      "total",
+		Help:      "The total number of notifications put into the dead-letter queue, partitioned by receiver type.",
+	}, []string{"type"})
+
+	deadLetterDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "notification_manager",
+		Subsystem: "deadletter",
+		Name:      "dropped_total",
+		Help:      "The total number of notifications dropped because the dead-letter queue is full.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(deadLetterTotal, deadLetterDropped)
+}
+
+// registerMetrics exposes the size of the dead-letter queue.
+func registerMetrics(q *Queue) {
+	prometheus.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "notification_manager",
+			Subsystem: "deadletter",
+			Name:      "queue_depth",
+			Help:      "The number of notifications in the dead-letter queue.",
+		}, func() float64 {
+			return float64(q.Len())
+		}),
+	)
+}
@@ -2,18 +2,28 @@ package dispatcher
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/ack"
 	"github.com/kubesphere/notification-manager/pkg/aggregation"
 	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/deadletter"
+	"github.com/kubesphere/notification-manager/pkg/enrichment"
+	"github.com/kubesphere/notification-manager/pkg/escalation"
 	"github.com/kubesphere/notification-manager/pkg/filter"
 	"github.com/kubesphere/notification-manager/pkg/history"
+	"github.com/kubesphere/notification-manager/pkg/inhibit"
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/nflog"
 	"github.com/kubesphere/notification-manager/pkg/notify"
+	"github.com/kubesphere/notification-manager/pkg/relabel"
 	"github.com/kubesphere/notification-manager/pkg/route"
 	"github.com/kubesphere/notification-manager/pkg/silence"
 	"github.com/kubesphere/notification-manager/pkg/stage"
+	"github.com/kubesphere/notification-manager/pkg/status"
 	"github.com/kubesphere/notification-manager/pkg/store"
 	"github.com/kubesphere/notification-manager/pkg/template"
 	"github.com/kubesphere/notification-manager/pkg/utils"
@@ -23,6 +33,15 @@ type Dispatcher struct {
 	l           log.Logger
 	notifierCtl *controller.Controller
 	alerts      *store.AlertStore
+	deadLetters *deadletter.Queue
+	nflog       *nflog.Log
+	acks        *ack.Store
+	recorder    *status.Recorder
+	recent      *RecentAlerts
+	aggregator  *aggregation.Aggregator
+	inhibitions *inhibit.Cache
+	escalations *escalation.Manager
+	silences    *silence.Lifecycle
 
 	scheduleTimeout time.Duration
 	wkrTimeout      time.Duration
@@ -31,16 +50,32 @@ type Dispatcher struct {
 	seq   int64
 }
 
-func New(l log.Logger, notifierCtl *controller.Controller, alerts *store.AlertStore, scheduleTimeout time.Duration, wkrTimeout time.Duration, workerQueue int) *Dispatcher {
+func New(l log.Logger, notifierCtl *controller.Controller, alerts *store.AlertStore, deadLetters *deadletter.Queue, nl *nflog.Log, acks *ack.Store, recorder *status.Recorder, recent *RecentAlerts, scheduleTimeout time.Duration, wkrTimeout time.Duration, workerQueue int) *Dispatcher {
 
-	return &Dispatcher{
+	d := &Dispatcher{
 		l:               l,
 		notifierCtl:     notifierCtl,
 		alerts:          alerts,
+		deadLetters:     deadLetters,
+		nflog:           nl,
+		acks:            acks,
+		recorder:        recorder,
+		recent:          recent,
 		scheduleTimeout: scheduleTimeout,
 		wkrTimeout:      wkrTimeout,
 		semCh:           make(chan struct{}, workerQueue),
+		inhibitions:     inhibit.NewCache(),
 	}
+	d.aggregator = aggregation.NewAggregator(notifierCtl, l, d.flush)
+	// The escalation steps are checked by the same silences, inhibitors and alert selectors as the alerts dispatched.
+	d.escalations = escalation.NewManager(notifierCtl, acks, l,
+		stage.MultiStage{silence.NewStage(notifierCtl, recorder), inhibit.NewStage(notifierCtl, d.inhibitions)},
+		filter.NewStage(notifierCtl, d.inhibitions, recorder),
+		d.flush)
+	// The expiry notifications of the silences are sent through the notify pipeline.
+	d.silences = silence.NewLifecycle(notifierCtl, l, d.flush)
+
+	return d
 }
 
 func (d *Dispatcher) Run() error {
@@ -51,6 +86,11 @@ func (d *Dispatcher) Run() error {
 			go d.processAlerts(alerts)
 		} else {
 			d.processAlerts(alerts)
+			// Send the alerts buffered in the aggregation groups.
+			d.aggregator.Close()
+			if err := d.escalations.Snapshot(); err != nil {
+				_ = level.Error(d.l).Log("msg", "Dispatcher: write escalation snapshot failed", "error", err.Error())
+			}
 			return nil
 		}
 	}
@@ -65,14 +105,24 @@ func (d *Dispatcher) processAlerts(alerts []*template.Alert) {
 	_ = level.Debug(d.l).Log("msg", "Dispatcher: Begins to process alerts...", "alerts", len(alerts))
 
 	if err := d.getWorker(); err != nil {
+		// Return the alerts to the store, so that they will be processed later rather than lost.
+		if err := d.alerts.Requeue(alerts); err != nil {
+			_ = level.Error(d.l).Log("msg", "Dispatcher: requeue alerts failed", "alerts", len(alerts), "error", err.Error())
+		}
 		return
 	}
 	defer d.releaseWorker()
 
-	d.seq = d.seq + 1
+	// The alerts will be acknowledged after the worker finished, and the alerts kept by the aggregation groups
+	// and the escalations have been sent.
+	batch := store.NewBatch(func() {
+		d.ackAlerts(alerts)
+	})
+
 	start := time.Now()
 	ctx, cancel := context.WithTimeout(context.Background(), d.wkrTimeout)
-	ctx = context.WithValue(ctx, "seq", d.seq)
+	ctx = context.WithValue(ctx, "seq", atomic.AddInt64(&d.seq, 1))
+	ctx = store.WithBatch(ctx, batch)
 	defer cancel()
 
 	stopCh := make(chan struct{})
@@ -82,15 +132,27 @@ func (d *Dispatcher) processAlerts(alerts []*template.Alert) {
 	case <-stopCh:
 		elapsed := time.Since(start).String()
 		_ = level.Debug(d.l).Log("msg", "Dispatcher: Processor exit after "+elapsed)
+		batch.Release()
 		return
 	case <-ctx.Done():
 		if err := ctx.Err(); err != nil {
 			_ = level.Warn(d.l).Log("msg", "Dispatcher: process alerts timeout in "+d.wkrTimeout.String(), "error", err.Error())
 		}
+		// The worker is still running, the alerts can not be acknowledged until it exits.
+		go func() {
+			<-stopCh
+			batch.Release()
+		}()
 		return
 	}
 }
 
+func (d *Dispatcher) ackAlerts(alerts []*template.Alert) {
+	if err := d.alerts.Ack(alerts); err != nil {
+		_ = level.Error(d.l).Log("msg", "Dispatcher: ack alerts failed", "error", err.Error())
+	}
+}
+
 func (d *Dispatcher) getWorker() error {
 	ctx, cancel := context.WithTimeout(context.Background(), d.scheduleTimeout)
 	defer cancel()
@@ -112,16 +174,31 @@ func (d *Dispatcher) releaseWorker() {
 func (d *Dispatcher) worker(ctx context.Context, data interface{}, stopCh chan struct{}) {
 
 	pipeline := stage.MultiStage{}
+	// Relabel stage
+	pipeline = append(pipeline, relabel.NewStage(d.notifierCtl))
+	// Enrichment stage
+	pipeline = append(pipeline, enrichment.NewStage(d.notifierCtl))
+	// Remember the source alerts of the inhibitors, including the alerts which will be acknowledged or silenced.
+	pipeline = append(pipeline, inhibit.NewObserveStage(d.inhibitions))
+	// Cancel the escalations of the resolved alerts, including the alerts which will be acknowledged or silenced.
+	pipeline = append(pipeline, escalation.NewCancelStage(d.escalations))
+	// Ack stage
+	pipeline = append(pipeline, ack.NewStage(d.acks))
 	// Global silence stage
-	pipeline = append(pipeline, silence.NewStage(d.notifierCtl))
+	pipeline = append(pipeline, silence.NewStage(d.notifierCtl, d.recorder))
+	// Global inhibit stage
+	pipeline = append(pipeline, inhibit.NewStage(d.notifierCtl, d.inhibitions))
 	// Route stage
-	pipeline = append(pipeline, route.NewStage(d.notifierCtl))
-	// Tenant silence stage
-	pipeline = append(pipeline, filter.NewStage(d.notifierCtl))
-	// Aggregation stage
-	pipeline = append(pipeline, aggregation.NewStage(d.notifierCtl))
-	// Notify stage
-	pipeline = append(pipeline, notify.NewStage(d.notifierCtl))
+	pipeline = append(pipeline, route.NewStage(d.notifierCtl, d.recorder))
+	// Record the routed alerts for the silence preview.
+	pipeline = append(pipeline, d.recent)
+	// Tenant silence and inhibit stage
+	pipeline = append(pipeline, filter.NewStage(d.notifierCtl, d.inhibitions, d.recorder))
+	// Escalation stage, the firing alerts will be sent to the receivers of the escalation steps later.
+	pipeline = append(pipeline, d.escalations)
+	// Aggregation stage, the alerts which need to wait will be sent when their groups are flushed.
+	pipeline = append(pipeline, d.aggregator)
+	pipeline = append(pipeline, d.notifyPipeline()...)
 
 	_, output, err := pipeline.Exec(ctx, d.l, data)
 	if err != nil {
@@ -133,6 +210,49 @@ func (d *Dispatcher) worker(ctx context.Context, data interface{}, stopCh chan s
 	stopCh <- struct{}{}
 }
 
+func (d *Dispatcher) notifyPipeline() stage.MultiStage {
+
+	pipeline := stage.MultiStage{}
+	// Dedup stage
+	pipeline = append(pipeline, nflog.NewStage(d.notifierCtl, d.nflog))
+	// Notify stage
+	pipeline = append(pipeline, notify.NewRetryStage(d.notifierCtl, d.deadLetters, d.nflog, d.recorder))
+
+	return pipeline
+}
+
+// flush sends the notifications of the aggregation groups, the escalation steps and the silence expiry notices.
+// It waits for a worker rather than dropping the notifications when the workers are busy. The release is called
+// after the notifications have been sent or failed after all retries, so that the alerts will not be acknowledged
+// by the store before that.
+func (d *Dispatcher) flush(data map[internal.Receiver][]*template.Data, release func()) {
+
+	if release == nil {
+		release = func() {}
+	}
+
+	for d.getWorker() != nil {
+		_ = level.Warn(d.l).Log("msg", "Dispatcher: waiting for a worker to flush notifications", "receivers", len(data))
+	}
+	defer d.releaseWorker()
+
+	// The retry stage holds the batch until the retries finish.
+	batch := store.NewBatch(release)
+	defer batch.Release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.wkrTimeout)
+	ctx = context.WithValue(ctx, "seq", atomic.AddInt64(&d.seq, 1))
+	ctx = store.WithBatch(ctx, batch)
+	defer cancel()
+
+	_, output, err := d.notifyPipeline().Exec(ctx, d.l, data)
+	if err != nil {
+		_ = level.Error(d.l).Log("msg", "Dispatcher: flush notifications failed", "seq", ctx.Value("seq"), "error", err.Error())
+	}
+
+	go d.execHistoryStage(ctx.Value("seq"), output)
+}
+
 func (d *Dispatcher) execHistoryStage(seq, input interface{}) {
 	s := history.NewStage(d.notifierCtl)
 	if _, _, err := s.Exec(context.WithValue(context.Background(), "seq", seq), d.l, input); err != nil {
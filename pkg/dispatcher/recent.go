@@ -0,0 +1,152 @@
+package dispatcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/modern-go/reflect2"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	recentCapacity *int
+)
+
+func init() {
+	recentCapacity = kingpin.Flag(
+		"dispatcher.recentAlerts.capacity",
+		"The maximum number of the recently routed alerts kept for the silence preview, the oldest one will be dropped when the buffer is full",
+	).Default("10000").Int()
+}
+
+// RecentReceiver is a receiver to which a recent alert was routed.
+type RecentReceiver struct {
+	Tenant string            `json:"tenant,omitempty"`
+	Type   string            `json:"type"`
+	Name   string            `json:"name,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// RecentAlert is an alert seen by the dispatcher recently, and the receivers it was routed to.
+type RecentAlert struct {
+	Alert     *template.Alert
+	SeenAt    time.Time
+	Receivers []RecentReceiver
+}
+
+// RecentAlerts is a bounded in-memory ring buffer which holds the recently routed alerts.
+// It is also a stage which records the output of the route stage, and passes it through.
+type RecentAlerts struct {
+	mutex sync.Mutex
+	// The length of the buffer is the capacity.
+	alerts []*RecentAlert
+	// The index of the oldest alert.
+	head int
+	// The number of alerts in the buffer.
+	size int
+}
+
+func NewRecentAlerts() *RecentAlerts {
+	return newRecentAlerts(*recentCapacity)
+}
+
+func newRecentAlerts(capacity int) *RecentAlerts {
+	r := &RecentAlerts{}
+	if capacity > 0 {
+		r.alerts = make([]*RecentAlert, capacity)
+	}
+	return r
+}
+
+func (r *RecentAlerts) Exec(ctx context.Context, _ log.Logger, data interface{}) (context.Context, interface{}, error) {
+
+	if reflect2.IsNil(data) || len(r.alerts) == 0 {
+		return ctx, data, nil
+	}
+
+	now := time.Now()
+	m := make(map[*template.Alert]*RecentAlert)
+	var alerts []*RecentAlert
+	for receiver, as := range data.(map[internal.Receiver][]*template.Alert) {
+		for _, alert := range as {
+			ra := m[alert]
+			if ra == nil {
+				ra = &RecentAlert{
+					Alert:  alert.Clone(),
+					SeenAt: now,
+				}
+				m[alert] = ra
+				alerts = append(alerts, ra)
+			}
+			ra.Receivers = append(ra.Receivers, RecentReceiver{
+				Tenant: receiver.GetTenantID(),
+				Type:   receiver.GetType(),
+				Name:   receiver.GetName(),
+				Labels: receiver.GetLabels(),
+			})
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, ra := range alerts {
+		r.push(ra)
+	}
+
+	return ctx, data, nil
+}
+
+// push adds the alert to the buffer, the oldest alert will be overwritten if the buffer is full.
+func (r *RecentAlerts) push(ra *RecentAlert) {
+	capacity := len(r.alerts)
+	if r.size < capacity {
+		r.alerts[(r.head+r.size)%capacity] = ra
+		r.size++
+		return
+	}
+
+	r.alerts[r.head] = ra
+	r.head = (r.head + 1) % capacity
+}
+
+// at returns the i-th oldest alert in the buffer.
+func (r *RecentAlerts) at(i int) *RecentAlert {
+	return r.alerts[(r.head+i)%len(r.alerts)]
+}
+
+// List returns the alerts seen in the time range [start, end], in the order they were seen.
+func (r *RecentAlerts) List(start, end time.Time) []*RecentAlert {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var res []*RecentAlert
+	for i := 0; i < r.size; i++ {
+		ra := r.at(i)
+		if !ra.SeenAt.Before(start) && !ra.SeenAt.After(end) {
+			res = append(res, ra)
+		}
+	}
+
+	return res
+}
+
+// Get returns a copy of the latest alert with the fingerprint seen recently, nil will be returned if it is not found.
+func (r *RecentAlerts) Get(fingerprint string) *template.Alert {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i := r.size - 1; i >= 0; i-- {
+		if ra := r.at(i); ra.Alert.Fingerprint() == fingerprint {
+			return ra.Alert.Clone()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package dispatcher
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/internal/webhook"
+	"github.com/kubesphere/notification-manager/pkg/template"
+)
+
+func newReceiver(tenant, name string) internal.Receiver {
+	return &webhook.Receiver{Common: &internal.Common{Name: name, TenantID: tenant, Hash: tenant + "/" + name}}
+}
+
+func newAlert(name string) *template.Alert {
+	return &template.Alert{Status: "firing", Labels: template.KV{"alertname": name}}
+}
+
+func TestGet(t *testing.T) {
+	r := newRecentAlerts(10)
+
+	a, b := newAlert("a"), newAlert("b")
+	data := map[internal.Receiver][]*template.Alert{
+		newReceiver("admin", "slack"): {a},
+		newReceiver("", "global"):     {a, b},
+	}
+	if _, _, err := r.Exec(context.Background(), log.NewNopLogger(), data); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, alert := range []*template.Alert{a, b} {
+		got := r.Get(alert.Fingerprint())
+		if got == nil || got.Fingerprint() != alert.Fingerprint() {
+			t.Fatalf("expected the alert %s, got %v", alert.Labels["alertname"], got)
+		}
+		if got == alert {
+			t.Fatalf("expected a copy of the alert %s", alert.Labels["alertname"])
+		}
+	}
+	if r.Get(newAlert("c").Fingerprint()) != nil {
+		t.Fatal("expected the alert c not to be found")
+	}
+}
+
+func TestRecentAlertsWrap(t *testing.T) {
+	r := newRecentAlerts(3)
+
+	rcv := newReceiver("admin", "slack")
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		data := map[internal.Receiver][]*template.Alert{rcv: {newAlert(name)}}
+		if _, _, err := r.Exec(context.Background(), log.NewNopLogger(), data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var names []string
+	for _, ra := range r.List(time.Time{}, time.Now()) {
+		names = append(names, ra.Alert.Labels["alertname"])
+	}
+	if strings.Join(names, ",") != "c,d,e" {
+		t.Fatalf("expected the alerts c,d,e, got %v", names)
+	}
+
+	if r.Get(newAlert("b").Fingerprint()) != nil {
+		t.Fatal("the dropped alert b should not be found")
+	}
+	if r.Get(newAlert("c").Fingerprint()) == nil {
+		t.Fatal("the alert c should be found")
+	}
+}
+
+func TestRecentAlertsDisabled(t *testing.T) {
+	r := newRecentAlerts(0)
+
+	data := map[internal.Receiver][]*template.Alert{newReceiver("admin", "slack"): {newAlert("a")}}
+	if _, _, err := r.Exec(context.Background(), log.NewNopLogger(), data); err != nil {
+		t.Fatal(err)
+	}
+	if res := r.List(time.Time{}, time.Now()); len(res) != 0 {
+		t.Fatalf("expected no alerts, got %d", len(res))
+	}
+}
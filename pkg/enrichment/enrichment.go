@@ -0,0 +1,132 @@
+package enrichment
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/constants"
+	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/stage"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+	"github.com/modern-go/reflect2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type enrichmentStage struct {
+	notifierCtl *controller.Controller
+}
+
+// NewStage returns a stage which copies the metadata of the namespace, pod and workload the alerts belong to onto the alerts.
+func NewStage(notifierCtl *controller.Controller) stage.Stage {
+	return &enrichmentStage{
+		notifierCtl: notifierCtl,
+	}
+}
+
+func (s *enrichmentStage) Exec(ctx context.Context, l log.Logger, data interface{}) (context.Context, interface{}, error) {
+
+	if reflect2.IsNil(data) {
+		return ctx, nil, nil
+	}
+
+	enrichment := s.notifierCtl.GetEnrichment()
+	if enrichment == nil {
+		return ctx, data, nil
+	}
+
+	input := data.([]*template.Alert)
+
+	_ = level.Debug(l).Log("msg", "Start enrichment stage", "seq", ctx.Value("seq"), "alert", len(input))
+
+	for _, alert := range input {
+		if err := s.enrich(ctx, enrichment, alert); err != nil {
+			// An alert which failed to be enriched will still be sent.
+			_ = level.Error(l).Log("msg", "Enrich alert failed", "stage", "Enrichment", "seq", ctx.Value("seq"), "alert", alert.ID, "error", err.Error())
+		}
+	}
+
+	return ctx, input, nil
+}
+
+func (s *enrichmentStage) enrich(ctx context.Context, enrichment *v2beta2.Enrichment, alert *template.Alert) error {
+
+	namespace := alert.Labels[constants.Namespace]
+	if utils.StringIsNil(namespace) {
+		return nil
+	}
+
+	if enrichment.Namespace != nil {
+		ns, err := s.notifierCtl.GetNamespace(ctx, namespace)
+		if err != nil {
+			return err
+		}
+		if ns != nil {
+			copyMetadata(alert, ns, enrichment.Namespace)
+		}
+	}
+
+	podName := alert.Labels[constants.Pod]
+	if utils.StringIsNil(podName) || (enrichment.Pod == nil && enrichment.Workload == nil) {
+		return nil
+	}
+
+	pod, err := s.notifierCtl.GetPod(ctx, namespace, podName)
+	if err != nil || pod == nil {
+		return err
+	}
+
+	if enrichment.Pod != nil {
+		copyMetadata(alert, pod, enrichment.Pod)
+	}
+
+	if enrichment.Workload != nil {
+		workload, err := s.notifierCtl.GetWorkload(ctx, pod)
+		if err != nil {
+			return err
+		}
+		if workload != nil {
+			copyMetadata(alert, workload, enrichment.Workload)
+		}
+	}
+
+	return nil
+}
+
+// LabelNames returns the names of the labels which may be copied to the alerts by the enrichment.
+func LabelNames(enrichment *v2beta2.Enrichment) []string {
+
+	if enrichment == nil {
+		return nil
+	}
+
+	var names []string
+	for _, me := range []*v2beta2.MetadataEnrichment{enrichment.Namespace, enrichment.Pod, enrichment.Workload} {
+		if me == nil {
+			continue
+		}
+		for _, key := range append(append([]string{}, me.Labels...), me.Annotations...) {
+			names = append(names, me.Prefix+key)
+		}
+	}
+
+	return names
+}
+
+// copyMetadata copies the labels and annotations of the object to the labels of the alert,
+// the existing labels of the alert will not be overwritten, and the copied labels do not change the fingerprint of the alert.
+func copyMetadata(alert *template.Alert, obj metav1.Object, me *v2beta2.MetadataEnrichment) {
+
+	set := func(src map[string]string, keys []string) {
+		for _, key := range keys {
+			if v, ok := src[key]; ok {
+				alert.Enrich(me.Prefix+key, v)
+			}
+		}
+	}
+
+	set(obj.GetLabels(), me.Labels)
+	set(obj.GetAnnotations(), me.Annotations)
+}
@@ -0,0 +1,102 @@
+package enrichment
+
+import (
+	"testing"
+
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/constants"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCopyMetadata(t *testing.T) {
+	obj := &metav1.ObjectMeta{
+		Labels:      map[string]string{"team": "infra", "app": "nginx"},
+		Annotations: map[string]string{"owner": "alice"},
+	}
+
+	tests := []struct {
+		name     string
+		labels   template.KV
+		me       *v2beta2.MetadataEnrichment
+		expected template.KV
+	}{
+		{
+			name:     "copy labels and annotations",
+			labels:   template.KV{"alertname": "a"},
+			me:       &v2beta2.MetadataEnrichment{Labels: []string{"team"}, Annotations: []string{"owner"}},
+			expected: template.KV{"alertname": "a", "team": "infra", "owner": "alice"},
+		},
+		{
+			name:     "add prefix",
+			labels:   template.KV{"alertname": "a"},
+			me:       &v2beta2.MetadataEnrichment{Labels: []string{"team"}, Prefix: "ns_"},
+			expected: template.KV{"alertname": "a", "ns_team": "infra"},
+		},
+		{
+			name:     "existing labels are not overwritten",
+			labels:   template.KV{"app": "mysql"},
+			me:       &v2beta2.MetadataEnrichment{Labels: []string{"app", "team"}},
+			expected: template.KV{"app": "mysql", "team": "infra"},
+		},
+		{
+			name:     "missing keys are ignored",
+			labels:   template.KV{"alertname": "a"},
+			me:       &v2beta2.MetadataEnrichment{Labels: []string{"missing"}, Annotations: []string{"team"}},
+			expected: template.KV{"alertname": "a"},
+		},
+		{
+			name:     "nil labels",
+			me:       &v2beta2.MetadataEnrichment{Labels: []string{"app"}},
+			expected: template.KV{"app": "nginx"},
+		},
+	}
+
+	for _, test := range tests {
+		alert := &template.Alert{Labels: test.labels}
+		copyMetadata(alert, obj, test.me)
+
+		if len(alert.Labels) != len(test.expected) {
+			t.Fatalf("%s: expected %v, got %v", test.name, test.expected, alert.Labels)
+		}
+		for k, v := range test.expected {
+			if alert.Labels[k] != v {
+				t.Fatalf("%s: expected %v, got %v", test.name, test.expected, alert.Labels)
+			}
+		}
+	}
+}
+
+func TestFingerprintNotChanged(t *testing.T) {
+	pod := &metav1.ObjectMeta{
+		Labels: map[string]string{"app": "nginx"},
+	}
+	me := &v2beta2.MetadataEnrichment{Labels: []string{"app"}, Prefix: "pod_"}
+
+	labels := template.KV{"alertname": "a", "namespace": "default", "pod": "nginx-0"}
+	firing := &template.Alert{Status: constants.AlertFiring, Labels: labels.Clone()}
+	fingerprint := firing.Fingerprint()
+	copyMetadata(firing, pod, me)
+	if firing.Labels["pod_app"] != "nginx" {
+		t.Fatalf("expected the pod label to be copied, got %v", firing.Labels)
+	}
+	if firing.Fingerprint() != fingerprint {
+		t.Fatal("expected the fingerprint not to be changed by the enriched labels")
+	}
+	if firing.Clone().Fingerprint() != fingerprint {
+		t.Fatal("expected the fingerprint of the clone not to be changed by the enriched labels")
+	}
+
+	// The pod has gone before the alert is resolved, the resolved alert can not be enriched.
+	resolved := &template.Alert{Status: constants.AlertResolved, Labels: labels.Clone()}
+	if resolved.Fingerprint() != firing.Fingerprint() {
+		t.Fatal("expected the resolved alert to have the same fingerprint as the enriched firing alert")
+	}
+
+	// The label of the alert itself is part of the fingerprint.
+	own := &template.Alert{Labels: template.KV{"alertname": "a", "pod_app": "nginx"}}
+	copyMetadata(own, pod, me)
+	if own.Fingerprint() == (&template.Alert{Labels: template.KV{"alertname": "a"}}).Fingerprint() {
+		t.Fatal("expected the existing label to be part of the fingerprint")
+	}
+}
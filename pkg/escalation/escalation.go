@@ -0,0 +1,498 @@
+package escalation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/ack"
+	"github.com/kubesphere/notification-manager/pkg/constants"
+	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/stage"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+	"github.com/modern-go/reflect2"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	retention        *time.Duration
+	snapshotFile     *string
+	snapshotInterval *time.Duration
+)
+
+func init() {
+	retention = kingpin.Flag(
+		"escalation.retention",
+		"How long to track a firing alert for escalation if it is neither resolved nor resent",
+	).Default("24h").Duration()
+	snapshotFile = kingpin.Flag(
+		"escalation.snapshotFile",
+		"The file the escalations are written to periodically and loaded from at start, so that they survive a restart. The escalations are only kept in memory if not set",
+	).Default("").String()
+	snapshotInterval = kingpin.Flag(
+		"escalation.snapshotInterval",
+		"Interval to write the escalations to the snapshot file",
+	).Default("1m").Duration()
+}
+
+// SendFunc sends the notifications to the receivers of an escalation step,
+// and calls release once the notifications have been sent or failed after all retries. The release can be nil.
+type SendFunc func(data map[internal.Receiver][]*template.Data, release func())
+
+// escalation tracks the escalation of a firing alert with an escalation policy.
+type escalation struct {
+	policy      string
+	fingerprint string
+	alert       *template.Alert
+	// The time when the escalation started, the delays of the steps are relative to it.
+	startsAt time.Time
+	// The time when the alert was last received.
+	updatedAt time.Time
+	// The index of the next step.
+	step  int
+	timer *time.Timer
+}
+
+// state is the state of an escalation written to the snapshot file.
+type state struct {
+	Policy      string          `json:"policy"`
+	Fingerprint string          `json:"fingerprint"`
+	Alert       *template.Alert `json:"alert"`
+	StartsAt    time.Time       `json:"startsAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+	Step        int             `json:"step"`
+	// Finished is true if all the steps have been executed.
+	Finished bool `json:"finished,omitempty"`
+}
+
+// Manager tracks the firing alerts sent to the receivers which have an escalation policy,
+// and sends the alerts to the receivers of the escalation steps in order.
+// The escalation of an alert will be cancelled when the alert is resolved or acknowledged.
+// The escalations do not hold the alerts in the store, they are written to the snapshot file periodically
+// if it is set, so that they survive a restart.
+// The silences, inhibitors and alert selectors are checked again before each step,
+// so that a step will not be sent if the alert has been silenced or inhibited since the escalation started.
+type Manager struct {
+	mutex       sync.Mutex
+	notifierCtl *controller.Controller
+	acks        *ack.Store
+	logger      log.Logger
+	escalations map[string]*escalation
+	// mute drops the alerts silenced or inhibited globally, the input is a slice of alerts.
+	mute stage.Stage
+	// filter drops the alerts silenced, inhibited or not selected by the receivers, the input is a map of receivers to alerts.
+	filter    stage.Stage
+	send      SendFunc
+	retention time.Duration
+
+	snapshotFile     string
+	snapshotInterval time.Duration
+}
+
+func NewManager(notifierCtl *controller.Controller, acks *ack.Store, logger log.Logger, mute, filter stage.Stage, send SendFunc) *Manager {
+	m := &Manager{
+		notifierCtl:      notifierCtl,
+		acks:             acks,
+		logger:           logger,
+		escalations:      make(map[string]*escalation),
+		mute:             mute,
+		filter:           filter,
+		send:             send,
+		retention:        *retention,
+		snapshotFile:     *snapshotFile,
+		snapshotInterval: *snapshotInterval,
+	}
+
+	unfinished, err := m.load()
+	if err != nil {
+		_ = level.Error(logger).Log("msg", "Escalation: load snapshot failed", "file", m.snapshotFile, "error", err.Error())
+	}
+	// The delay of the next step is checked when it is executed.
+	for _, k := range unfinished {
+		m.schedule(k, m.escalations[k], 0)
+	}
+
+	go m.gc()
+	go m.snapshot()
+	return m
+}
+
+func key(policy, fingerprint string) string {
+	return fmt.Sprintf("%s/%s", policy, fingerprint)
+}
+
+// Exec starts the escalations of the firing alerts. The escalations of the resolved alerts are cancelled
+// by the cancel stage, which runs before the alerts are acknowledged, silenced or inhibited.
+// The data is passed to the next stage unchanged.
+func (m *Manager) Exec(ctx context.Context, l log.Logger, data interface{}) (context.Context, interface{}, error) {
+
+	if reflect2.IsNil(data) {
+		return ctx, nil, nil
+	}
+
+	_ = level.Debug(l).Log("msg", "Start escalation stage", "seq", ctx.Value("seq"))
+
+	alertMap := data.(map[internal.Receiver][]*template.Alert)
+	for receiver, alerts := range alertMap {
+		policy := receiver.GetEscalationPolicy()
+		for _, alert := range alerts {
+			// The acknowledged alert will not be escalated.
+			if alert.Status != constants.AlertResolved && !utils.StringIsNil(policy) && alert.Acknowledgement == nil {
+				m.track(policy, alert)
+			}
+		}
+	}
+
+	return ctx, data, nil
+}
+
+func (m *Manager) track(policy string, alert *template.Alert) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	fingerprint := alert.Fingerprint()
+	k := key(policy, fingerprint)
+	now := time.Now()
+	if e, ok := m.escalations[k]; ok {
+		// The escalation which has finished is kept, so that the resent alert will not be escalated again.
+		e.alert = alert.Clone()
+		e.updatedAt = now
+		return
+	}
+
+	e := &escalation{
+		policy:      policy,
+		fingerprint: fingerprint,
+		alert:       alert.Clone(),
+		startsAt:    now,
+		updatedAt:   now,
+	}
+	m.schedule(k, e, 0)
+	m.escalations[k] = e
+}
+
+// escalate executes the next step of the escalation, and schedules the step after it.
+func (m *Manager) escalate(k string) {
+
+	m.mutex.Lock()
+	e, ok := m.escalations[k]
+	m.mutex.Unlock()
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	policy, err := m.notifierCtl.GetActiveEscalationPolicy(ctx, e.policy)
+	acknowledgement, ackErr := m.acks.Get(e.fingerprint)
+
+	m.mutex.Lock()
+	// The escalation has been cancelled.
+	if m.escalations[k] != e {
+		m.mutex.Unlock()
+		return
+	}
+
+	if ackErr != nil {
+		_ = level.Error(m.logger).Log("msg", "Escalation: get acknowledgement failed", "fingerprint", e.fingerprint, "error", ackErr.Error())
+	} else if acknowledgement != nil {
+		_ = level.Debug(m.logger).Log("msg", "Escalation: alert acknowledged", "policy", e.policy, "fingerprint", e.fingerprint, "by", acknowledgement.By)
+		m.remove(k, e)
+		m.mutex.Unlock()
+		return
+	}
+
+	if err != nil {
+		_ = level.Error(m.logger).Log("msg", "Escalation: get escalation policy failed", "policy", e.policy, "error", err.Error())
+		m.schedule(k, e, time.Minute)
+		m.mutex.Unlock()
+		return
+	}
+
+	if policy == nil || e.step >= len(policy.Spec.Steps) {
+		// Keep the escalation, so that the resent alert will not be escalated again.
+		e.timer = nil
+		m.mutex.Unlock()
+		return
+	}
+
+	step := policy.Spec.Steps[e.step]
+	// The step will be executed later if its delay has been increased.
+	if d := time.Until(e.startsAt.Add(step.Delay.Duration)); d > 0 {
+		m.schedule(k, e, d)
+		m.mutex.Unlock()
+		return
+	}
+
+	e.step = e.step + 1
+	if e.step < len(policy.Spec.Steps) {
+		m.schedule(k, e, time.Until(e.startsAt.Add(policy.Spec.Steps[e.step].Delay.Duration)))
+	} else {
+		e.timer = nil
+	}
+
+	index := e.step
+	alert := e.alert.Clone()
+	rcvs := m.notifierCtl.RcvsFromReceiverSelector(step.Receivers)
+	m.mutex.Unlock()
+
+	if len(rcvs) == 0 {
+		return
+	}
+
+	res := m.check(ctx, alert, rcvs)
+	if len(res) == 0 {
+		_ = level.Debug(m.logger).Log("msg", "Escalation: skip step, the alert is silenced or inhibited", "policy", policy.Name, "step", index, "fingerprint", e.fingerprint)
+		return
+	}
+
+	_ = level.Debug(m.logger).Log("msg", "Escalation: execute step", "policy", policy.Name, "step", index, "receivers", len(res))
+	m.send(res, nil)
+}
+
+// check applies the silences, inhibitors and alert selectors to the alert sent to the receivers of a step,
+// and returns the notifications of the receivers which the alert is still sent to.
+// The alert will be sent to all the receivers if the check fails, an escalation should not be lost because of an error.
+func (m *Manager) check(ctx context.Context, alert *template.Alert, rcvs []internal.Receiver) map[internal.Receiver][]*template.Data {
+
+	if m.mute != nil {
+		_, output, err := m.mute.Exec(ctx, m.logger, []*template.Alert{alert})
+		if err != nil {
+			_ = level.Error(m.logger).Log("msg", "Escalation: check silences and inhibitors failed", "fingerprint", alert.Fingerprint(), "error", err.Error())
+		} else if reflect2.IsNil(output) || len(output.([]*template.Alert)) == 0 {
+			return nil
+		}
+	}
+
+	input := make(map[internal.Receiver][]*template.Alert)
+	for _, rcv := range rcvs {
+		input[rcv] = []*template.Alert{alert}
+	}
+
+	if m.filter != nil {
+		_, output, err := m.filter.Exec(ctx, m.logger, input)
+		if err != nil {
+			_ = level.Error(m.logger).Log("msg", "Escalation: filter receivers failed", "fingerprint", alert.Fingerprint(), "error", err.Error())
+		} else if !reflect2.IsNil(output) {
+			input = output.(map[internal.Receiver][]*template.Alert)
+		}
+	}
+
+	res := make(map[internal.Receiver][]*template.Data)
+	for rcv, alerts := range input {
+		if len(alerts) == 0 {
+			continue
+		}
+
+		// The notify stage changes the labels of the alerts, every receiver needs its own copy.
+		d := &template.Data{
+			GroupLabels: template.KV{},
+			Alerts:      template.Alerts{alert.Clone()},
+		}
+		res[rcv] = []*template.Data{d.Format()}
+	}
+
+	return res
+}
+
+// schedule executes the next step of the escalation after the duration, it must be called with the lock held.
+func (m *Manager) schedule(k string, e *escalation, d time.Duration) {
+	e.timer = time.AfterFunc(d, func() {
+		m.escalate(k)
+	})
+}
+
+// Cancel stops the escalations of the alert with the fingerprint.
+func (m *Manager) Cancel(fingerprint string) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for k, e := range m.escalations {
+		if e.fingerprint == fingerprint {
+			m.remove(k, e)
+		}
+	}
+}
+
+// remove stops and removes the escalation, it must be called with the lock held.
+func (m *Manager) remove(k string, e *escalation) {
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+	delete(m.escalations, k)
+}
+
+func (m *Manager) gc() {
+	if m.retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.retention / 10)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		m.expire(time.Now())
+	}
+}
+
+// expire removes the escalations of the alerts which have not been received within the retention.
+func (m *Manager) expire(now time.Time) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for k, e := range m.escalations {
+		if now.Sub(e.updatedAt) > m.retention {
+			m.remove(k, e)
+		}
+	}
+}
+
+func (m *Manager) snapshot() {
+	if utils.StringIsNil(m.snapshotFile) || m.snapshotInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.snapshotInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := m.Snapshot(); err != nil {
+			_ = level.Error(m.logger).Log("msg", "Escalation: write snapshot failed", "file", m.snapshotFile, "error", err.Error())
+		}
+	}
+}
+
+// Snapshot writes the escalations to the snapshot file, it does nothing if the snapshot file is not set.
+func (m *Manager) Snapshot() error {
+	if utils.StringIsNil(m.snapshotFile) {
+		return nil
+	}
+
+	m.mutex.Lock()
+	states := make(map[string]*state, len(m.escalations))
+	for k, e := range m.escalations {
+		states[k] = &state{
+			Policy:      e.policy,
+			Fingerprint: e.fingerprint,
+			Alert:       e.alert,
+			StartsAt:    e.startsAt,
+			UpdatedAt:   e.updatedAt,
+			Step:        e.step,
+			Finished:    e.timer == nil,
+		}
+	}
+	bs, err := utils.JsonMarshal(states)
+	m.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it, so that the snapshot will not be corrupted by a crash.
+	tmp, err := os.CreateTemp(filepath.Dir(m.snapshotFile), filepath.Base(m.snapshotFile)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(bs); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), m.snapshotFile)
+}
+
+// load loads the escalations from the snapshot file, the expired escalations are dropped.
+// It returns the keys of the unfinished escalations, whose next steps need to be scheduled.
+func (m *Manager) load() ([]string, error) {
+	if utils.StringIsNil(m.snapshotFile) {
+		return nil, nil
+	}
+
+	bs, err := os.ReadFile(m.snapshotFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	states := make(map[string]*state)
+	if err := utils.JsonUnmarshal(bs, &states); err != nil {
+		return nil, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var unfinished []string
+	for k, s := range states {
+		if s == nil || s.Alert == nil || (m.retention > 0 && time.Since(s.UpdatedAt) > m.retention) {
+			continue
+		}
+
+		e := &escalation{
+			policy:      s.Policy,
+			fingerprint: s.Fingerprint,
+			alert:       s.Alert,
+			startsAt:    s.StartsAt,
+			updatedAt:   s.UpdatedAt,
+			step:        s.Step,
+		}
+		if !s.Finished {
+			unfinished = append(unfinished, k)
+		}
+		m.escalations[k] = e
+	}
+
+	return unfinished, nil
+}
+
+type cancelStage struct {
+	manager *Manager
+}
+
+// NewCancelStage returns a stage which cancels the escalations of the resolved alerts.
+// It should run before the alerts are acknowledged, silenced or inhibited, so that the resolved alerts
+// dropped by these stages cancel their escalations too.
+func NewCancelStage(m *Manager) stage.Stage {
+	return &cancelStage{
+		manager: m,
+	}
+}
+
+func (s *cancelStage) Exec(ctx context.Context, l log.Logger, data interface{}) (context.Context, interface{}, error) {
+
+	if reflect2.IsNil(data) {
+		return ctx, nil, nil
+	}
+
+	_ = level.Debug(l).Log("msg", "Start escalation cancel stage", "seq", ctx.Value("seq"))
+
+	for _, alert := range data.([]*template.Alert) {
+		if alert.Status == constants.AlertResolved {
+			s.manager.Cancel(alert.Fingerprint())
+		}
+	}
+
+	return ctx, data, nil
+}
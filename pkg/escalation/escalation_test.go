@@ -0,0 +1,250 @@
+package escalation
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/internal/webhook"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+)
+
+// stageFunc adapts a function to a stage.
+type stageFunc func(data interface{}) (interface{}, error)
+
+func (f stageFunc) Exec(ctx context.Context, _ log.Logger, data interface{}) (context.Context, interface{}, error) {
+	output, err := f(data)
+	return ctx, output, err
+}
+
+// mute drops the alerts with the label silenced.
+var mute = stageFunc(func(data interface{}) (interface{}, error) {
+	var output []*template.Alert
+	for _, alert := range data.([]*template.Alert) {
+		if alert.Labels["silenced"] == "" {
+			output = append(output, alert)
+		}
+	}
+	return output, nil
+})
+
+// filter drops the alerts sent to the receiver named muted.
+var filter = stageFunc(func(data interface{}) (interface{}, error) {
+	res := make(map[internal.Receiver][]*template.Alert)
+	for rcv, alerts := range data.(map[internal.Receiver][]*template.Alert) {
+		if rcv.GetName() != "muted" {
+			res[rcv] = alerts
+		}
+	}
+	return res, nil
+})
+
+var failed = stageFunc(func(data interface{}) (interface{}, error) {
+	return nil, utils.Error("failed")
+})
+
+func newReceiver(name string) internal.Receiver {
+	return &webhook.Receiver{Common: &internal.Common{Name: name, Hash: name}}
+}
+
+func newManager(mute, filter stageFunc) *Manager {
+	m := &Manager{
+		logger:      log.NewNopLogger(),
+		escalations: make(map[string]*escalation),
+		retention:   time.Hour,
+	}
+	// Keep the fields nil rather than a nil stageFunc.
+	if mute != nil {
+		m.mute = mute
+	}
+	if filter != nil {
+		m.filter = filter
+	}
+	return m
+}
+
+func TestCheck(t *testing.T) {
+	rcvs := []internal.Receiver{newReceiver("a"), newReceiver("muted")}
+
+	tests := []struct {
+		name      string
+		mute      stageFunc
+		filter    stageFunc
+		labels    template.KV
+		receivers []string
+	}{
+		{"no check", nil, nil, template.KV{"alertname": "a"}, []string{"a", "muted"}},
+		{"not muted", mute, filter, template.KV{"alertname": "a"}, []string{"a"}},
+		{"silenced since the escalation started", mute, filter, template.KV{"alertname": "a", "silenced": "true"}, nil},
+		// An escalation should not be lost because of an error.
+		{"mute failed", failed, filter, template.KV{"alertname": "a", "silenced": "true"}, []string{"a"}},
+		{"filter failed", mute, failed, template.KV{"alertname": "a"}, []string{"a", "muted"}},
+	}
+
+	for _, test := range tests {
+		m := newManager(test.mute, test.filter)
+		alert := &template.Alert{Status: "firing", Labels: test.labels}
+		res := m.check(context.Background(), alert, rcvs)
+
+		if len(res) != len(test.receivers) {
+			t.Fatalf("%s: expected receivers %v, got %d receivers", test.name, test.receivers, len(res))
+		}
+		for _, name := range test.receivers {
+			found := false
+			for rcv, ds := range res {
+				if rcv.GetName() != name {
+					continue
+				}
+				found = true
+				if len(ds) != 1 || len(ds[0].Alerts) != 1 {
+					t.Fatalf("%s: expected one alert sent to %s", test.name, name)
+				}
+				if ds[0].Alerts[0] == alert {
+					t.Fatalf("%s: expected the receiver %s to get a copy of the alert", test.name, name)
+				}
+			}
+			if !found {
+				t.Fatalf("%s: expected the alert to be sent to %s", test.name, name)
+			}
+		}
+	}
+}
+
+func TestTrack(t *testing.T) {
+	m := newManager(nil, nil)
+	alert := &template.Alert{Status: "firing", Labels: template.KV{"alertname": "a"}}
+	k := key("policy", alert.Fingerprint())
+
+	m.track("policy", alert)
+	e, ok := m.escalations[k]
+	if !ok || e.timer == nil {
+		t.Fatal("expected the escalation to be started")
+	}
+	e.timer.Stop()
+
+	// The resent alert updates the escalation rather than starting a new one.
+	resent := alert.Clone()
+	resent.Annotations = template.KV{"message": "resent"}
+	m.track("policy", resent)
+	if m.escalations[k] != e || e.alert.Annotations["message"] != "resent" {
+		t.Fatal("expected the escalation to be updated by the resent alert")
+	}
+
+	// The escalation has finished, the resent alert is not escalated again.
+	e.timer = nil
+	m.track("policy", alert)
+	if m.escalations[k] != e || e.timer != nil {
+		t.Fatal("expected the finished escalation to be kept, so that the alert will not be escalated again")
+	}
+}
+
+func TestCancelStage(t *testing.T) {
+	m := newManager(nil, nil)
+	firing := &template.Alert{Status: "firing", Labels: template.KV{"alertname": "a"}}
+	m.escalations[key("policy", firing.Fingerprint())] = &escalation{
+		policy:      "policy",
+		fingerprint: firing.Fingerprint(),
+		alert:       firing,
+		timer:       time.AfterFunc(time.Hour, func() {}),
+	}
+
+	s := NewCancelStage(m)
+	if _, _, err := s.Exec(context.Background(), log.NewNopLogger(), []*template.Alert{firing}); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.escalations) != 1 {
+		t.Fatal("expected the escalation of the firing alert to be kept")
+	}
+
+	// The resolved alert cancels the escalation, even if it will be dropped by the stages after.
+	resolved := &template.Alert{Status: "resolved", Labels: firing.Labels.Clone(), Acknowledgement: &template.Acknowledgement{By: "admin"}}
+	_, output, err := s.Exec(context.Background(), log.NewNopLogger(), []*template.Alert{resolved})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.escalations) != 0 {
+		t.Fatal("expected the escalation of the resolved alert to be cancelled")
+	}
+	if len(output.([]*template.Alert)) != 1 {
+		t.Fatal("expected the alerts to be passed to the next stage unchanged")
+	}
+}
+
+func TestExpire(t *testing.T) {
+	m := newManager(nil, nil)
+	now := time.Now()
+
+	m.escalations["old"] = &escalation{
+		updatedAt: now.Add(-2 * time.Hour),
+		timer:     time.AfterFunc(time.Hour, func() {}),
+	}
+	m.escalations["new"] = &escalation{updatedAt: now.Add(-time.Minute)}
+
+	m.expire(now)
+
+	if _, ok := m.escalations["old"]; ok {
+		t.Fatal("expected the escalation not updated within the retention to be removed")
+	}
+	if _, ok := m.escalations["new"]; !ok {
+		t.Fatal("expected the escalation updated within the retention to be kept")
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "escalations")
+	now := time.Now()
+
+	m := newManager(nil, nil)
+	m.snapshotFile = file
+	alert := &template.Alert{Status: "firing", Labels: template.KV{"alertname": "a"}}
+	m.escalations["running"] = &escalation{
+		policy:      "policy",
+		fingerprint: alert.Fingerprint(),
+		alert:       alert,
+		startsAt:    now.Add(-time.Minute),
+		updatedAt:   now,
+		step:        1,
+		timer:       time.AfterFunc(time.Hour, func() {}),
+	}
+	m.escalations["finished"] = &escalation{
+		policy:      "policy",
+		fingerprint: "finished",
+		alert:       alert,
+		updatedAt:   now,
+		step:        2,
+	}
+	m.escalations["expired"] = &escalation{
+		policy:    "policy",
+		alert:     alert,
+		updatedAt: now.Add(-2 * time.Hour),
+		timer:     time.AfterFunc(time.Hour, func() {}),
+	}
+	if err := m.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := newManager(nil, nil)
+	loaded.snapshotFile = file
+	unfinished, err := loaded.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unfinished) != 1 || unfinished[0] != "running" {
+		t.Fatalf("expected the running escalation to be scheduled, got %v", unfinished)
+	}
+
+	if _, ok := loaded.escalations["expired"]; ok {
+		t.Fatal("expected the expired escalation to be dropped")
+	}
+	running, ok := loaded.escalations["running"]
+	if !ok || running.step != 1 || running.fingerprint != alert.Fingerprint() || !running.startsAt.Equal(now.Add(-time.Minute)) {
+		t.Fatalf("expected the running escalation to be loaded, got %+v", running)
+	}
+	if _, ok := loaded.escalations["finished"]; !ok {
+		t.Fatal("expected the finished escalation to be loaded, so that the alert will not be escalated again")
+	}
+}
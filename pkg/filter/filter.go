@@ -2,24 +2,39 @@ package filter
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/kubesphere/notification-manager/apis/v2beta2"
 	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/inhibit"
 	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/matcher"
 	"github.com/kubesphere/notification-manager/pkg/stage"
+	"github.com/kubesphere/notification-manager/pkg/status"
 	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/kubesphere/notification-manager/pkg/utils"
 	"github.com/modern-go/reflect2"
 )
 
+// filterController is the part of the controller which the filter stage depends on.
+type filterController interface {
+	GetActiveSilences(ctx context.Context, tenant string) ([]v2beta2.Silence, error)
+	GetActiveInhibitors(ctx context.Context, tenant string) ([]v2beta2.Inhibitor, error)
+}
+
 type filterStage struct {
-	notifierCtl *controller.Controller
+	notifierCtl filterController
+	inhibitions *inhibit.Cache
+	recorder    *status.Recorder
 }
 
-func NewStage(notifierCtl *controller.Controller) stage.Stage {
+func NewStage(notifierCtl *controller.Controller, inhibitions *inhibit.Cache, recorder *status.Recorder) stage.Stage {
 	return &filterStage{
 		notifierCtl,
+		inhibitions,
+		recorder,
 	}
 }
 
@@ -40,7 +55,13 @@ func (s *filterStage) Exec(ctx context.Context, l log.Logger, data interface{})
 			return ctx, data, err
 		}
 
-		as, err = filter(as, receiver.GetAlertSelector())
+		as, err = s.inhibit(ctx, as, receiver)
+		if err != nil {
+			_ = level.Error(l).Log("msg", "Inhibit failed", "stage", "Filter", "seq", ctx.Value("seq"), "tenant", receiver.GetTenantID(), "error", err.Error())
+			return ctx, data, err
+		}
+
+		as, err = filter(as, receiver)
 		if err != nil {
 			_ = level.Error(l).Log("msg", "Filter failed", "stage", "Filter", "seq", ctx.Value("seq"), "error", err.Error(), "receiver", receiver.GetName())
 			return ctx, nil, err
@@ -52,6 +73,7 @@ func (s *filterStage) Exec(ctx context.Context, l log.Logger, data interface{})
 	return ctx, res, nil
 }
 
+// mute drops the alerts muted by the active silences of the receiver's tenant which are applied to the receiver.
 func (s *filterStage) mute(ctx context.Context, alerts []*template.Alert, receiver internal.Receiver) ([]*template.Alert, error) {
 
 	silences, err := s.notifierCtl.GetActiveSilences(ctx, receiver.GetTenantID())
@@ -59,6 +81,20 @@ func (s *filterStage) mute(ctx context.Context, alerts []*template.Alert, receiv
 		return nil, err
 	}
 
+	// The global silences which are applied to specific receivers are skipped in the silence stage, and applied here.
+	if !utils.StringIsNil(receiver.GetTenantID()) {
+		globals, err := s.notifierCtl.GetActiveSilences(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, silence := range globals {
+			if silence.Spec.Receivers != nil {
+				silences = append(silences, silence)
+			}
+		}
+	}
+
 	if len(silences) == 0 {
 		return alerts, nil
 	}
@@ -71,8 +107,13 @@ func (s *filterStage) mute(ctx context.Context, alerts []*template.Alert, receiv
 				continue
 			}
 
-			if v2beta2.LabelMatchSelector(alert.Labels, silence.Spec.Matcher) {
+			if !matchReceiver(&silence, receiver) {
+				continue
+			}
+
+			if matcher.Matches(matcher.SilenceKey(silence.Name), matcher.Version(&silence), silence.Spec.Matcher, alert.Labels) {
 				flag = true
+				s.recorder.SilenceSuppressed(silence.Name)
 				break
 			}
 		}
@@ -85,20 +126,61 @@ func (s *filterStage) mute(ctx context.Context, alerts []*template.Alert, receiv
 	return as, err
 }
 
-// FilterAlerts filter the alerts with label selector,if the selector is not correct, return all of the alerts.
-func filter(alerts []*template.Alert, selector *v2beta2.LabelSelector) ([]*template.Alert, error) {
+// matchReceiver returns true if the receiver is selected by the silence, the label selector of the receivers is compiled
+// by the matcher cache.
+func matchReceiver(silence *v2beta2.Silence, receiver internal.Receiver) bool {
 
-	if selector == nil {
+	var selector *v2beta2.Matcher
+	if rs := silence.Spec.Receivers; rs != nil && rs.Selector != nil {
+		m, err := matcher.Get(matcher.SilenceReceiverKey(silence.Name), matcher.Version(silence), rs.Selector)
+		if err != nil {
+			return false
+		}
+		selector = m
+	}
+
+	return silence.MatchReceiver(receiver.GetName(), receiver.GetType(), receiver.GetLabels(), selector)
+}
+
+// inhibit drops the alerts inhibited by the inhibitors of the receiver's tenant.
+func (s *filterStage) inhibit(ctx context.Context, alerts []*template.Alert, receiver internal.Receiver) ([]*template.Alert, error) {
+
+	inhibitors, err := s.notifierCtl.GetActiveInhibitors(ctx, receiver.GetTenantID())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(inhibitors) == 0 {
 		return alerts, nil
 	}
 
 	var as []*template.Alert
 	for _, alert := range alerts {
-		ok, err := selector.Matches(alert.Labels)
-		if err != nil {
-			return nil, err
+		if !s.inhibitions.Inhibited(inhibitors, alert) {
+			as = append(as, alert)
 		}
-		if ok {
+	}
+
+	return as, nil
+}
+
+// FilterAlerts filter the alerts with the alert selector of the receiver, if the selector is not correct, return an error.
+func filter(alerts []*template.Alert, receiver internal.Receiver) ([]*template.Alert, error) {
+
+	selector := receiver.GetAlertSelector()
+	if selector == nil {
+		return alerts, nil
+	}
+
+	key := matcher.ReceiverKey(receiver.GetTenantID(), receiver.GetType(), receiver.GetName())
+	m, err := matcher.Get(key, strconv.FormatUint(receiver.GetResourceVersion(), 10), selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var as []*template.Alert
+	for _, alert := range alerts {
+		if m.Matches(alert.Labels) {
 			as = append(as, alert)
 		}
 	}
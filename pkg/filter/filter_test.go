@@ -0,0 +1,113 @@
+package filter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/internal/webhook"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeController struct {
+	// The silences of each tenant, the tenant of the global silences is empty.
+	silences map[string][]v2beta2.Silence
+}
+
+func (c *fakeController) GetActiveSilences(_ context.Context, tenant string) ([]v2beta2.Silence, error) {
+	return c.silences[tenant], nil
+}
+
+func (c *fakeController) GetActiveInhibitors(_ context.Context, _ string) ([]v2beta2.Inhibitor, error) {
+	return nil, nil
+}
+
+func newReceiver(tenant, receiverType, name string, labels map[string]string) internal.Receiver {
+	return &webhook.Receiver{Common: &internal.Common{Name: name, TenantID: tenant, Type: receiverType, Labels: labels}}
+}
+
+func newSilence(name string, generation int64, receivers *v2beta2.SilenceReceiverSelector) v2beta2.Silence {
+	return v2beta2.Silence{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Generation: generation},
+		Spec: v2beta2.SilenceSpec{
+			Matcher:   &v2beta2.LabelSelector{MatchLabels: map[string]string{"alertname": "a"}},
+			Receivers: receivers,
+		},
+	}
+}
+
+func TestMute(t *testing.T) {
+	alerts := []*template.Alert{
+		{Labels: template.KV{"alertname": "a"}},
+		{Labels: template.KV{"alertname": "b"}},
+	}
+
+	ops := newReceiver("user", "slack", "ops", map[string]string{"team": "ops"})
+	dev := newReceiver("user", "email", "dev", map[string]string{"team": "dev"})
+	tests := []struct {
+		name      string
+		receivers *v2beta2.SilenceReceiverSelector
+		global    bool
+		// Whether the alert a sent to the receivers ops and dev is muted.
+		muted map[internal.Receiver]bool
+	}{
+		{"all receivers", nil, false, map[internal.Receiver]bool{ops: true, dev: true}},
+		{"name", &v2beta2.SilenceReceiverSelector{Name: []string{"ops"}}, false, map[internal.Receiver]bool{ops: true, dev: false}},
+		{"regexName", &v2beta2.SilenceReceiverSelector{RegexName: "^d.*"}, false, map[internal.Receiver]bool{ops: false, dev: true}},
+		{"name or regexName", &v2beta2.SilenceReceiverSelector{Name: []string{"ops"}, RegexName: "^d.*"}, false, map[internal.Receiver]bool{ops: true, dev: true}},
+		{"type", &v2beta2.SilenceReceiverSelector{Type: []string{"email"}}, false, map[internal.Receiver]bool{ops: false, dev: true}},
+		{"selector", &v2beta2.SilenceReceiverSelector{Selector: &v2beta2.LabelSelector{MatchLabels: map[string]string{"team": "ops"}}}, false, map[internal.Receiver]bool{ops: true, dev: false}},
+		{"all fields must match", &v2beta2.SilenceReceiverSelector{Name: []string{"ops"}, Type: []string{"email"}}, false, map[internal.Receiver]bool{ops: false, dev: false}},
+		{"invalid selector", &v2beta2.SilenceReceiverSelector{Selector: &v2beta2.LabelSelector{MatchExpressions: []v2beta2.LabelSelectorRequirement{{Key: "team", Operator: v2beta2.LabelSelectorOpMatch, RegexValue: "("}}}}, false, map[internal.Receiver]bool{ops: false, dev: false}},
+		// The global silences which are applied to specific receivers are applied to the receivers of all the tenants.
+		{"global with receivers", &v2beta2.SilenceReceiverSelector{Type: []string{"slack"}}, true, map[internal.Receiver]bool{ops: true, dev: false}},
+		// The global silences without receivers are applied in the silence stage rather than here.
+		{"global without receivers", nil, true, map[internal.Receiver]bool{ops: false, dev: false}},
+	}
+
+	for _, test := range tests {
+		tenant := "user"
+		if test.global {
+			tenant = ""
+		}
+		s := &filterStage{notifierCtl: &fakeController{silences: map[string][]v2beta2.Silence{
+			tenant: {newSilence(test.name, 1, test.receivers)},
+		}}}
+
+		for _, receiver := range []internal.Receiver{ops, dev} {
+			as, err := s.mute(context.Background(), alerts, receiver)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The alert b never matches the silence.
+			expected := 1
+			if !test.muted[receiver] {
+				expected = 2
+			}
+			if len(as) != expected {
+				t.Fatalf("%s: expected %d alerts sent to %s, got %d", test.name, expected, receiver.GetName(), len(as))
+			}
+		}
+	}
+}
+
+func TestMuteSelectorUpdated(t *testing.T) {
+	alerts := []*template.Alert{{Labels: template.KV{"alertname": "a"}}}
+	ops := newReceiver("user", "slack", "ops", map[string]string{"team": "ops"})
+
+	ctl := &fakeController{silences: map[string][]v2beta2.Silence{
+		"user": {newSilence("updated", 1, &v2beta2.SilenceReceiverSelector{Selector: &v2beta2.LabelSelector{MatchLabels: map[string]string{"team": "ops"}}})},
+	}}
+	s := &filterStage{notifierCtl: ctl}
+	if as, _ := s.mute(context.Background(), alerts, ops); len(as) != 0 {
+		t.Fatal("expected the alert to be muted")
+	}
+
+	// The compiled selector of the receivers is cached until the silence is updated.
+	ctl.silences["user"] = []v2beta2.Silence{newSilence("updated", 2, &v2beta2.SilenceReceiverSelector{Selector: &v2beta2.LabelSelector{MatchLabels: map[string]string{"team": "dev"}}})}
+	if as, _ := s.mute(context.Background(), alerts, ops); len(as) != 1 {
+		t.Fatal("expected the updated selector to be used")
+	}
+}
@@ -0,0 +1,250 @@
+package inhibit
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/constants"
+	"github.com/kubesphere/notification-manager/pkg/matcher"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	retention *time.Duration
+)
+
+func init() {
+	retention = kingpin.Flag(
+		"inhibit.retention",
+		"How long to remember a firing alert as the source alert of the inhibitors if it is neither resolved nor resent",
+	).Default("6h").Duration()
+}
+
+type entry struct {
+	alert     *template.Alert
+	expiresAt time.Time
+}
+
+// sourceIndex indexes the firing alerts which match the source of an inhibitor by the values of the equal labels,
+// so that the source alerts of a target alert can be found without scanning all the alerts.
+type sourceIndex struct {
+	version string
+	source  *v2beta2.Matcher
+	equal   []string
+	// The equal key to the fingerprints of the source alerts.
+	alerts map[string]map[string]*entry
+	used   int32
+}
+
+func (idx *sourceIndex) add(fingerprint string, e *entry) {
+	if !idx.source.Matches(e.alert.Labels) {
+		idx.remove(fingerprint, e.alert)
+		return
+	}
+
+	k := equalKey(e.alert, idx.equal)
+	m, ok := idx.alerts[k]
+	if !ok {
+		m = make(map[string]*entry)
+		idx.alerts[k] = m
+	}
+	m[fingerprint] = e
+}
+
+func (idx *sourceIndex) remove(fingerprint string, alert *template.Alert) {
+	k := equalKey(alert, idx.equal)
+	if m, ok := idx.alerts[k]; ok {
+		delete(m, fingerprint)
+		if len(m) == 0 {
+			delete(idx.alerts, k)
+		}
+	}
+}
+
+// Cache remembers the firing alerts, which are used as the source alerts of the inhibitors.
+// An alert will be forgotten when it is resolved, or it is not resent within the retention time.
+// The source alerts of every inhibitor are indexed by the values of the equal labels, like Alertmanager does.
+type Cache struct {
+	mutex     sync.RWMutex
+	alerts    map[string]*entry
+	indexes   map[string]*sourceIndex
+	retention time.Duration
+}
+
+func NewCache() *Cache {
+	c := &Cache{
+		alerts:    make(map[string]*entry),
+		indexes:   make(map[string]*sourceIndex),
+		retention: *retention,
+	}
+
+	go c.gc()
+	return c
+}
+
+// Observe remembers the firing alerts and forgets the resolved alerts.
+func (c *Cache) Observe(alerts []*template.Alert) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, alert := range alerts {
+		fingerprint := alert.Fingerprint()
+		if alert.Status == constants.AlertResolved {
+			c.forget(fingerprint)
+			continue
+		}
+
+		e := &entry{
+			alert:     alert,
+			expiresAt: time.Now().Add(c.retention),
+		}
+		c.alerts[fingerprint] = e
+		for _, idx := range c.indexes {
+			idx.add(fingerprint, e)
+		}
+	}
+}
+
+// forget removes the alert from the cache and the indexes, it must be called with the lock held.
+func (c *Cache) forget(fingerprint string) {
+	e, ok := c.alerts[fingerprint]
+	if !ok {
+		return
+	}
+
+	delete(c.alerts, fingerprint)
+	for _, idx := range c.indexes {
+		idx.remove(fingerprint, e.alert)
+	}
+}
+
+// Inhibited returns true if the alert is inhibited by any of the inhibitors.
+// An alert is inhibited if it matches the target of an inhibitor, and there is a firing alert other than itself
+// which matches the source of the inhibitor and has the same values of the equal labels.
+// The inhibitors with an invalid source or target never inhibit any alert.
+func (c *Cache) Inhibited(inhibitors []v2beta2.Inhibitor, alert *template.Alert) bool {
+
+	if alert.Status == constants.AlertResolved {
+		return false
+	}
+
+	fingerprint := alert.Fingerprint()
+	now := time.Now()
+	for _, inhibitor := range inhibitors {
+		if inhibitor.Spec.Source == nil || inhibitor.Spec.Target == nil {
+			continue
+		}
+
+		target, err := matcher.Get(matcher.InhibitorKey(inhibitor.Name, "target"), matcher.Version(&inhibitor), inhibitor.Spec.Target)
+		if err != nil || !target.Matches(alert.Labels) {
+			continue
+		}
+
+		if c.inhibitedBy(&inhibitor, alert, fingerprint, now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inhibitedBy returns true if there is a source alert of the inhibitor which inhibits the alert.
+func (c *Cache) inhibitedBy(inhibitor *v2beta2.Inhibitor, alert *template.Alert, fingerprint string, now time.Time) bool {
+
+	c.mutex.RLock()
+	idx, ok := c.indexes[inhibitor.Name]
+	if ok && idx.version == matcher.Version(inhibitor) {
+		atomic.StoreInt32(&idx.used, 1)
+		defer c.mutex.RUnlock()
+		return idx.inhibits(alert, fingerprint, now)
+	}
+	c.mutex.RUnlock()
+
+	source, err := matcher.Get(matcher.InhibitorKey(inhibitor.Name, "source"), matcher.Version(inhibitor), inhibitor.Spec.Source)
+	if err != nil {
+		return false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// The index may have been built by another goroutine.
+	idx, ok = c.indexes[inhibitor.Name]
+	if !ok || idx.version != matcher.Version(inhibitor) {
+		idx = &sourceIndex{
+			version: matcher.Version(inhibitor),
+			source:  source,
+			equal:   inhibitor.Spec.Equal,
+			alerts:  make(map[string]map[string]*entry),
+		}
+		for fp, e := range c.alerts {
+			idx.add(fp, e)
+		}
+		c.indexes[inhibitor.Name] = idx
+	}
+	atomic.StoreInt32(&idx.used, 1)
+
+	return idx.inhibits(alert, fingerprint, now)
+}
+
+// inhibits returns true if there is a firing source alert other than the alert itself
+// which has the same values of the equal labels, it must be called with the lock held.
+func (idx *sourceIndex) inhibits(alert *template.Alert, fingerprint string, now time.Time) bool {
+	for fp, e := range idx.alerts[equalKey(alert, idx.equal)] {
+		if fp != fingerprint && !e.expiresAt.Before(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// equalKey returns the values of the equal labels of the alert, the alerts with the same key have the same values.
+func equalKey(alert *template.Alert, labels []string) string {
+	var sb strings.Builder
+	for _, label := range labels {
+		v := alert.Labels[label]
+		// The length is written before every value, so that the values containing the separator will not collide.
+		sb.WriteString(strconv.Itoa(len(v)))
+		sb.WriteByte(':')
+		sb.WriteString(v)
+	}
+
+	return sb.String()
+}
+
+func (c *Cache) gc() {
+	if c.retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(c.retention / 10)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.expire(time.Now())
+	}
+}
+
+// expire removes the expired alerts, and the indexes of the inhibitors which have not been used since the last time.
+func (c *Cache) expire(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for fp, e := range c.alerts {
+		if e.expiresAt.Before(now) {
+			c.forget(fp)
+		}
+	}
+
+	for name, idx := range c.indexes {
+		if atomic.SwapInt32(&idx.used, 0) == 0 {
+			delete(c.indexes, name)
+		}
+	}
+}
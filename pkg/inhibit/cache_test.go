@@ -0,0 +1,159 @@
+package inhibit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/constants"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newAlert(status string, labels template.KV) *template.Alert {
+	return &template.Alert{
+		Status: status,
+		Labels: labels,
+	}
+}
+
+func newCache() *Cache {
+	return &Cache{
+		alerts:    make(map[string]*entry),
+		indexes:   make(map[string]*sourceIndex),
+		retention: time.Hour,
+	}
+}
+
+func TestInhibited(t *testing.T) {
+	inhibitors := []v2beta2.Inhibitor{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "inhibited", Generation: 1},
+			Spec: v2beta2.InhibitorSpec{
+				Source: &v2beta2.LabelSelector{MatchLabels: map[string]string{"severity": "critical"}},
+				Target: &v2beta2.LabelSelector{MatchLabels: map[string]string{"severity": "warning"}},
+				Equal:  []string{"namespace"},
+			},
+		},
+	}
+
+	c := newCache()
+	source := newAlert(constants.AlertFiring, template.KV{"alertname": "a", "severity": "critical", "namespace": "default"})
+	c.Observe([]*template.Alert{source})
+
+	tests := []struct {
+		name      string
+		alert     *template.Alert
+		inhibited bool
+	}{
+		{"target with equal labels", newAlert(constants.AlertFiring, template.KV{"alertname": "b", "severity": "warning", "namespace": "default"}), true},
+		{"target with different labels", newAlert(constants.AlertFiring, template.KV{"alertname": "b", "severity": "warning", "namespace": "kube-system"}), false},
+		{"not a target", newAlert(constants.AlertFiring, template.KV{"alertname": "b", "severity": "info", "namespace": "default"}), false},
+		{"resolved target", newAlert(constants.AlertResolved, template.KV{"alertname": "b", "severity": "warning", "namespace": "default"}), false},
+	}
+
+	for _, test := range tests {
+		if got := c.Inhibited(inhibitors, test.alert); got != test.inhibited {
+			t.Fatalf("%s: expected inhibited %v, got %v", test.name, test.inhibited, got)
+		}
+	}
+
+	target := tests[0].alert
+	// The source alert is forgotten after it is resolved.
+	c.Observe([]*template.Alert{newAlert(constants.AlertResolved, source.Labels)})
+	if c.Inhibited(inhibitors, target) {
+		t.Fatal("expected the resolved source alert not to inhibit")
+	}
+
+	// The source alert expires if it is not resent within the retention.
+	c.Observe([]*template.Alert{source})
+	for _, e := range c.alerts {
+		e.expiresAt = time.Now().Add(-time.Second)
+	}
+	if c.Inhibited(inhibitors, target) {
+		t.Fatal("expected the expired source alert not to inhibit")
+	}
+}
+
+func TestNotInhibitSelf(t *testing.T) {
+	inhibitors := []v2beta2.Inhibitor{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "self", Generation: 1},
+			Spec: v2beta2.InhibitorSpec{
+				Source: &v2beta2.LabelSelector{MatchLabels: map[string]string{"alertname": "a"}},
+				Target: &v2beta2.LabelSelector{MatchLabels: map[string]string{"alertname": "a"}},
+			},
+		},
+	}
+
+	c := newCache()
+	alert := newAlert(constants.AlertFiring, template.KV{"alertname": "a"})
+	c.Observe([]*template.Alert{alert})
+	if c.Inhibited(inhibitors, alert) {
+		t.Fatal("expected an alert never to inhibit itself")
+	}
+}
+
+func TestSourceIndex(t *testing.T) {
+	inhibitor := v2beta2.Inhibitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "index", ResourceVersion: "1", Generation: 1},
+		Spec: v2beta2.InhibitorSpec{
+			Source: &v2beta2.LabelSelector{MatchLabels: map[string]string{"severity": "critical"}},
+			Target: &v2beta2.LabelSelector{MatchLabels: map[string]string{"severity": "warning"}},
+			Equal:  []string{"namespace", "pod"},
+		},
+	}
+
+	c := newCache()
+	target := newAlert(constants.AlertFiring, template.KV{"alertname": "b", "severity": "warning", "namespace": "default", "pod": "p"})
+	// The index is built when the inhibitor is used first.
+	if c.Inhibited([]v2beta2.Inhibitor{inhibitor}, target) {
+		t.Fatal("expected no inhibition without source alerts")
+	}
+	if _, ok := c.indexes[inhibitor.Name]; !ok {
+		t.Fatal("expected the index of the inhibitor to be built")
+	}
+
+	// The alerts observed later are added to the index.
+	source := newAlert(constants.AlertFiring, template.KV{"alertname": "a", "severity": "critical", "namespace": "default", "pod": "p"})
+	c.Observe([]*template.Alert{source})
+	if !c.Inhibited([]v2beta2.Inhibitor{inhibitor}, target) {
+		t.Fatal("expected the target to be inhibited by the source observed later")
+	}
+
+	// The values of the equal labels must not collide when they are joined.
+	collided := newAlert(constants.AlertFiring, template.KV{"alertname": "b", "severity": "warning", "namespace": "defaultp", "pod": ""})
+	if c.Inhibited([]v2beta2.Inhibitor{inhibitor}, collided) {
+		t.Fatal("expected the target with different equal labels not to be inhibited")
+	}
+
+	// The index is kept when only the status of the inhibitor is written.
+	written := inhibitor
+	written.ResourceVersion = "2"
+	idx := c.indexes[inhibitor.Name]
+	if !c.Inhibited([]v2beta2.Inhibitor{written}, target) || c.indexes[inhibitor.Name] != idx {
+		t.Fatal("expected the index to be kept when the generation is not changed")
+	}
+
+	// The index is rebuilt when the spec of the inhibitor is updated.
+	updated := inhibitor
+	updated.ResourceVersion = "3"
+	updated.Generation = 2
+	updated.Spec.Source = &v2beta2.LabelSelector{MatchLabels: map[string]string{"severity": "error"}}
+	if c.Inhibited([]v2beta2.Inhibitor{updated}, target) {
+		t.Fatal("expected the updated inhibitor not to match the source")
+	}
+	if c.indexes[inhibitor.Name].version != "2" {
+		t.Fatal("expected the index to be rebuilt for the updated inhibitor")
+	}
+
+	// The index which is not used between two expirations is removed.
+	c.expire(time.Now())
+	if _, ok := c.indexes[inhibitor.Name]; !ok {
+		t.Fatal("expected the used index to be kept")
+	}
+	c.expire(time.Now())
+	if _, ok := c.indexes[inhibitor.Name]; ok {
+		t.Fatal("expected the unused index to be removed")
+	}
+}
@@ -0,0 +1,79 @@
+package inhibit
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/stage"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/modern-go/reflect2"
+)
+
+type inhibitStage struct {
+	notifierCtl *controller.Controller
+	cache       *Cache
+}
+
+// NewStage returns a stage which drops the alerts inhibited by the global inhibitors.
+// The tenant inhibitors are applied in the filter stage. The source alerts are remembered by the observe stage.
+func NewStage(notifierCtl *controller.Controller, cache *Cache) stage.Stage {
+	return &inhibitStage{
+		notifierCtl: notifierCtl,
+		cache:       cache,
+	}
+}
+
+func (s *inhibitStage) Exec(ctx context.Context, l log.Logger, data interface{}) (context.Context, interface{}, error) {
+	if reflect2.IsNil(data) {
+		return ctx, nil, nil
+	}
+
+	input := data.([]*template.Alert)
+
+	_ = level.Debug(l).Log("msg", "Start inhibit stage", "seq", ctx.Value("seq"), "alert", len(input))
+
+	inhibitors, err := s.notifierCtl.GetActiveInhibitors(ctx, "")
+	if err != nil {
+		_ = level.Error(l).Log("msg", "Get inhibitor failed", "stage", "Inhibit", "seq", ctx.Value("seq"), "error", err.Error())
+		return ctx, nil, err
+	}
+
+	if len(inhibitors) == 0 {
+		return ctx, input, nil
+	}
+
+	var output []*template.Alert
+	for _, alert := range input {
+		if !s.cache.Inhibited(inhibitors, alert) {
+			output = append(output, alert)
+		}
+	}
+
+	return ctx, output, nil
+}
+
+type observeStage struct {
+	cache *Cache
+}
+
+// NewObserveStage returns a stage which remembers the firing alerts as the source alerts of the inhibitors.
+// It must run before the alerts are silenced, so that a silenced alert can still inhibit other alerts like Alertmanager.
+// The data is passed to the next stage unchanged.
+func NewObserveStage(cache *Cache) stage.Stage {
+	return &observeStage{
+		cache: cache,
+	}
+}
+
+func (s *observeStage) Exec(ctx context.Context, l log.Logger, data interface{}) (context.Context, interface{}, error) {
+	if reflect2.IsNil(data) {
+		return ctx, nil, nil
+	}
+
+	_ = level.Debug(l).Log("msg", "Start inhibit observe stage", "seq", ctx.Value("seq"))
+
+	s.cache.Observe(data.([]*template.Alert))
+	return ctx, data, nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/kubesphere/notification-manager/apis/v2beta2"
 )
@@ -19,6 +21,78 @@ type Template struct {
 	TmplText      *v2beta2.ConfigmapKeySelector `json:"tmplText,omitempty"`
 }
 
+// GroupOptions defines how to group the alerts sent to the receiver.
+type GroupOptions struct {
+	// Labels for grouping the alerts.
+	GroupLabels []string `json:"groupLabels,omitempty"`
+	// How long to wait before sending the first notification of a group.
+	GroupWait *time.Duration `json:"groupWait,omitempty"`
+	// How long to wait before sending the next notification of a group.
+	GroupInterval *time.Duration `json:"groupInterval,omitempty"`
+}
+
+// NewGroupOptions returns the group options with the group labels, nil will be returned if no group labels.
+func NewGroupOptions(groupLabels []string) *GroupOptions {
+	if len(groupLabels) == 0 {
+		return nil
+	}
+
+	return &GroupOptions{
+		GroupLabels: groupLabels,
+	}
+}
+
+// Merge returns the group options which use the values set in o first, and then the values set in the given options.
+func (o *GroupOptions) Merge(other *GroupOptions) *GroupOptions {
+	if o == nil {
+		return other
+	}
+
+	if other == nil {
+		return o
+	}
+
+	res := *o
+	if res.GroupLabels == nil {
+		res.GroupLabels = other.GroupLabels
+	}
+	if res.GroupWait == nil {
+		res.GroupWait = other.GroupWait
+	}
+	if res.GroupInterval == nil {
+		res.GroupInterval = other.GroupInterval
+	}
+
+	return &res
+}
+
+// String returns the canonical representation of the group options, the unset options are represented by "-".
+func (o *GroupOptions) String() string {
+	if o == nil {
+		return "-"
+	}
+
+	labels := "-"
+	if o.GroupLabels != nil {
+		labels = strings.Join(o.GroupLabels, ",")
+	}
+	wait, interval := "-", "-"
+	if o.GroupWait != nil {
+		wait = o.GroupWait.String()
+	}
+	if o.GroupInterval != nil {
+		interval = o.GroupInterval.String()
+	}
+
+	return fmt.Sprintf("%s/%s/%s", labels, wait, interval)
+}
+
+// RouteKey returns the key which identifies the receiver together with the options set by the routers.
+// The alerts sent to the same receiver with different options must not be put together.
+func RouteKey(r Receiver) string {
+	return fmt.Sprintf("%s/%s/%s", r.GetHash(), r.GetGroupOptions().String(), r.GetEscalationPolicy())
+}
+
 type Common struct {
 	Name            string                 `json:"name,omitempty"`
 	ResourceVersion uint64                 `json:"resourceVersion,omitempty"`
@@ -30,6 +104,9 @@ type Common struct {
 	ConfigSelector  *v2beta2.LabelSelector `json:"configSelector,omitempty"`
 	Hash            string                 `json:"hash,omitempty"`
 	Template        `json:"template,omitempty"`
+	GroupOptions    *GroupOptions `json:"groupOptions,omitempty"`
+	// The name of the EscalationPolicy used to escalate the firing alerts sent to the receiver.
+	EscalationPolicy string `json:"escalationPolicy,omitempty"`
 }
 
 func (c *Common) GetName() string {
@@ -89,6 +166,22 @@ func (c *Common) GetHash() string {
 	return c.Hash
 }
 
+func (c *Common) GetGroupOptions() *GroupOptions {
+	return c.GroupOptions
+}
+
+func (c *Common) SetGroupOptions(o *GroupOptions) {
+	c.GroupOptions = o
+}
+
+func (c *Common) GetEscalationPolicy() string {
+	return c.EscalationPolicy
+}
+
+func (c *Common) SetEscalationPolicy(name string) {
+	c.EscalationPolicy = name
+}
+
 func (c *Common) Clone() *Common {
 
 	return &Common{
@@ -106,6 +199,8 @@ func (c *Common) Clone() *Common {
 			TmplType:      c.TmplType,
 			TmplText:      c.TmplText,
 		},
+		GroupOptions:     c.GroupOptions,
+		EscalationPolicy: c.EscalationPolicy,
 	}
 }
 
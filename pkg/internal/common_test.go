@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGroupOptionsString(t *testing.T) {
+	wait := 30 * time.Second
+	tests := []struct {
+		opts     *GroupOptions
+		expected string
+	}{
+		{nil, "-"},
+		{&GroupOptions{}, "-/-/-"},
+		{&GroupOptions{GroupLabels: []string{}}, "/-/-"},
+		{&GroupOptions{GroupLabels: []string{"alertname", "namespace"}, GroupWait: &wait}, "alertname,namespace/30s/-"},
+	}
+
+	for _, test := range tests {
+		if got := test.opts.String(); got != test.expected {
+			t.Fatalf("expected %s, got %s", test.expected, got)
+		}
+	}
+}
@@ -38,13 +38,15 @@ func NewReceiver(tenantID string, obj *v2beta2.Receiver) internal.Receiver {
 
 	r := &Receiver{
 		Common: &internal.Common{
-			Name:           obj.Name,
-			TenantID:       tenantID,
-			Type:           constants.DingTalk,
-			Labels:         obj.Labels,
-			Enable:         dingtalk.Enabled,
-			AlertSelector:  dingtalk.AlertSelector,
-			ConfigSelector: dingtalk.DingTalkConfigSelector,
+			Name:             obj.Name,
+			TenantID:         tenantID,
+			Type:             constants.DingTalk,
+			Labels:           obj.Labels,
+			Enable:           dingtalk.Enabled,
+			AlertSelector:    dingtalk.AlertSelector,
+			GroupOptions:     internal.NewGroupOptions(dingtalk.GroupLabels),
+			EscalationPolicy: dingtalk.EscalationPolicy,
+			ConfigSelector:   dingtalk.DingTalkConfigSelector,
 			Template: internal.Template{
 				TmplText: dingtalk.TmplText,
 			},
@@ -23,12 +23,14 @@ func NewReceiver(tenantID string, obj *v2beta2.Receiver) internal.Receiver {
 	discord := obj.Spec.Discord
 	r := &Receiver{
 		Common: &internal.Common{
-			Name:          obj.Name,
-			TenantID:      tenantID,
-			Type:          constants.Discord,
-			Labels:        obj.Labels,
-			Enable:        discord.Enabled,
-			AlertSelector: discord.AlertSelector,
+			Name:             obj.Name,
+			TenantID:         tenantID,
+			Type:             constants.Discord,
+			Labels:           obj.Labels,
+			Enable:           discord.Enabled,
+			AlertSelector:    discord.AlertSelector,
+			GroupOptions:     internal.NewGroupOptions(discord.GroupLabels),
+			EscalationPolicy: discord.EscalationPolicy,
 			Template: internal.Template{
 				TmplName: *discord.Template,
 				TmplText: discord.TmplText,
@@ -26,13 +26,15 @@ func NewReceiver(tenantID string, obj *v2beta2.Receiver) internal.Receiver {
 	e := obj.Spec.Email
 	r := &Receiver{
 		Common: &internal.Common{
-			Name:           obj.Name,
-			TenantID:       tenantID,
-			Type:           constants.Email,
-			Labels:         obj.Labels,
-			Enable:         e.Enabled,
-			AlertSelector:  e.AlertSelector,
-			ConfigSelector: e.EmailConfigSelector,
+			Name:             obj.Name,
+			TenantID:         tenantID,
+			Type:             constants.Email,
+			Labels:           obj.Labels,
+			Enable:           e.Enabled,
+			AlertSelector:    e.AlertSelector,
+			GroupOptions:     internal.NewGroupOptions(e.GroupLabels),
+			EscalationPolicy: e.EscalationPolicy,
+			ConfigSelector:   e.EmailConfigSelector,
 			Template: internal.Template{
 				TmplText: e.TmplText,
 			},
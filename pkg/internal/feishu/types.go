@@ -31,13 +31,15 @@ func NewReceiver(tenantID string, obj *v2beta2.Receiver) internal.Receiver {
 	f := obj.Spec.Feishu
 	r := &Receiver{
 		Common: &internal.Common{
-			Name:           obj.Name,
-			TenantID:       tenantID,
-			Type:           constants.Feishu,
-			Labels:         obj.Labels,
-			Enable:         f.Enabled,
-			AlertSelector:  f.AlertSelector,
-			ConfigSelector: f.FeishuConfigSelector,
+			Name:             obj.Name,
+			TenantID:         tenantID,
+			Type:             constants.Feishu,
+			Labels:           obj.Labels,
+			Enable:           f.Enabled,
+			AlertSelector:    f.AlertSelector,
+			GroupOptions:     internal.NewGroupOptions(f.GroupLabels),
+			EscalationPolicy: f.EscalationPolicy,
+			ConfigSelector:   f.FeishuConfigSelector,
 			Template: internal.Template{
 				TmplText: f.TmplText,
 			},
@@ -19,6 +19,10 @@ type Receiver interface {
 	GetHash() string
 	SetHash(h string)
 	GetChannels() (string, interface{})
+	GetGroupOptions() *GroupOptions
+	SetGroupOptions(o *GroupOptions)
+	GetEscalationPolicy() string
+	SetEscalationPolicy(name string)
 }
 
 type Config interface {
@@ -29,13 +29,15 @@ func NewReceiver(tenantID string, obj *v2beta2.Receiver) internal.Receiver {
 	p := obj.Spec.Pushover
 	r := &Receiver{
 		Common: &internal.Common{
-			Name:           obj.Name,
-			TenantID:       tenantID,
-			Type:           constants.Pushover,
-			Labels:         obj.Labels,
-			Enable:         p.Enabled,
-			AlertSelector:  p.AlertSelector,
-			ConfigSelector: p.PushoverConfigSelector,
+			Name:             obj.Name,
+			TenantID:         tenantID,
+			Type:             constants.Pushover,
+			Labels:           obj.Labels,
+			Enable:           p.Enabled,
+			AlertSelector:    p.AlertSelector,
+			GroupOptions:     internal.NewGroupOptions(p.GroupLabels),
+			EscalationPolicy: p.EscalationPolicy,
+			ConfigSelector:   p.PushoverConfigSelector,
 			Template: internal.Template{
 				TmplText: p.TmplText,
 			},
@@ -24,13 +24,15 @@ func NewReceiver(tenantID string, obj *v2beta2.Receiver) internal.Receiver {
 	s := obj.Spec.Slack
 	r := &Receiver{
 		Common: &internal.Common{
-			Name:           obj.Name,
-			TenantID:       tenantID,
-			Type:           constants.Slack,
-			Labels:         obj.Labels,
-			Enable:         s.Enabled,
-			AlertSelector:  s.AlertSelector,
-			ConfigSelector: s.SlackConfigSelector,
+			Name:             obj.Name,
+			TenantID:         tenantID,
+			Type:             constants.Slack,
+			Labels:           obj.Labels,
+			Enable:           s.Enabled,
+			AlertSelector:    s.AlertSelector,
+			GroupOptions:     internal.NewGroupOptions(s.GroupLabels),
+			EscalationPolicy: s.EscalationPolicy,
+			ConfigSelector:   s.SlackConfigSelector,
 			Template: internal.Template{
 				TmplText: s.TmplText,
 			},
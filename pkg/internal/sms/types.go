@@ -25,13 +25,15 @@ func NewReceiver(tenantID string, obj *v2beta2.Receiver) internal.Receiver {
 	s := obj.Spec.Sms
 	r := &Receiver{
 		Common: &internal.Common{
-			Name:           obj.Name,
-			TenantID:       tenantID,
-			Type:           constants.SMS,
-			Labels:         obj.Labels,
-			Enable:         s.Enabled,
-			AlertSelector:  s.AlertSelector,
-			ConfigSelector: s.SmsConfigSelector,
+			Name:             obj.Name,
+			TenantID:         tenantID,
+			Type:             constants.SMS,
+			Labels:           obj.Labels,
+			Enable:           s.Enabled,
+			AlertSelector:    s.AlertSelector,
+			GroupOptions:     internal.NewGroupOptions(s.GroupLabels),
+			EscalationPolicy: s.EscalationPolicy,
+			ConfigSelector:   s.SmsConfigSelector,
 			Template: internal.Template{
 				TmplText: s.TmplText,
 			},
@@ -26,13 +26,15 @@ func NewReceiver(tenantID string, obj *v2beta2.Receiver) internal.Receiver {
 	telegram := obj.Spec.Telegram
 	r := &Receiver{
 		Common: &internal.Common{
-			Name:           obj.Name,
-			TenantID:       tenantID,
-			Type:           constants.Telegram,
-			Labels:         obj.Labels,
-			Enable:         telegram.Enabled,
-			AlertSelector:  telegram.AlertSelector,
-			ConfigSelector: telegram.TelegramConfigSelector,
+			Name:             obj.Name,
+			TenantID:         tenantID,
+			Type:             constants.Telegram,
+			Labels:           obj.Labels,
+			Enable:           telegram.Enabled,
+			AlertSelector:    telegram.AlertSelector,
+			GroupOptions:     internal.NewGroupOptions(telegram.GroupLabels),
+			EscalationPolicy: telegram.EscalationPolicy,
+			ConfigSelector:   telegram.TelegramConfigSelector,
 			Template: internal.Template{
 				TmplName: *telegram.Template,
 				TmplText: telegram.TmplText,
@@ -25,12 +25,14 @@ func NewReceiver(tenantID string, obj *v2beta2.Receiver) internal.Receiver {
 	w := obj.Spec.Webhook
 	r := &Receiver{
 		Common: &internal.Common{
-			Name:          obj.Name,
-			TenantID:      tenantID,
-			Type:          constants.Webhook,
-			Labels:        obj.Labels,
-			Enable:        w.Enabled,
-			AlertSelector: w.AlertSelector,
+			Name:             obj.Name,
+			TenantID:         tenantID,
+			Type:             constants.Webhook,
+			Labels:           obj.Labels,
+			Enable:           w.Enabled,
+			AlertSelector:    w.AlertSelector,
+			GroupOptions:     internal.NewGroupOptions(w.GroupLabels),
+			EscalationPolicy: w.EscalationPolicy,
 			Template: internal.Template{
 				TmplText: w.TmplText,
 			},
@@ -32,13 +32,15 @@ func NewReceiver(tenantID string, obj *v2beta2.Receiver) internal.Receiver {
 	w := obj.Spec.Wechat
 	r := &Receiver{
 		Common: &internal.Common{
-			Name:           obj.Name,
-			TenantID:       tenantID,
-			Type:           constants.WeChat,
-			Labels:         obj.Labels,
-			Enable:         w.Enabled,
-			AlertSelector:  w.AlertSelector,
-			ConfigSelector: w.WechatConfigSelector,
+			Name:             obj.Name,
+			TenantID:         tenantID,
+			Type:             constants.WeChat,
+			Labels:           obj.Labels,
+			Enable:           w.Enabled,
+			AlertSelector:    w.AlertSelector,
+			GroupOptions:     internal.NewGroupOptions(w.GroupLabels),
+			EscalationPolicy: w.EscalationPolicy,
+			ConfigSelector:   w.WechatConfigSelector,
 			Template: internal.Template{
 				TmplText: w.TmplText,
 			},
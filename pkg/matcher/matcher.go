@@ -0,0 +1,133 @@
+package matcher
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// The matchers which have not been used in this interval will be removed.
+	sweepInterval = 10 * time.Minute
+)
+
+var defaultCache = NewCache()
+
+// Cache caches the compiled matchers of the label selectors in the objects,
+// the matcher of an object is compiled again when the spec of the object changes, see Version.
+type Cache struct {
+	mutex     sync.RWMutex
+	entries   map[string]*entry
+	lastSweep time.Time
+}
+
+type entry struct {
+	version string
+	matcher *v2beta2.Matcher
+	err     error
+	used    int32
+}
+
+func NewCache() *Cache {
+	return &Cache{
+		entries:   make(map[string]*entry),
+		lastSweep: time.Now(),
+	}
+}
+
+// SilenceKey returns the key of the matcher of the silence.
+func SilenceKey(name string) string {
+	return fmt.Sprintf("silence/%s", name)
+}
+
+// SilenceReceiverKey returns the key of the label selector of the receivers to which the silence is applied.
+func SilenceReceiverKey(name string) string {
+	return fmt.Sprintf("silence/%s/receivers", name)
+}
+
+// RouterKey returns the key of the alert selector of the router.
+func RouterKey(name string) string {
+	return fmt.Sprintf("router/%s", name)
+}
+
+// InhibitorKey returns the key of the source or target selector of the inhibitor.
+func InhibitorKey(name, selector string) string {
+	return fmt.Sprintf("inhibitor/%s/%s", name, selector)
+}
+
+// Version returns the version of the spec of the object, which is used to identify the compiled matchers.
+// The generation is used rather than the resourceVersion, because the resourceVersion changes whenever
+// the status is written, and the matchers need not be compiled again for that.
+func Version(obj metav1.Object) string {
+	return strconv.FormatInt(obj.GetGeneration(), 10)
+}
+
+// ReceiverKey returns the key of the alert selector of the receiver.
+func ReceiverKey(tenant, receiverType, name string) string {
+	return fmt.Sprintf("receiver/%s/%s/%s", tenant, receiverType, name)
+}
+
+// Get returns the compiled matcher of the label selector, the key identifies the label selector in the object,
+// such as "silence/<name>/matcher". The error of the compilation is cached as well.
+func (c *Cache) Get(key, version string, selector *v2beta2.LabelSelector) (*v2beta2.Matcher, error) {
+
+	c.mutex.RLock()
+	e, ok := c.entries[key]
+	c.mutex.RUnlock()
+	if ok && e.version == version {
+		atomic.StoreInt32(&e.used, 1)
+		return e.matcher, e.err
+	}
+
+	e = &entry{
+		version: version,
+		used:    1,
+	}
+	e.matcher, e.err = selector.Compile()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[key] = e
+	if time.Since(c.lastSweep) > sweepInterval {
+		c.sweep()
+	}
+
+	return e.matcher, e.err
+}
+
+// sweep removes the matchers which have not been used since the last sweep, it must be called with the lock held.
+func (c *Cache) sweep() {
+	for k, e := range c.entries {
+		if atomic.SwapInt32(&e.used, 0) == 0 {
+			delete(c.entries, k)
+		}
+	}
+	c.lastSweep = time.Now()
+}
+
+// Get returns the compiled matcher of the label selector in the object from the default cache.
+func Get(key, version string, selector *v2beta2.LabelSelector) (*v2beta2.Matcher, error) {
+	return defaultCache.Get(key, version, selector)
+}
+
+// Matches returns true if the labels match the label selector in the object, a nil label selector matches all labels.
+// False will be returned if the label selector is invalid.
+func Matches(key, version string, selector *v2beta2.LabelSelector, label map[string]string) bool {
+
+	if selector == nil {
+		return true
+	}
+
+	m, err := defaultCache.Get(key, version, selector)
+	if err != nil {
+		return false
+	}
+
+	return m.Matches(label)
+}
@@ -0,0 +1,309 @@
+package matcher
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var (
+	selector = &v2beta2.LabelSelector{
+		MatchLabels: map[string]string{
+			"cluster": "host",
+		},
+		MatchExpressions: []v2beta2.LabelSelectorRequirement{
+			{
+				Key:      "severity",
+				Operator: "In",
+				Values:   []string{"warning", "critical"},
+			},
+			{
+				Key:        "namespace",
+				Operator:   v2beta2.LabelSelectorOpMatch,
+				RegexValue: "^kube-.*$",
+			},
+			{
+				Key:        "pod",
+				Operator:   v2beta2.LabelSelectorOpNotMatch,
+				RegexValue: "^test-.*",
+			},
+		},
+	}
+
+	alertLabels = map[string]string{
+		"alertname": "KubePodCrashLooping",
+		"cluster":   "host",
+		"namespace": "kube-system",
+		"pod":       "coredns-5d78c9869d-9xj7b",
+		"severity":  "critical",
+	}
+)
+
+func BenchmarkLabelSelectorMatches(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if ok, err := selector.Matches(alertLabels); err != nil || !ok {
+			b.Fatal("labels should match the selector")
+		}
+	}
+}
+
+func BenchmarkMatcherMatches(b *testing.B) {
+	m, err := selector.Compile()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !m.Matches(alertLabels) {
+			b.Fatal("labels should match the selector")
+		}
+	}
+}
+
+func BenchmarkCachedMatches(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if !Matches(SilenceKey("benchmark"), "1", selector, alertLabels) {
+			b.Fatal("labels should match the selector")
+		}
+	}
+}
+
+func BenchmarkCachedMatchesParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if !Matches(SilenceKey("benchmark"), "1", selector, alertLabels) {
+				b.Fatal("labels should match the selector")
+			}
+		}
+	})
+}
+
+// oldMatches is the implementation of LabelSelector.Matches before the matchers were compiled,
+// the compiled matchers must agree with it.
+func oldMatches(ls *v2beta2.LabelSelector, label map[string]string) (bool, error) {
+	if label == nil {
+		return false, nil
+	}
+
+	selector := &metav1.LabelSelector{
+		MatchLabels: ls.MatchLabels,
+	}
+	for _, requirement := range ls.MatchExpressions {
+		switch requirement.Operator {
+		case v2beta2.LabelSelectorOpMatch:
+			match, err := regexp.MatchString(requirement.RegexValue, label[requirement.Key])
+			if !match {
+				return false, err
+			}
+		case v2beta2.LabelSelectorOpNotMatch:
+			match, err := regexp.MatchString(requirement.RegexValue, label[requirement.Key])
+			if match || err != nil {
+				return false, err
+			}
+		default:
+			selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
+				Key:      requirement.Key,
+				Operator: metav1.LabelSelectorOperator(requirement.Operator),
+				Values:   requirement.Values,
+			})
+		}
+	}
+
+	sl, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, err
+	}
+
+	return sl.Matches(labels.Set(label)), nil
+}
+
+func TestMatcherMatches(t *testing.T) {
+	requirement := func(key string, op v2beta2.LabelSelectorOperator, values ...string) v2beta2.LabelSelectorRequirement {
+		r := v2beta2.LabelSelectorRequirement{Key: key, Operator: op}
+		if op == v2beta2.LabelSelectorOpMatch || op == v2beta2.LabelSelectorOpNotMatch {
+			r.RegexValue = values[0]
+		} else {
+			r.Values = values
+		}
+		return r
+	}
+
+	selectors := []struct {
+		name     string
+		selector *v2beta2.LabelSelector
+	}{
+		{"empty", &v2beta2.LabelSelector{}},
+		{"matchLabels", &v2beta2.LabelSelector{MatchLabels: map[string]string{"cluster": "host"}}},
+		{"In", &v2beta2.LabelSelector{MatchExpressions: []v2beta2.LabelSelectorRequirement{
+			requirement("severity", "In", "warning", "critical"),
+		}}},
+		{"NotIn", &v2beta2.LabelSelector{MatchExpressions: []v2beta2.LabelSelectorRequirement{
+			requirement("severity", "NotIn", "info", "none"),
+		}}},
+		{"Exists", &v2beta2.LabelSelector{MatchExpressions: []v2beta2.LabelSelectorRequirement{
+			requirement("namespace", "Exists"),
+		}}},
+		{"DoesNotExist", &v2beta2.LabelSelector{MatchExpressions: []v2beta2.LabelSelectorRequirement{
+			requirement("namespace", "DoesNotExist"),
+		}}},
+		{"Match", &v2beta2.LabelSelector{MatchExpressions: []v2beta2.LabelSelectorRequirement{
+			requirement("namespace", v2beta2.LabelSelectorOpMatch, "^kube-.*$"),
+		}}},
+		{"unanchored Match", &v2beta2.LabelSelector{MatchExpressions: []v2beta2.LabelSelectorRequirement{
+			requirement("namespace", v2beta2.LabelSelectorOpMatch, "system"),
+		}}},
+		{"Match empty value", &v2beta2.LabelSelector{MatchExpressions: []v2beta2.LabelSelectorRequirement{
+			requirement("namespace", v2beta2.LabelSelectorOpMatch, "^$"),
+		}}},
+		{"NotMatch", &v2beta2.LabelSelector{MatchExpressions: []v2beta2.LabelSelectorRequirement{
+			requirement("pod", v2beta2.LabelSelectorOpNotMatch, "^test-.*"),
+		}}},
+		{"NotMatch empty value", &v2beta2.LabelSelector{MatchExpressions: []v2beta2.LabelSelectorRequirement{
+			requirement("pod", v2beta2.LabelSelectorOpNotMatch, "^$"),
+		}}},
+		{"combined", selector},
+	}
+
+	labelSets := []map[string]string{
+		nil,
+		{},
+		alertLabels,
+		{"cluster": "host"},
+		{"cluster": "member", "severity": "critical"},
+		{"severity": "info", "namespace": "default"},
+		{"namespace": "kube-system", "pod": "test-pod"},
+		{"namespace": "", "pod": ""},
+		{"namespace": "my-kube-system"},
+	}
+
+	for _, s := range selectors {
+		m, err := s.selector.Compile()
+		if err != nil {
+			t.Fatalf("%s: %s", s.name, err)
+		}
+
+		for _, label := range labelSets {
+			expected, err := oldMatches(s.selector, label)
+			if err != nil {
+				t.Fatalf("%s: %s", s.name, err)
+			}
+
+			if ok := m.Matches(label); ok != expected {
+				t.Fatalf("%s, labels %v: expected %v, got %v", s.name, label, expected, ok)
+			}
+			if ok, err := s.selector.Matches(label); err != nil || ok != expected {
+				t.Fatalf("%s, labels %v: expected %v, got (%v, %v)", s.name, label, expected, ok, err)
+			}
+		}
+	}
+}
+
+func TestMatcherInvalid(t *testing.T) {
+	selectors := []*v2beta2.LabelSelector{
+		{MatchExpressions: []v2beta2.LabelSelectorRequirement{{Key: "namespace", Operator: v2beta2.LabelSelectorOpMatch, RegexValue: "("}}},
+		{MatchExpressions: []v2beta2.LabelSelectorRequirement{{Key: "namespace", Operator: "In"}}},
+		{MatchExpressions: []v2beta2.LabelSelectorRequirement{{Key: "namespace", Operator: "Unknown"}}},
+	}
+
+	for _, s := range selectors {
+		if _, err := s.Compile(); err == nil {
+			t.Fatalf("%v: expected an error", s.MatchExpressions)
+		}
+		if ok, err := s.Matches(alertLabels); ok || err == nil {
+			t.Fatalf("%v: expected (false, error), got (%v, %v)", s.MatchExpressions, ok, err)
+		}
+		if Matches(RouterKey("invalid"), "1", s, alertLabels) {
+			t.Fatalf("%v: the invalid selector should match nothing", s.MatchExpressions)
+		}
+	}
+
+	var nilSelector *v2beta2.LabelSelector
+	if _, err := nilSelector.Compile(); err == nil {
+		t.Fatal("expected an error compiling a nil selector")
+	}
+	if !Matches(RouterKey("nil"), "1", nil, alertLabels) {
+		t.Fatal("a nil selector should match all labels")
+	}
+}
+
+func TestCacheResourceVersion(t *testing.T) {
+	c := NewCache()
+	key := SilenceKey("test")
+
+	host := &v2beta2.LabelSelector{MatchLabels: map[string]string{"cluster": "host"}}
+	member := &v2beta2.LabelSelector{MatchLabels: map[string]string{"cluster": "member"}}
+
+	m1, err := c.Get(key, "1", host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m1.Matches(alertLabels) {
+		t.Fatal("the labels should match the selector of version 1")
+	}
+
+	// The matcher is not compiled again if the resourceVersion is not changed.
+	m, err := c.Get(key, "1", member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m != m1 {
+		t.Fatal("expected the cached matcher of version 1")
+	}
+
+	// The matcher is compiled again when the resourceVersion changes.
+	m2, err := c.Get(key, "2", member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m2 == m1 || m2.Matches(alertLabels) {
+		t.Fatal("expected the matcher of version 2")
+	}
+
+	// The compilation error is cached with the resourceVersion as well.
+	invalid := &v2beta2.LabelSelector{MatchExpressions: []v2beta2.LabelSelectorRequirement{
+		{Key: "namespace", Operator: v2beta2.LabelSelectorOpMatch, RegexValue: "("},
+	}}
+	if _, err := c.Get(key, "3", invalid); err == nil {
+		t.Fatal("expected an error of version 3")
+	}
+	if _, err := c.Get(key, "3", host); err == nil {
+		t.Fatal("expected the cached error of version 3")
+	}
+	if m, err := c.Get(key, "4", host); err != nil || !m.Matches(alertLabels) {
+		t.Fatalf("expected the matcher of version 4, got error %v", err)
+	}
+}
+
+func TestCacheSweep(t *testing.T) {
+	c := NewCache()
+	host := &v2beta2.LabelSelector{MatchLabels: map[string]string{"cluster": "host"}}
+
+	if _, err := c.Get(SilenceKey("used"), "1", host); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(SilenceKey("unused"), "1", host); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first sweep only resets the usage.
+	c.mutex.Lock()
+	c.sweep()
+	c.mutex.Unlock()
+	if _, err := c.Get(SilenceKey("used"), "1", host); err != nil {
+		t.Fatal(err)
+	}
+
+	c.mutex.Lock()
+	c.sweep()
+	c.mutex.Unlock()
+	if _, ok := c.entries[SilenceKey("used")]; !ok {
+		t.Fatal("expected the used matcher to be kept")
+	}
+	if _, ok := c.entries[SilenceKey("unused")]; ok {
+		t.Fatal("expected the unused matcher to be removed")
+	}
+}
@@ -0,0 +1,204 @@
+package nflog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+var (
+	retention        *time.Duration
+	snapshotFile     *string
+	snapshotInterval *time.Duration
+)
+
+func init() {
+	retention = kingpin.Flag(
+		"nflog.retention",
+		"How long to keep the notification log entries",
+	).Default("120h").Duration()
+	snapshotFile = kingpin.Flag(
+		"nflog.snapshotFile",
+		"The file the notification log is written to periodically and loaded from at start, so that it survives a restart. The notification log is only kept in memory if not set",
+	).Default("").String()
+	snapshotInterval = kingpin.Flag(
+		"nflog.snapshotInterval",
+		"Interval to write the notification log to the snapshot file",
+	).Default("1m").Duration()
+}
+
+// Entry records the last notification of an alert sent to a receiver.
+type Entry struct {
+	// The status of the alert when it was notified, firing or resolved.
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Log is the notification log, it remembers when and in which status an alert was last notified to a receiver.
+// The entries are kept in memory, and will be removed after the retention time.
+// The entries are written to the snapshot file periodically if it is set.
+type Log struct {
+	mutex     sync.Mutex
+	entries   map[string]*Entry
+	retention time.Duration
+	logger    log.Logger
+
+	snapshotFile     string
+	snapshotInterval time.Duration
+}
+
+func New(logger log.Logger) *Log {
+	l := &Log{
+		entries:          make(map[string]*Entry),
+		retention:        *retention,
+		logger:           logger,
+		snapshotFile:     *snapshotFile,
+		snapshotInterval: *snapshotInterval,
+	}
+
+	if err := l.load(); err != nil {
+		_ = level.Error(logger).Log("msg", "NotificationLog: load snapshot failed", "file", l.snapshotFile, "error", err.Error())
+	}
+
+	go l.gc()
+	go l.snapshot()
+	return l
+}
+
+func key(receiverHash, fingerprint string) string {
+	return fmt.Sprintf("%s/%s", receiverHash, fingerprint)
+}
+
+// Log records that the alert with the fingerprint has been notified to the receiver in the status.
+func (l *Log) Log(receiverHash, fingerprint, status string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.entries[key(receiverHash, fingerprint)] = &Entry{
+		Status:    status,
+		Timestamp: time.Now(),
+	}
+}
+
+// Query returns the last notification of the alert with the fingerprint sent to the receiver.
+func (l *Log) Query(receiverHash, fingerprint string) (Entry, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	e, ok := l.entries[key(receiverHash, fingerprint)]
+	if !ok {
+		return Entry{}, false
+	}
+
+	return *e, true
+}
+
+func (l *Log) gc() {
+	if l.retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(l.retention / 10)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		l.mutex.Lock()
+		for k, e := range l.entries {
+			if time.Since(e.Timestamp) > l.retention {
+				delete(l.entries, k)
+			}
+		}
+		l.mutex.Unlock()
+	}
+}
+
+func (l *Log) snapshot() {
+	if utils.StringIsNil(l.snapshotFile) || l.snapshotInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(l.snapshotInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := l.Snapshot(); err != nil {
+			_ = level.Error(l.logger).Log("msg", "NotificationLog: write snapshot failed", "file", l.snapshotFile, "error", err.Error())
+		}
+	}
+}
+
+// Snapshot writes the notification log to the snapshot file, it does nothing if the snapshot file is not set.
+func (l *Log) Snapshot() error {
+	if utils.StringIsNil(l.snapshotFile) {
+		return nil
+	}
+
+	l.mutex.Lock()
+	bs, err := utils.JsonMarshal(l.entries)
+	l.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it, so that the snapshot will not be corrupted by a crash.
+	tmp, err := os.CreateTemp(filepath.Dir(l.snapshotFile), filepath.Base(l.snapshotFile)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(bs); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), l.snapshotFile)
+}
+
+// load loads the notification log from the snapshot file, the expired entries are dropped.
+func (l *Log) load() error {
+	if utils.StringIsNil(l.snapshotFile) {
+		return nil
+	}
+
+	bs, err := os.ReadFile(l.snapshotFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	entries := make(map[string]*Entry)
+	if err := utils.JsonUnmarshal(bs, &entries); err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for k, e := range entries {
+		if e == nil || (l.retention > 0 && time.Since(e.Timestamp) > l.retention) {
+			continue
+		}
+		l.entries[k] = e
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package nflog
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/constants"
+)
+
+func TestSnapshot(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "nflog")
+
+	l := &Log{entries: make(map[string]*Entry), retention: time.Hour, snapshotFile: file}
+	l.Log("receiver", "new", constants.AlertFiring)
+	l.entries[key("receiver", "expired")] = &Entry{constants.AlertFiring, time.Now().Add(-2 * time.Hour)}
+	if err := l.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := &Log{entries: make(map[string]*Entry), retention: time.Hour, snapshotFile: file}
+	if err := loaded.load(); err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := loaded.Query("receiver", "new"); !ok || e.Status != constants.AlertFiring {
+		t.Fatalf("expected the entry to be loaded, got %v", e)
+	}
+	if _, ok := loaded.Query("receiver", "expired"); ok {
+		t.Fatal("expected the expired entry to be dropped")
+	}
+
+	// Nothing is loaded if the snapshot file does not exist.
+	missing := &Log{entries: make(map[string]*Entry), snapshotFile: filepath.Join(t.TempDir(), "missing")}
+	if err := missing.load(); err != nil || len(missing.entries) != 0 {
+		t.Fatalf("expected an empty log, got %d entries, error %v", len(missing.entries), err)
+	}
+}
@@ -0,0 +1,120 @@
+package nflog
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/constants"
+	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/stage"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/modern-go/reflect2"
+)
+
+type dedupStage struct {
+	notifierCtl *controller.Controller
+	nflog       *Log
+}
+
+// NewStage returns a stage which drops the notifications that have been sent to the receiver within the repeat interval.
+func NewStage(notifierCtl *controller.Controller, nflog *Log) stage.Stage {
+	return &dedupStage{
+		notifierCtl: notifierCtl,
+		nflog:       nflog,
+	}
+}
+
+func (s *dedupStage) Exec(ctx context.Context, l log.Logger, data interface{}) (context.Context, interface{}, error) {
+
+	if reflect2.IsNil(data) {
+		return ctx, nil, nil
+	}
+
+	input := data.(map[internal.Receiver][]*template.Data)
+	repeatInterval := s.notifierCtl.GetRepeatInterval()
+	if repeatInterval <= 0 && !acknowledged(input) {
+		return ctx, data, nil
+	}
+
+	_ = level.Debug(l).Log("msg", "Start dedup stage", "seq", ctx.Value("seq"), "repeat interval", repeatInterval)
+
+	output := make(map[internal.Receiver][]*template.Data)
+	for receiver, ds := range input {
+		var res []*template.Data
+		for _, d := range ds {
+			if nd := s.dedup(receiver, d, repeatInterval); nd != nil {
+				res = append(res, nd)
+			}
+		}
+
+		if len(res) > 0 {
+			output[receiver] = res
+		}
+	}
+
+	if len(output) == 0 {
+		return ctx, nil, nil
+	}
+
+	return ctx, output, nil
+}
+
+// acknowledged returns true if any alert has been acknowledged.
+func acknowledged(input map[internal.Receiver][]*template.Data) bool {
+	for _, ds := range input {
+		for _, d := range ds {
+			for _, alert := range d.Alerts {
+				if alert.Acknowledgement != nil {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// dedup returns the notification need to be sent to the receiver, nil will be returned if no need to send.
+// A notification need to be sent if it contains a new firing alert, an alert which was resolved
+// and fired again, an alert which was notified before the repeat interval, or a resolved alert.
+// The resolved alerts whose firing notification were not sent will be dropped if the repeat interval is set.
+// The acknowledged firing alerts which have been notified will not be notified again.
+func (s *dedupStage) dedup(receiver internal.Receiver, d *template.Data, repeatInterval time.Duration) *template.Data {
+
+	var alerts template.Alerts
+	needToNotify := false
+	for _, alert := range d.Alerts {
+		e, ok := s.nflog.Query(receiver.GetHash(), alert.Fingerprint())
+		if alert.Status == constants.AlertResolved {
+			if repeatInterval > 0 && (!ok || e.Status != constants.AlertFiring) {
+				continue
+			}
+			needToNotify = true
+		} else if !ok || e.Status != constants.AlertFiring {
+			needToNotify = true
+		} else if alert.Acknowledgement != nil {
+			continue
+		} else if time.Since(e.Timestamp) >= repeatInterval {
+			needToNotify = true
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	if !needToNotify {
+		return nil
+	}
+
+	if len(alerts) == len(d.Alerts) {
+		return d
+	}
+
+	nd := &template.Data{
+		GroupLabels: d.GroupLabels,
+		Alerts:      alerts,
+	}
+	return nd.Format()
+}
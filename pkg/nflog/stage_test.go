@@ -0,0 +1,66 @@
+package nflog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/constants"
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/internal/webhook"
+	"github.com/kubesphere/notification-manager/pkg/template"
+)
+
+func newData(status string) *template.Data {
+	return &template.Data{
+		Alerts: template.Alerts{
+			{
+				Status: status,
+				Labels: template.KV{"alertname": "test"},
+			},
+		},
+	}
+}
+
+func TestDedup(t *testing.T) {
+	receiver := &webhook.Receiver{Common: &internal.Common{Hash: "receiver"}}
+	fingerprint := newData(constants.AlertFiring).Alerts[0].Fingerprint()
+
+	tests := []struct {
+		name           string
+		entry          *Entry
+		status         string
+		acknowledged   bool
+		repeatInterval time.Duration
+		notify         bool
+	}{
+		{"new firing alert", nil, constants.AlertFiring, false, time.Hour, true},
+		{"firing alert within the repeat interval", &Entry{constants.AlertFiring, time.Now()}, constants.AlertFiring, false, time.Hour, false},
+		{"firing alert after the repeat interval", &Entry{constants.AlertFiring, time.Now().Add(-2 * time.Hour)}, constants.AlertFiring, false, time.Hour, true},
+		{"alert fired again after resolved", &Entry{constants.AlertResolved, time.Now()}, constants.AlertFiring, false, time.Hour, true},
+		{"acknowledged alert notified before", &Entry{constants.AlertFiring, time.Now().Add(-2 * time.Hour)}, constants.AlertFiring, true, time.Hour, false},
+		{"resolved alert notified firing", &Entry{constants.AlertFiring, time.Now()}, constants.AlertResolved, false, time.Hour, true},
+		{"resolved alert notified resolved", &Entry{constants.AlertResolved, time.Now()}, constants.AlertResolved, false, time.Hour, false},
+		// The firing alert may be silenced, inhibited or failed to be sent.
+		{"resolved alert without log entry", nil, constants.AlertResolved, false, time.Hour, false},
+		{"resolved alert without repeat interval", nil, constants.AlertResolved, false, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := &Log{entries: make(map[string]*Entry)}
+			if test.entry != nil {
+				l.entries[key(receiver.GetHash(), fingerprint)] = test.entry
+			}
+
+			d := newData(test.status)
+			if test.acknowledged {
+				d.Alerts[0].Acknowledgement = &template.Acknowledgement{}
+			}
+
+			s := &dedupStage{nflog: l}
+			if got := s.dedup(receiver, d, test.repeatInterval) != nil; got != test.notify {
+				t.Fatalf("expected notify %v, got %v", test.notify, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+)
+
+const attemptKey = "attempt"
+
+// Attempt records the targets to which a notification has been sent successfully. A notifier which sends
+// the notification to several targets, such as channels, recipients or chatbots, will skip these targets
+// when the notification is retried, so that only the failed targets receive the notification again.
+type Attempt struct {
+	mutex sync.Mutex
+	sent  map[string]bool
+}
+
+func NewAttempt() *Attempt {
+	return &Attempt{
+		sent: make(map[string]bool),
+	}
+}
+
+// WithAttempt returns a copy of the context which carries the attempt.
+func WithAttempt(ctx context.Context, a *Attempt) context.Context {
+	return context.WithValue(ctx, attemptKey, a)
+}
+
+// Sent returns the number of the targets to which the notification has been sent successfully.
+func (a *Attempt) Sent() int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return len(a.sent)
+}
+
+func (a *Attempt) isSent(target string) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.sent[target]
+}
+
+func (a *Attempt) markSent(target string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.sent[target] = true
+}
+
+// Send sends the notification to the target with the send function, the target must be unique in the notifier.
+// The target will be skipped if the notification has been sent to it in a previous attempt carried by the context.
+func Send(ctx context.Context, target string, send func() error) error {
+	a, ok := ctx.Value(attemptKey).(*Attempt)
+	if !ok || a == nil {
+		return send()
+	}
+
+	if a.isSent(target) {
+		return nil
+	}
+
+	if err := send(); err != nil {
+		return err
+	}
+
+	a.markSent(target)
+	return nil
+}
@@ -0,0 +1,62 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSendSkipsSentTargets(t *testing.T) {
+	attempt := NewAttempt()
+	ctx := WithAttempt(context.Background(), attempt)
+
+	sent := make(map[string]int)
+	notify := func(failed map[string]bool) error {
+		var err error
+		for _, target := range []string{"a", "b", "c"} {
+			target := target
+			if e := Send(ctx, target, func() error {
+				sent[target]++
+				if failed[target] {
+					return errors.New("failed")
+				}
+				return nil
+			}); e != nil {
+				err = e
+			}
+		}
+		return err
+	}
+
+	if err := notify(map[string]bool{"b": true}); err == nil {
+		t.Fatal("expected the first attempt to fail")
+	}
+	if err := notify(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the failed target is retried.
+	expected := map[string]int{"a": 1, "b": 2, "c": 1}
+	for target, n := range expected {
+		if sent[target] != n {
+			t.Fatalf("expected %s to be sent %d times, got %d", target, n, sent[target])
+		}
+	}
+	if attempt.Sent() != 3 {
+		t.Fatalf("expected 3 targets sent, got %d", attempt.Sent())
+	}
+}
+
+func TestSendWithoutAttempt(t *testing.T) {
+	n := 0
+	for i := 0; i < 2; i++ {
+		_ = Send(context.Background(), "a", func() error {
+			n++
+			return nil
+		})
+	}
+
+	if n != 2 {
+		t.Fatalf("expected the target to be sent every time without attempt, got %d", n)
+	}
+}
@@ -378,14 +378,17 @@ func (n *Notifier) sendToChatBot(ctx context.Context, data *template.Data) error
 		if n.receiver.TmplType == constants.Markdown {
 			msg = fmt.Sprintf("%s %s", msg, atMobiles)
 		}
+		target := fmt.Sprintf("chatbot/%d", index)
 		group.Add(func(stopCh chan interface{}) {
-			n.throttle.TryAdd(webhook, n.chatbotThreshold, n.chatbotUnit, n.chatbotMaxWaitTime)
-			if !n.throttle.Allow(webhook, n.logger) {
-				_ = level.Error(n.logger).Log("msg", "DingTalkNotifier: message to chatbot dropped because of flow control")
-				stopCh <- utils.Error("")
-			}
+			err := notifier.Send(ctx, target, func() error {
+				n.throttle.TryAdd(webhook, n.chatbotThreshold, n.chatbotUnit, n.chatbotMaxWaitTime)
+				if !n.throttle.Allow(webhook, n.logger) {
+					_ = level.Error(n.logger).Log("msg", "DingTalkNotifier: message to chatbot dropped because of flow control")
+					return utils.Error("")
+				}
 
-			err := send(title, msg)
+				return send(title, msg)
+			})
 			if err == nil {
 				if n.sentSuccessfulHandler != nil {
 					(*n.sentSuccessfulHandler)(alerts)
@@ -514,14 +517,17 @@ func (n *Notifier) sendToConversation(ctx context.Context, data *template.Data)
 		msg := d.Message
 		for _, chatID := range n.receiver.ChatIDs {
 			id := chatID
+			target := fmt.Sprintf("conversation/%s/%d", id, index)
 			group.Add(func(stopCh chan interface{}) {
-				n.throttle.TryAdd(appkey, n.conversationThreshold, n.conversationUnit, n.conversationMaxWaitTime)
-				if !n.throttle.Allow(appkey, n.logger) {
-					_ = level.Error(n.logger).Log("msg", "DingTalkNotifier: message to conversation dropped because of flow control", "conversation", chatID)
-					stopCh <- utils.Error("")
-				}
+				err := notifier.Send(ctx, target, func() error {
+					n.throttle.TryAdd(appkey, n.conversationThreshold, n.conversationUnit, n.conversationMaxWaitTime)
+					if !n.throttle.Allow(appkey, n.logger) {
+						_ = level.Error(n.logger).Log("msg", "DingTalkNotifier: message to conversation dropped because of flow control", "conversation", id)
+						return utils.Error("")
+					}
 
-				err := send(id, title, msg)
+					return send(id, title, msg)
+				})
 				if err == nil {
 					if n.sentSuccessfulHandler != nil {
 						(*n.sentSuccessfulHandler)(alerts)
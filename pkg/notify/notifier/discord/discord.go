@@ -131,9 +131,12 @@ func (n *Notifier) Notify(ctx context.Context, data *template.Data) error {
 			d := splitData[index]
 			alerts := d.Alerts
 			msg := d.Message
+			target := fmt.Sprintf("%d", index)
 			group.Add(func(stopCh chan interface{}) {
 				msg := fmt.Sprintf("%s\n%s%s", msg, atUsers, atRoles)
-				err := n.sendTo(ctx, msg)
+				err := notifier.Send(ctx, target, func() error {
+					return n.sendTo(ctx, msg)
+				})
 				if err == nil {
 					if n.sentSuccessfulHandler != nil {
 						(*n.sentSuccessfulHandler)(alerts)
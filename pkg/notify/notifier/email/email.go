@@ -173,7 +173,9 @@ func (n *Notifier) Notify(ctx context.Context, data *template.Data) error {
 	for _, t := range n.receiver.To {
 		to := t
 		group.Add(func(stopCh chan interface{}) {
-			err := sendEmail(to, subject, body)
+			err := notifier.Send(ctx, to, func() error {
+				return sendEmail(to, subject, body)
+			})
 			if err == nil {
 				if n.sentSuccessfulHandler != nil {
 					(*n.sentSuccessfulHandler)(data.Alerts)
@@ -151,7 +151,9 @@ func (n *Notifier) Notify(ctx context.Context, data *template.Data) error {
 	group := async.NewGroup(ctx)
 	if n.receiver.ChatBot != nil {
 		group.Add(func(stopCh chan interface{}) {
-			err := n.sendToChatBot(ctx, content)
+			err := notifier.Send(ctx, "chatbot", func() error {
+				return n.sendToChatBot(ctx, content)
+			})
 			if err == nil {
 				if n.sentSuccessfulHandler != nil {
 					(*n.sentSuccessfulHandler)(data.Alerts)
@@ -163,7 +165,9 @@ func (n *Notifier) Notify(ctx context.Context, data *template.Data) error {
 
 	if len(n.receiver.User) > 0 || len(n.receiver.Department) > 0 {
 		group.Add(func(stopCh chan interface{}) {
-			err := n.batchSend(ctx, content)
+			err := notifier.Send(ctx, "batch", func() error {
+				return n.batchSend(ctx, content)
+			})
 			if err == nil {
 				if n.sentSuccessfulHandler != nil {
 					(*n.sentSuccessfulHandler)(data.Alerts)
@@ -3,6 +3,7 @@ package pushover
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -243,10 +244,13 @@ func (n *Notifier) Notify(ctx context.Context, data *template.Data) error {
 		d := splitData[index]
 		alerts := d.Alerts
 		message := d.Message
-		for _, p := range n.receiver.Profiles {
+		for i, p := range n.receiver.Profiles {
 			profile := p
+			target := fmt.Sprintf("%d/%d", i, index)
 			group.Add(func(stopCh chan interface{}) {
-				err := send(profile, title, message)
+				err := notifier.Send(ctx, target, func() error {
+					return send(profile, title, message)
+				})
 				if err == nil {
 					if n.sentSuccessfulHandler != nil {
 						(*n.sentSuccessfulHandler)(alerts)
@@ -165,7 +165,9 @@ func (n *Notifier) Notify(ctx context.Context, data *template.Data) error {
 	for _, channel := range n.receiver.Channels {
 		ch := channel
 		group.Add(func(stopCh chan interface{}) {
-			err := send(ch)
+			err := notifier.Send(ctx, ch, func() error {
+				return send(ch)
+			})
 			if err == nil {
 				if n.sentSuccessfulHandler != nil {
 					(*n.sentSuccessfulHandler)(data.Alerts)
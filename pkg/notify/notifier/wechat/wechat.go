@@ -299,7 +299,7 @@ func (n *Notifier) Notify(ctx context.Context, data *template.Data) error {
 			stopCh <- n.sendToChatBot(ctx, data)
 		})
 	}
-	for {
+	for b := 0; ; b++ {
 		if us >= len(toUser) && ps >= len(toParty) && ts >= len(toTag) {
 			break
 		}
@@ -313,8 +313,11 @@ func (n *Notifier) Notify(ctx context.Context, data *template.Data) error {
 			d := splitData[index]
 			alerts := d.Alerts
 			msg := d.Message
+			target := fmt.Sprintf("batch/%d/%d", b, index)
 			group.Add(func(stopCh chan interface{}) {
-				err := send(r, msg)
+				err := notifier.Send(ctx, target, func() error {
+					return send(r, msg)
+				})
 				if err == nil {
 					if n.sentSuccessfulHandler != nil {
 						(*n.sentSuccessfulHandler)(alerts)
@@ -435,8 +438,11 @@ func (n *Notifier) sendToChatBot(ctx context.Context, data *template.Data) error
 		if n.receiver.TmplType == constants.Markdown {
 			msg = fmt.Sprintf("%s\n%s", msg, atUsers)
 		}
+		target := fmt.Sprintf("chatbot/%d", index)
 		group.Add(func(stopCh chan interface{}) {
-			err := send(msg)
+			err := notifier.Send(ctx, target, func() error {
+				return send(msg)
+			})
 			if err == nil {
 				if n.sentSuccessfulHandler != nil {
 					(*n.sentSuccessfulHandler)(alerts)
@@ -10,7 +10,9 @@ import (
 	"github.com/kubesphere/notification-manager/pkg/async"
 	"github.com/kubesphere/notification-manager/pkg/constants"
 	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/deadletter"
 	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/nflog"
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier/dingtalk"
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier/discord"
@@ -23,6 +25,7 @@ import (
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier/webhook"
 	"github.com/kubesphere/notification-manager/pkg/notify/notifier/wechat"
 	"github.com/kubesphere/notification-manager/pkg/stage"
+	"github.com/kubesphere/notification-manager/pkg/status"
 	"github.com/kubesphere/notification-manager/pkg/template"
 	"github.com/modern-go/reflect2"
 )
@@ -31,6 +34,8 @@ type Factory func(logger log.Logger, receiver internal.Receiver, notifierCtl *co
 
 var (
 	factories map[string]Factory
+
+	noopHandler = func([]*template.Alert) {}
 )
 
 func init() {
@@ -56,6 +61,9 @@ func Register(name string, factory Factory) {
 
 type notifyStage struct {
 	notifierCtl *controller.Controller
+	deadLetters *deadletter.Queue
+	nflog       *nflog.Log
+	recorder    *status.Recorder
 }
 
 func NewStage(notifierCtl *controller.Controller) stage.Stage {
@@ -65,6 +73,20 @@ func NewStage(notifierCtl *controller.Controller) stage.Stage {
 	}
 }
 
+// NewRetryStage returns a notify stage which retries the failed notifications according to the retry policy,
+// and puts the notifications which still fail after all retries into the dead-letter queue.
+// The notifications sent successfully will be recorded in the notification log,
+// and the results of the notifications will be recorded in the status of the receivers.
+func NewRetryStage(notifierCtl *controller.Controller, deadLetters *deadletter.Queue, nl *nflog.Log, recorder *status.Recorder) stage.Stage {
+
+	return &notifyStage{
+		notifierCtl: notifierCtl,
+		deadLetters: deadLetters,
+		nflog:       nl,
+		recorder:    recorder,
+	}
+}
+
 func (s *notifyStage) Exec(ctx context.Context, l log.Logger, data interface{}) (context.Context, interface{}, error) {
 
 	if reflect2.IsNil(data) {
@@ -111,13 +133,15 @@ func (s *notifyStage) Exec(ctx context.Context, l log.Logger, data interface{})
 			})
 			continue
 		}
-		nf.SetSentSuccessfulHandler(&handler)
+		nf.SetSentSuccessfulHandler(s.logHandler(receiver, ds, &handler))
+		// The output of this stage has been returned when the retries finish, the retries only record the notification log.
+		retryHandler := s.logHandler(receiver, ds, &noopHandler)
 
 		for _, d := range ds {
 			alert := d.Clone()
 			s.addExtensionLabels(receiver, alert)
 			group.Add(func(stopCh chan interface{}) {
-				stopCh <- nf.Notify(ctx, alert)
+				stopCh <- s.notify(ctx, l, nf, receiver, alert, retryHandler)
 			})
 		}
 	}
@@ -125,6 +149,31 @@ func (s *notifyStage) Exec(ctx context.Context, l log.Logger, data interface{})
 	return ctx, alertMap, group.Wait()
 }
 
+// logHandler wraps the sent successful handler to record the notifications sent to the receiver in the notification log.
+func (s *notifyStage) logHandler(receiver internal.Receiver, ds []*template.Data, handler *func([]*template.Alert)) *func([]*template.Alert) {
+	if s.nflog == nil {
+		return handler
+	}
+
+	// The labels of the alerts sent to the notifier may be changed, so the fingerprints are calculated in advance.
+	fingerprints := make(map[string]string)
+	for _, d := range ds {
+		for _, alert := range d.Alerts {
+			fingerprints[alert.ID] = alert.Fingerprint()
+		}
+	}
+
+	h := func(alerts []*template.Alert) {
+		(*handler)(alerts)
+		for _, alert := range alerts {
+			if fingerprint, ok := fingerprints[alert.ID]; ok {
+				s.nflog.Log(receiver.GetHash(), fingerprint, alert.Status)
+			}
+		}
+	}
+	return &h
+}
+
 func setReceiver(alertData []*template.Data, receiver internal.Receiver, alertMap map[string]*template.Alert) {
 	if receiver.GetName() == "" {
 		return
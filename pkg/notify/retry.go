@@ -0,0 +1,189 @@
+package notify
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/store"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultMaxAttempts    = 1
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = time.Minute
+	// The timeout of every retry if the worker has no timeout.
+	defaultRetryTimeout = 30 * time.Second
+)
+
+var retryTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "notification_manager",
+	Subsystem: "notification",
+	Name:      "retries_total",
+	Help:      "The total number of retries of the failed notifications, partitioned by receiver type.",
+}, []string{"type"})
+
+func init() {
+	prometheus.MustRegister(retryTotal)
+}
+
+type retryPolicy struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	jitter         int
+}
+
+func newRetryPolicy(p *v2beta2.RetryPolicy) *retryPolicy {
+	rp := &retryPolicy{
+		maxAttempts:    defaultMaxAttempts,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+	}
+
+	if p == nil {
+		return rp
+	}
+
+	if p.MaxAttempts > 0 {
+		rp.maxAttempts = p.MaxAttempts
+	}
+	if p.InitialBackoff != nil && p.InitialBackoff.Duration > 0 {
+		rp.initialBackoff = p.InitialBackoff.Duration
+	}
+	if p.MaxBackoff != nil && p.MaxBackoff.Duration > 0 {
+		rp.maxBackoff = p.MaxBackoff.Duration
+	}
+	if rp.maxBackoff < rp.initialBackoff {
+		rp.maxBackoff = rp.initialBackoff
+	}
+	if p.Jitter > 0 && p.Jitter <= 100 {
+		rp.jitter = p.Jitter
+	}
+
+	return rp
+}
+
+// backoff returns the time to wait before the next attempt, the attempt starts from 1.
+func (rp *retryPolicy) backoff(attempt int) time.Duration {
+	d := rp.initialBackoff
+	for i := 1; i < attempt && d < rp.maxBackoff; i++ {
+		d = d * 2
+	}
+	if d > rp.maxBackoff {
+		d = rp.maxBackoff
+	}
+
+	if rp.jitter > 0 {
+		delta := int64(d) * int64(rp.jitter) / 100
+		if delta > 0 {
+			d = d - time.Duration(delta) + time.Duration(rand.Int63n(2*delta+1))
+		}
+	}
+
+	return d
+}
+
+// notify sends the notification, and retries according to the retry policy of the receiver type if failed.
+// Only the targets which failed in the previous attempts are retried, so that the targets which have received the notification
+// will not receive it again. The retries run in the background, so that they neither hold the worker nor are limited by
+// the worker timeout, every retry has the same timeout as the worker. The batch of the alerts is held until the retries finish.
+// The notification which still fails after all attempts will be put into the dead-letter queue.
+func (s *notifyStage) notify(ctx context.Context, l log.Logger, nf notifier.Notifier, receiver internal.Receiver, data *template.Data, handler *func([]*template.Alert)) error {
+
+	if s.deadLetters == nil {
+		return nf.Notify(ctx, data)
+	}
+
+	attempt := notifier.NewAttempt()
+	err := nf.Notify(notifier.WithAttempt(ctx, attempt), data)
+	if err == nil {
+		s.recorder.ReceiverNotified(receiver.GetName(), nil)
+		return nil
+	}
+
+	rp := newRetryPolicy(s.notifierCtl.GetRetryPolicy(receiver.GetType()))
+	if rp.maxAttempts <= 1 {
+		s.deadLetter(ctx, l, receiver, data, attempt, 1, err)
+		return err
+	}
+
+	var timeout time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	if timeout <= 0 {
+		timeout = defaultRetryTimeout
+	}
+
+	release := store.Hold(ctx)
+	go func() {
+		defer release()
+		s.retry(context.WithValue(context.Background(), "seq", ctx.Value("seq")), l, rp, timeout, receiver, data, handler, attempt, err)
+	}()
+
+	return nil
+}
+
+func (s *notifyStage) retry(ctx context.Context, l log.Logger, rp *retryPolicy, timeout time.Duration, receiver internal.Receiver,
+	data *template.Data, handler *func([]*template.Alert), attempt *notifier.Attempt, err error) {
+
+	// The notifier of the worker can not be used after the worker finished, create a new one.
+	nf, e := factories[receiver.GetType()](l, receiver, s.notifierCtl)
+	if e != nil {
+		s.deadLetter(ctx, l, receiver, data, attempt, 1, err)
+		return
+	}
+	nf.SetSentSuccessfulHandler(handler)
+
+	attempts := 1
+	for attempts < rp.maxAttempts {
+		backoff := rp.backoff(attempts)
+		_ = level.Warn(l).Log("msg", "NotifyStage: notify failed, retry later", "receiver", receiver.GetName(),
+			"type", receiver.GetType(), "attempts", attempts, "sent", attempt.Sent(), "backoff", backoff, "error", err.Error(), "seq", ctx.Value("seq"))
+
+		wait(ctx, backoff)
+		retryTotal.WithLabelValues(receiver.GetType()).Inc()
+		attempts++
+
+		c, cancel := context.WithTimeout(ctx, timeout)
+		err = nf.Notify(notifier.WithAttempt(c, attempt), data)
+		cancel()
+		if err == nil {
+			s.recorder.ReceiverNotified(receiver.GetName(), nil)
+			return
+		}
+	}
+
+	s.deadLetter(ctx, l, receiver, data, attempt, attempts, err)
+}
+
+// deadLetter puts the notification into the dead-letter queue, the targets which have received the notification
+// will be skipped when the notification is replayed.
+func (s *notifyStage) deadLetter(ctx context.Context, l log.Logger, receiver internal.Receiver, data *template.Data, attempt *notifier.Attempt, attempts int, err error) {
+
+	s.recorder.ReceiverNotified(receiver.GetName(), err)
+	e := s.deadLetters.Add(receiver, data, attempt, attempts, err)
+	_ = level.Error(l).Log("msg", "NotifyStage: notify failed, put into dead-letter queue", "id", e.ID,
+		"receiver", receiver.GetName(), "type", receiver.GetType(), "attempts", attempts, "seq", ctx.Value("seq"))
+}
+
+// wait waits for the duration, it returns false if the context is done before that.
+func wait(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	rp := newRetryPolicy(&v2beta2.RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: &metav1.Duration{Duration: time.Second},
+		MaxBackoff:     &metav1.Duration{Duration: 5 * time.Second},
+	})
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, d := range expected {
+		if got := rp.backoff(i + 1); got != d {
+			t.Fatalf("attempt %d: expected backoff %s, got %s", i+1, d, got)
+		}
+	}
+}
+
+func TestRetryPolicyDefaults(t *testing.T) {
+	rp := newRetryPolicy(&v2beta2.RetryPolicy{
+		InitialBackoff: &metav1.Duration{Duration: 2 * time.Minute},
+		Jitter:         200,
+	})
+
+	if rp.maxAttempts != defaultMaxAttempts {
+		t.Fatalf("expected default max attempts, got %d", rp.maxAttempts)
+	}
+	// The max backoff is never less than the initial backoff.
+	if rp.maxBackoff != 2*time.Minute {
+		t.Fatalf("expected max backoff to be raised to the initial backoff, got %s", rp.maxBackoff)
+	}
+	// An invalid jitter is ignored.
+	if rp.jitter != 0 {
+		t.Fatalf("expected the invalid jitter to be ignored, got %d", rp.jitter)
+	}
+}
+
+func TestRetryPolicyJitter(t *testing.T) {
+	rp := newRetryPolicy(&v2beta2.RetryPolicy{
+		InitialBackoff: &metav1.Duration{Duration: 10 * time.Second},
+		Jitter:         20,
+	})
+
+	for i := 0; i < 100; i++ {
+		if d := rp.backoff(1); d < 8*time.Second || d > 12*time.Second {
+			t.Fatalf("backoff %s out of the jitter range", d)
+		}
+	}
+}
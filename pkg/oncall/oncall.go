@@ -0,0 +1,218 @@
+package oncall
+
+import (
+	"reflect"
+	"sort"
+	"time"
+	// Embed the time zone database, so that the time zones of the schedules can be loaded in any image.
+	_ "time/tzdata"
+
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+)
+
+const (
+	// The maximum number of handoffs calculated for a layer in a time range.
+	maxHandoffs = 10000
+)
+
+// Shift is a time range in which the receivers selected by the receiver selector are on call.
+type Shift struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// The layer which the shift belongs to, it is empty if the shift is an override.
+	Layer     string                    `json:"layer,omitempty"`
+	Override  bool                      `json:"override,omitempty"`
+	Receivers *v2beta2.ReceiverSelector `json:"receivers"`
+}
+
+func location(schedule *v2beta2.OnCallSchedule) (*time.Location, error) {
+	if utils.StringIsNil(schedule.Spec.TimeZone) {
+		return time.UTC, nil
+	}
+
+	return time.LoadLocation(schedule.Spec.TimeZone)
+}
+
+func rotation(layer *v2beta2.OnCallLayer) (string, int) {
+	t := layer.Rotation.Type
+	if utils.StringIsNil(t) {
+		t = v2beta2.RotationWeekly
+	}
+
+	n := layer.Rotation.ShiftLength
+	if n <= 0 {
+		n = 1
+	}
+
+	return t, n
+}
+
+// handoff returns the time of the k-th handoff of the layer.
+// The daily and weekly handoffs keep the same local time in the location.
+func handoff(layer *v2beta2.OnCallLayer, loc *time.Location, k int) time.Time {
+	start := layer.Start.Time.In(loc)
+	t, n := rotation(layer)
+	switch t {
+	case v2beta2.RotationHourly:
+		return start.Add(time.Duration(k*n) * time.Hour)
+	case v2beta2.RotationDaily:
+		return start.AddDate(0, 0, k*n)
+	default:
+		return start.AddDate(0, 0, 7*k*n)
+	}
+}
+
+// shiftIndex returns the index of the shift of the layer at the time, false will be returned if the layer is not active.
+func shiftIndex(layer *v2beta2.OnCallLayer, loc *time.Location, at time.Time) (int, bool) {
+
+	if at.Before(layer.Start.Time) || (layer.End != nil && !at.Before(layer.End.Time)) {
+		return 0, false
+	}
+
+	t, n := rotation(layer)
+	length := time.Duration(n) * time.Hour
+	switch t {
+	case v2beta2.RotationDaily:
+		length = length * 24
+	case v2beta2.RotationWeekly:
+		length = length * 24 * 7
+	}
+
+	// Estimate the index, then correct it, the length of a day may not be 24 hours in the location.
+	k := int(at.Sub(layer.Start.Time) / length)
+	for !handoff(layer, loc, k+1).After(at) {
+		k++
+	}
+	for k > 0 && handoff(layer, loc, k).After(at) {
+		k--
+	}
+
+	return k, true
+}
+
+// onCallAt returns the shift which is on call at the time, the start and end of the shift are not set.
+// Nil will be returned if nobody is on call.
+func onCallAt(schedule *v2beta2.OnCallSchedule, loc *time.Location, at time.Time) *Shift {
+
+	for i := range schedule.Spec.Overrides {
+		override := schedule.Spec.Overrides[i]
+		if !at.Before(override.Start.Time) && at.Before(override.End.Time) {
+			return &Shift{
+				Override:  true,
+				Receivers: &override.Receivers,
+			}
+		}
+	}
+
+	// The layer listed later takes precedence.
+	for i := len(schedule.Spec.Layers) - 1; i >= 0; i-- {
+		layer := &schedule.Spec.Layers[i]
+		if len(layer.Participants) == 0 {
+			continue
+		}
+
+		if k, ok := shiftIndex(layer, loc, at); ok {
+			return &Shift{
+				Layer:     layer.Name,
+				Receivers: &layer.Participants[k%len(layer.Participants)],
+			}
+		}
+	}
+
+	return nil
+}
+
+// OnCall returns the receiver selector of the participant who is on call at the time.
+// Nil will be returned if nobody is on call.
+func OnCall(schedule *v2beta2.OnCallSchedule, at time.Time) (*v2beta2.ReceiverSelector, error) {
+
+	loc, err := location(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	shift := onCallAt(schedule, loc, at)
+	if shift == nil {
+		return nil, nil
+	}
+
+	return shift.Receivers, nil
+}
+
+// Shifts returns the shifts in the time range [start, end), the shifts are truncated by the time range.
+// The time ranges in which nobody is on call are not included.
+func Shifts(schedule *v2beta2.OnCallSchedule, start, end time.Time) ([]Shift, error) {
+
+	loc, err := location(schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	if !end.After(start) {
+		return nil, nil
+	}
+
+	boundaries := []time.Time{start, end}
+	add := func(t time.Time) {
+		if t.After(start) && t.Before(end) {
+			boundaries = append(boundaries, t)
+		}
+	}
+
+	for _, override := range schedule.Spec.Overrides {
+		add(override.Start.Time)
+		add(override.End.Time)
+	}
+
+	for i := range schedule.Spec.Layers {
+		layer := &schedule.Spec.Layers[i]
+		if layer.End != nil {
+			add(layer.End.Time)
+		}
+
+		k := 0
+		if index, ok := shiftIndex(layer, loc, start); ok {
+			k = index + 1
+		}
+		for n := 0; n < maxHandoffs; n++ {
+			t := handoff(layer, loc, k+n)
+			if !t.Before(end) || (layer.End != nil && !t.Before(layer.End.Time)) {
+				break
+			}
+			add(t)
+		}
+	}
+
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i].Before(boundaries[j])
+	})
+
+	var shifts []Shift
+	for i := 0; i < len(boundaries)-1; i++ {
+		if !boundaries[i].Before(boundaries[i+1]) {
+			continue
+		}
+
+		shift := onCallAt(schedule, loc, boundaries[i])
+		if shift == nil {
+			continue
+		}
+
+		// Merge the adjacent shifts of the same participant.
+		if n := len(shifts); n > 0 {
+			last := &shifts[n-1]
+			if last.End.Equal(boundaries[i]) && last.Layer == shift.Layer && last.Override == shift.Override &&
+				reflect.DeepEqual(last.Receivers, shift.Receivers) {
+				last.End = boundaries[i+1].In(loc)
+				continue
+			}
+		}
+
+		shift.Start = boundaries[i].In(loc)
+		shift.End = boundaries[i+1].In(loc)
+		shifts = append(shifts, *shift)
+	}
+
+	return shifts, nil
+}
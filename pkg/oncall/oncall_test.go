@@ -0,0 +1,215 @@
+package oncall
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func participants(names ...string) []v2beta2.ReceiverSelector {
+	var res []v2beta2.ReceiverSelector
+	for _, name := range names {
+		res = append(res, v2beta2.ReceiverSelector{Name: []string{name}})
+	}
+	return res
+}
+
+func newSchedule(tz string, layers ...v2beta2.OnCallLayer) *v2beta2.OnCallSchedule {
+	return &v2beta2.OnCallSchedule{
+		Spec: v2beta2.OnCallScheduleSpec{
+			TimeZone: tz,
+			Layers:   layers,
+		},
+	}
+}
+
+func newLayer(name string, start time.Time, rotation string, length int, names ...string) v2beta2.OnCallLayer {
+	return v2beta2.OnCallLayer{
+		Name:         name,
+		Start:        metav1.NewTime(start),
+		Rotation:     v2beta2.OnCallRotation{Type: rotation, ShiftLength: length},
+		Participants: participants(names...),
+	}
+}
+
+func date(loc *time.Location, year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, loc)
+}
+
+func onCall(t *testing.T, schedule *v2beta2.OnCallSchedule, at time.Time) string {
+	t.Helper()
+
+	rs, err := OnCall(schedule, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs == nil {
+		return ""
+	}
+	return rs.Name[0]
+}
+
+func TestOnCall(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	weekly := newSchedule("", newLayer("weekly", date(time.UTC, 2023, 1, 2, 9, 0), "", 0, "a", "b", "c"))
+	end := metav1.NewTime(date(time.UTC, 2023, 1, 30, 9, 0))
+	weekly.Spec.Layers[0].End = &end
+
+	// The daily handoff is at 09:00 local time, DST starts at 2023-03-12 02:00 and ends at 2023-11-05 02:00 in New York.
+	daily := newSchedule("America/New_York", newLayer("daily", date(newYork, 2023, 3, 10, 9, 0), v2beta2.RotationDaily, 1, "a", "b", "c"))
+	fallBack := newSchedule("America/New_York", newLayer("daily", date(newYork, 2023, 11, 3, 9, 0), v2beta2.RotationDaily, 1, "a", "b", "c"))
+	// The hourly handoff does not keep the local time, a shift is always an hour.
+	hourly := newSchedule("America/New_York", newLayer("hourly", date(newYork, 2023, 3, 12, 0, 0), v2beta2.RotationHourly, 1, "a", "b", "c", "d"))
+
+	tests := []struct {
+		name     string
+		schedule *v2beta2.OnCallSchedule
+		at       time.Time
+		expected string
+	}{
+		{"before the layer starts", weekly, date(time.UTC, 2023, 1, 2, 8, 59), ""},
+		{"first shift", weekly, date(time.UTC, 2023, 1, 2, 9, 0), "a"},
+		{"end of first shift", weekly, date(time.UTC, 2023, 1, 9, 8, 59), "a"},
+		{"second shift", weekly, date(time.UTC, 2023, 1, 9, 9, 0), "b"},
+		{"rotation wraps", weekly, date(time.UTC, 2023, 1, 23, 9, 0), "a"},
+		{"after the layer ends", weekly, date(time.UTC, 2023, 1, 30, 9, 0), ""},
+		{"before spring forward", daily, date(newYork, 2023, 3, 11, 9, 0), "b"},
+		// 24 hours after the previous handoff, but the handoff is at 09:00 EDT.
+		{"24 hours after handoff on spring forward day", daily, date(newYork, 2023, 3, 12, 8, 30), "b"},
+		{"handoff on spring forward day", daily, date(newYork, 2023, 3, 12, 9, 0), "c"},
+		{"handoff after spring forward", daily, date(newYork, 2023, 3, 13, 9, 0), "a"},
+		{"before handoff on fall back day", fallBack, date(newYork, 2023, 11, 5, 8, 59), "b"},
+		// 24 hours after the previous handoff is 08:00 EST.
+		{"24 hours after handoff on fall back day", fallBack, date(newYork, 2023, 11, 5, 8, 0), "b"},
+		{"handoff on fall back day", fallBack, date(newYork, 2023, 11, 5, 9, 0), "c"},
+		{"hourly before spring forward", hourly, date(newYork, 2023, 3, 12, 1, 30), "b"},
+		// 03:30 EDT is 2.5 hours after 00:00 EST.
+		{"hourly after spring forward", hourly, date(newYork, 2023, 3, 12, 3, 30), "c"},
+	}
+
+	for _, test := range tests {
+		if got := onCall(t, test.schedule, test.at); got != test.expected {
+			t.Fatalf("%s: expected %q, got %q", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestPrecedence(t *testing.T) {
+	start := date(time.UTC, 2023, 1, 2, 0, 0)
+	schedule := newSchedule("",
+		newLayer("primary", start, v2beta2.RotationDaily, 1, "a"),
+		// The layer listed later takes precedence, it is only active for a day.
+		newLayer("holiday", start.AddDate(0, 0, 1), v2beta2.RotationDaily, 1, "b"),
+	)
+	end := metav1.NewTime(start.AddDate(0, 0, 2))
+	schedule.Spec.Layers[1].End = &end
+	schedule.Spec.Overrides = []v2beta2.OnCallOverride{{
+		Start:     metav1.NewTime(start.Add(time.Hour)),
+		End:       metav1.NewTime(start.Add(2 * time.Hour)),
+		Receivers: participants("c")[0],
+	}}
+
+	tests := []struct {
+		at       time.Time
+		expected string
+	}{
+		{start, "a"},
+		{start.Add(time.Hour), "c"},
+		{start.Add(2 * time.Hour), "a"},
+		{start.AddDate(0, 0, 1), "b"},
+		{start.AddDate(0, 0, 2), "a"},
+	}
+
+	for _, test := range tests {
+		if got := onCall(t, schedule, test.at); got != test.expected {
+			t.Fatalf("at %s: expected %q, got %q", test.at, test.expected, got)
+		}
+	}
+}
+
+func TestShifts(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schedule := newSchedule("America/New_York", newLayer("daily", date(newYork, 2023, 3, 10, 9, 0), v2beta2.RotationDaily, 1, "a", "b"))
+	schedule.Spec.Overrides = []v2beta2.OnCallOverride{{
+		Start:     metav1.NewTime(date(newYork, 2023, 3, 13, 12, 0)),
+		End:       metav1.NewTime(date(newYork, 2023, 3, 13, 14, 0)),
+		Receivers: participants("a")[0],
+	}}
+
+	shifts, err := Shifts(schedule, date(newYork, 2023, 3, 10, 0, 0), date(newYork, 2023, 3, 14, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		start, end time.Time
+		name       string
+		override   bool
+	}{
+		// Nobody is on call before the layer starts.
+		{date(newYork, 2023, 3, 10, 9, 0), date(newYork, 2023, 3, 11, 9, 0), "a", false},
+		// The shift on the spring forward day is 23 hours.
+		{date(newYork, 2023, 3, 11, 9, 0), date(newYork, 2023, 3, 12, 9, 0), "b", false},
+		{date(newYork, 2023, 3, 12, 9, 0), date(newYork, 2023, 3, 13, 9, 0), "a", false},
+		{date(newYork, 2023, 3, 13, 9, 0), date(newYork, 2023, 3, 13, 12, 0), "b", false},
+		{date(newYork, 2023, 3, 13, 12, 0), date(newYork, 2023, 3, 13, 14, 0), "a", true},
+		// The shift is truncated by the time range.
+		{date(newYork, 2023, 3, 13, 14, 0), date(newYork, 2023, 3, 14, 0, 0), "b", false},
+	}
+
+	if len(shifts) != len(expected) {
+		t.Fatalf("expected %d shifts, got %v", len(expected), shifts)
+	}
+	for i, e := range expected {
+		s := shifts[i]
+		if !s.Start.Equal(e.start) || !s.End.Equal(e.end) || s.Receivers.Name[0] != e.name || s.Override != e.override {
+			t.Fatalf("shift %d: expected %s-%s %s override %v, got %s-%s %s override %v",
+				i, e.start, e.end, e.name, e.override, s.Start, s.End, s.Receivers.Name[0], s.Override)
+		}
+		if s.Start.Location().String() != "America/New_York" {
+			t.Fatalf("shift %d: expected the time in the time zone of the schedule", i)
+		}
+	}
+
+	if d := shifts[1].End.Sub(shifts[1].Start); d != 23*time.Hour {
+		t.Fatalf("expected the shift on the spring forward day to be 23 hours, got %s", d)
+	}
+}
+
+func TestShiftsMerge(t *testing.T) {
+	// A single participant is on call all the time, the shifts are merged.
+	schedule := newSchedule("", newLayer("daily", date(time.UTC, 2023, 1, 1, 0, 0), v2beta2.RotationDaily, 1, "a"))
+
+	shifts, err := Shifts(schedule, date(time.UTC, 2023, 1, 1, 0, 0), date(time.UTC, 2023, 1, 8, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shifts) != 1 || !shifts[0].End.Equal(date(time.UTC, 2023, 1, 8, 0, 0)) {
+		t.Fatalf("expected a single merged shift, got %v", shifts)
+	}
+
+	if shifts, err := Shifts(schedule, date(time.UTC, 2023, 1, 8, 0, 0), date(time.UTC, 2023, 1, 1, 0, 0)); err != nil || shifts != nil {
+		t.Fatalf("expected no shifts for an empty time range, got %v, %v", shifts, err)
+	}
+}
+
+func TestInvalidTimeZone(t *testing.T) {
+	schedule := newSchedule("Mars/Olympus", newLayer("weekly", date(time.UTC, 2023, 1, 2, 9, 0), "", 0, "a"))
+
+	if _, err := OnCall(schedule, time.Now()); err == nil {
+		t.Fatal("expected an error for the invalid time zone")
+	}
+	if _, err := Shifts(schedule, time.Now(), time.Now().Add(time.Hour)); err == nil {
+		t.Fatal("expected an error for the invalid time zone")
+	}
+}
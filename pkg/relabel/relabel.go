@@ -0,0 +1,180 @@
+package relabel
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+)
+
+const (
+	ActionReplace   = "replace"
+	ActionKeep      = "keep"
+	ActionDrop      = "drop"
+	ActionLabelDrop = "labeldrop"
+	ActionLabelKeep = "labelkeep"
+	ActionLabelMap  = "labelmap"
+
+	ScopeLabels      = "labels"
+	ScopeAnnotations = "annotations"
+
+	defaultSeparator   = ";"
+	defaultRegex       = "(.*)"
+	defaultReplacement = "$1"
+)
+
+// Config is a relabel config whose defaults have been filled in and whose regular expression has been compiled.
+type Config struct {
+	sourceLabels []string
+	separator    string
+	regex        *regexp.Regexp
+	targetLabel  string
+	replacement  string
+	action       string
+	scope        string
+}
+
+// New checks the relabel config and compiles it.
+func New(c v2beta2.RelabelConfig) (*Config, error) {
+
+	cfg := &Config{
+		sourceLabels: c.SourceLabels,
+		separator:    defaultSeparator,
+		targetLabel:  c.TargetLabel,
+		replacement:  defaultReplacement,
+		action:       c.Action,
+		scope:        c.Scope,
+	}
+
+	if c.Separator != nil {
+		cfg.separator = *c.Separator
+	}
+
+	if c.Replacement != nil {
+		cfg.replacement = *c.Replacement
+	}
+
+	if utils.StringIsNil(cfg.action) {
+		cfg.action = ActionReplace
+	}
+
+	if utils.StringIsNil(cfg.scope) {
+		cfg.scope = ScopeLabels
+	}
+
+	regex := c.Regex
+	if utils.StringIsNil(regex) {
+		regex = defaultRegex
+	}
+
+	var err error
+	cfg.regex, err = regexp.Compile("^(?:" + regex + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %s, %s", c.Regex, err.Error())
+	}
+
+	switch cfg.action {
+	case ActionReplace:
+		if utils.StringIsNil(cfg.targetLabel) {
+			return nil, fmt.Errorf("targetLabel is required for %s action", cfg.action)
+		}
+	case ActionKeep, ActionDrop, ActionLabelDrop, ActionLabelKeep, ActionLabelMap:
+	default:
+		return nil, fmt.Errorf("unknown relabel action %s", cfg.action)
+	}
+
+	switch cfg.scope {
+	case ScopeLabels, ScopeAnnotations:
+	default:
+		return nil, fmt.Errorf("unknown relabel scope %s", cfg.scope)
+	}
+
+	return cfg, nil
+}
+
+// Process applies the relabel configs to the alert in order.
+// It returns false if the alert is dropped by a keep or drop action.
+func Process(alert *template.Alert, cfgs ...*Config) bool {
+
+	for _, cfg := range cfgs {
+		var kv template.KV
+		if cfg.scope == ScopeAnnotations {
+			if alert.Annotations == nil {
+				alert.Annotations = make(template.KV)
+			}
+			kv = alert.Annotations
+		} else {
+			if alert.Labels == nil {
+				alert.Labels = make(template.KV)
+			}
+			kv = alert.Labels
+		}
+
+		if !cfg.relabel(kv) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (cfg *Config) relabel(kv template.KV) bool {
+
+	values := make([]string, 0, len(cfg.sourceLabels))
+	for _, label := range cfg.sourceLabels {
+		values = append(values, kv[label])
+	}
+	value := strings.Join(values, cfg.separator)
+
+	switch cfg.action {
+	case ActionKeep:
+		return cfg.regex.MatchString(value)
+	case ActionDrop:
+		return !cfg.regex.MatchString(value)
+	case ActionReplace:
+		indexes := cfg.regex.FindStringSubmatchIndex(value)
+		// If there is no match, no replacement should take place.
+		if indexes == nil {
+			break
+		}
+
+		target := string(cfg.regex.ExpandString([]byte{}, cfg.targetLabel, value, indexes))
+		if utils.StringIsNil(target) {
+			break
+		}
+
+		res := cfg.regex.ExpandString([]byte{}, cfg.replacement, value, indexes)
+		if len(res) == 0 {
+			delete(kv, target)
+			break
+		}
+		kv[target] = string(res)
+	case ActionLabelDrop:
+		for k := range kv {
+			if cfg.regex.MatchString(k) {
+				delete(kv, k)
+			}
+		}
+	case ActionLabelKeep:
+		for k := range kv {
+			if !cfg.regex.MatchString(k) {
+				delete(kv, k)
+			}
+		}
+	case ActionLabelMap:
+		mapped := make(template.KV)
+		for k, v := range kv {
+			if cfg.regex.MatchString(k) {
+				mapped[cfg.regex.ReplaceAllString(k, cfg.replacement)] = v
+			}
+		}
+		for k, v := range mapped {
+			kv[k] = v
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,163 @@
+package relabel
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/template"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestProcess(t *testing.T) {
+	tests := []struct {
+		name        string
+		configs     []v2beta2.RelabelConfig
+		labels      template.KV
+		annotations template.KV
+		keep        bool
+		expected    template.KV
+	}{
+		{
+			name: "replace with the default regex",
+			configs: []v2beta2.RelabelConfig{
+				{SourceLabels: []string{"cluster", "namespace"}, TargetLabel: "scope"},
+			},
+			labels:   template.KV{"cluster": "host", "namespace": "default"},
+			keep:     true,
+			expected: template.KV{"cluster": "host", "namespace": "default", "scope": "host;default"},
+		},
+		{
+			name: "replace with capture groups in the target label",
+			configs: []v2beta2.RelabelConfig{
+				{SourceLabels: []string{"pod"}, Regex: "(.*)-([0-9]+)", TargetLabel: "${1}_index", Replacement: strPtr("$2")},
+			},
+			labels:   template.KV{"pod": "web-1"},
+			keep:     true,
+			expected: template.KV{"pod": "web-1", "web_index": "1"},
+		},
+		{
+			name: "replace does nothing if the regex does not match",
+			configs: []v2beta2.RelabelConfig{
+				{SourceLabels: []string{"pod"}, Regex: "db-.*", TargetLabel: "app", Replacement: strPtr("db")},
+			},
+			labels:   template.KV{"pod": "web-1"},
+			keep:     true,
+			expected: template.KV{"pod": "web-1"},
+		},
+		{
+			name: "empty replacement removes the target label",
+			configs: []v2beta2.RelabelConfig{
+				{SourceLabels: []string{"pod"}, TargetLabel: "app", Replacement: strPtr("")},
+			},
+			labels:   template.KV{"pod": "web-1", "app": "web"},
+			keep:     true,
+			expected: template.KV{"pod": "web-1"},
+		},
+		{
+			name: "keep the matched alert",
+			configs: []v2beta2.RelabelConfig{
+				{SourceLabels: []string{"severity"}, Regex: "critical|warning", Action: ActionKeep},
+			},
+			labels:   template.KV{"severity": "critical"},
+			keep:     true,
+			expected: template.KV{"severity": "critical"},
+		},
+		{
+			name: "keep drops the alert missing the source label",
+			configs: []v2beta2.RelabelConfig{
+				{SourceLabels: []string{"severity"}, Regex: "critical", Action: ActionKeep},
+			},
+			labels: template.KV{"alertname": "test"},
+			keep:   false,
+		},
+		{
+			name: "drop the matched alert",
+			configs: []v2beta2.RelabelConfig{
+				{SourceLabels: []string{"namespace"}, Regex: "test-.*", Action: ActionDrop},
+			},
+			labels: template.KV{"namespace": "test-1"},
+			keep:   false,
+		},
+		{
+			name: "labeldrop and labelkeep",
+			configs: []v2beta2.RelabelConfig{
+				{Regex: "tmp_.*", Action: ActionLabelDrop},
+				{Regex: "alertname|namespace", Action: ActionLabelKeep},
+			},
+			labels:   template.KV{"alertname": "test", "namespace": "default", "tmp_id": "1", "pod": "web-1"},
+			keep:     true,
+			expected: template.KV{"alertname": "test", "namespace": "default"},
+		},
+		{
+			name: "labelmap",
+			configs: []v2beta2.RelabelConfig{
+				{Regex: "label_(.+)", Action: ActionLabelMap},
+			},
+			labels:   template.KV{"label_app": "web"},
+			keep:     true,
+			expected: template.KV{"label_app": "web", "app": "web"},
+		},
+		{
+			name: "the configs apply in order",
+			configs: []v2beta2.RelabelConfig{
+				{SourceLabels: []string{"severity"}, TargetLabel: "level"},
+				{SourceLabels: []string{"level"}, Regex: "info", Action: ActionDrop},
+			},
+			labels: template.KV{"severity": "info"},
+			keep:   false,
+		},
+		{
+			name: "relabel the annotations",
+			configs: []v2beta2.RelabelConfig{
+				{SourceLabels: []string{"summary"}, TargetLabel: "message", Scope: ScopeAnnotations},
+			},
+			labels:      template.KV{"alertname": "test"},
+			annotations: template.KV{"summary": "disk full"},
+			keep:        true,
+			expected:    template.KV{"alertname": "test"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var cfgs []*Config
+			for _, c := range test.configs {
+				cfg, err := New(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+				cfgs = append(cfgs, cfg)
+			}
+
+			alert := &template.Alert{Labels: test.labels, Annotations: test.annotations}
+			if keep := Process(alert, cfgs...); keep != test.keep {
+				t.Fatalf("expected keep %v, got %v", test.keep, keep)
+			}
+			if test.keep && !reflect.DeepEqual(alert.Labels, test.expected) {
+				t.Fatalf("expected labels %v, got %v", test.expected, alert.Labels)
+			}
+			if test.annotations != nil && alert.Annotations["message"] != test.annotations["summary"] {
+				t.Fatalf("expected the annotation to be relabeled, got %v", alert.Annotations)
+			}
+		})
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	tests := []v2beta2.RelabelConfig{
+		{Regex: "(", TargetLabel: "a"},
+		{SourceLabels: []string{"a"}},
+		{Action: "unknown"},
+		{Action: ActionDrop, Scope: "unknown"},
+	}
+
+	for _, c := range tests {
+		if _, err := New(c); err == nil {
+			t.Fatalf("expected the config %+v to be invalid", c)
+		}
+	}
+}
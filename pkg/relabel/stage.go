@@ -0,0 +1,60 @@
+package relabel
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/stage"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/modern-go/reflect2"
+)
+
+type relabelStage struct {
+	notifierCtl *controller.Controller
+}
+
+// NewStage returns a stage which rewrites the labels and annotations of the alerts with the relabel configs,
+// the alerts dropped by the relabel configs will not be processed any more.
+func NewStage(notifierCtl *controller.Controller) stage.Stage {
+	return &relabelStage{
+		notifierCtl: notifierCtl,
+	}
+}
+
+func (s *relabelStage) Exec(ctx context.Context, l log.Logger, data interface{}) (context.Context, interface{}, error) {
+
+	if reflect2.IsNil(data) {
+		return ctx, nil, nil
+	}
+
+	relabelConfigs := s.notifierCtl.GetRelabelConfigs()
+	if len(relabelConfigs) == 0 {
+		return ctx, data, nil
+	}
+
+	input := data.([]*template.Alert)
+
+	_ = level.Debug(l).Log("msg", "Start relabel stage", "seq", ctx.Value("seq"), "alert", len(input))
+
+	var cfgs []*Config
+	for index, rc := range relabelConfigs {
+		cfg, err := New(rc)
+		if err != nil {
+			// Skip the invalid relabel config, so that the others can still take effect.
+			_ = level.Error(l).Log("msg", "Invalid relabel config", "stage", "Relabel", "seq", ctx.Value("seq"), "index", index, "error", err.Error())
+			continue
+		}
+		cfgs = append(cfgs, cfg)
+	}
+
+	var output []*template.Alert
+	for _, alert := range input {
+		if Process(alert, cfgs...) {
+			output = append(output, alert)
+		}
+	}
+
+	return ctx, output, nil
+}
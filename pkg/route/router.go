@@ -3,17 +3,19 @@ package route
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/modern-go/reflect2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kubesphere/notification-manager/apis/v2beta2"
 	"github.com/kubesphere/notification-manager/pkg/constants"
 	"github.com/kubesphere/notification-manager/pkg/controller"
 	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/matcher"
 	"github.com/kubesphere/notification-manager/pkg/stage"
+	"github.com/kubesphere/notification-manager/pkg/status"
 	"github.com/kubesphere/notification-manager/pkg/template"
 	"github.com/kubesphere/notification-manager/pkg/utils"
 )
@@ -25,6 +27,7 @@ const (
 
 type routeStage struct {
 	notifierCtl *controller.Controller
+	recorder    *status.Recorder
 }
 
 type packet struct {
@@ -32,9 +35,10 @@ type packet struct {
 	alerts   []*template.Alert
 }
 
-func NewStage(notifierCtl *controller.Controller) stage.Stage {
+func NewStage(notifierCtl *controller.Controller, recorder *status.Recorder) stage.Stage {
 	return &routeStage{
 		notifierCtl: notifierCtl,
+		recorder:    recorder,
 	}
 }
 
@@ -55,14 +59,13 @@ func (s *routeStage) Exec(ctx context.Context, l log.Logger, data interface{}) (
 	}
 
 	// Grouping alerts by cluster and namespace
-	alertMap := make(map[string][]*template.Alert)
+	alertMap := make(map[controller.ClusterNamespace][]*template.Alert)
 	for _, alert := range input {
-		key := ""
+		key := controller.ClusterNamespace{}
 		rl := alert.Labels[constants.RuleLevel]
 		if rl == constants.RuleLevelNamespace {
-			ns := alert.Labels[constants.Namespace]
-			cluster := alert.Labels[constants.Cluster]
-			key = fmt.Sprintf("%s|%s", cluster, ns)
+			key.Namespace = alert.Labels[constants.Namespace]
+			key.Cluster = alert.Labels[constants.Cluster]
 		}
 
 		as := alertMap[key]
@@ -70,36 +73,54 @@ func (s *routeStage) Exec(ctx context.Context, l log.Logger, data interface{}) (
 		alertMap[key] = as
 	}
 
-	m := make(map[string]*packet)
-	routePolicy := s.notifierCtl.GetRoutePolicy()
+	// The alerts which need to be sent to the tenant receivers.
+	tenantAlerts := make(map[*template.Alert]bool)
+	var nss []controller.ClusterNamespace
+	routed := make(map[*template.Alert][]internal.Receiver)
 	for key, alerts := range alertMap {
-		var tenantRcvs []internal.Receiver
-		if routePolicy != RouterOnly {
-			pair := strings.Split(key, "|")
-			cluster := pair[0]
-			ns := ""
-			if len(pair) > 1 {
-				ns = pair[1]
+		for _, alert := range alerts {
+			rcvs, routePolicy := s.rcvsFromRouter(l, alert, routers)
+			if utils.StringIsNil(routePolicy) {
+				routePolicy = s.notifierCtl.GetRoutePolicy()
+			}
+
+			if routePolicy != RouterOnly && !(routePolicy == RouterFirst && len(rcvs) != 0) {
+				if len(nss) == 0 || nss[len(nss)-1] != key {
+					nss = append(nss, key)
+				}
+				tenantAlerts[alert] = true
 			}
-			tenantRcvs = s.notifierCtl.RcvsFromNs(cluster, &ns)
+			routed[alert] = rcvs
 		}
+	}
+
+	// The tenant receivers of all the clusters and namespaces are looked up at once.
+	var tenantRcvs map[controller.ClusterNamespace][]internal.Receiver
+	if len(nss) > 0 {
+		tenantRcvs = s.notifierCtl.RcvsFromNamespaces(nss)
+	}
+
+	m := make(map[string]*packet)
+	for key, alerts := range alertMap {
 		for _, alert := range alerts {
-			rcvs := s.rcvsFromRouter(alert, routers)
-			if !(routePolicy == RouterFirst && len(rcvs) != 0) {
-				rcvs = append(rcvs, tenantRcvs...)
+			rcvs := routed[alert]
+			if tenantAlerts[alert] {
+				rcvs = append(rcvs, tenantRcvs[key]...)
 			}
 
 			rcvs = deduplication(rcvs)
 			for _, rcv := range rcvs {
-				hash := rcv.GetHash()
-				p := m[hash]
+				// The routers may set different group options and escalation policies for the same receiver,
+				// the alerts are put into different packets so that every alert uses the options of its router.
+				k := internal.RouteKey(rcv)
+				p := m[k]
 				if p == nil {
 					p = &packet{
 						receiver: rcv,
 					}
 				}
 				p.alerts = append(p.alerts, alert)
-				m[hash] = p
+				m[k] = p
 			}
 		}
 	}
@@ -116,32 +137,174 @@ func (s *routeStage) Exec(ctx context.Context, l log.Logger, data interface{}) (
 	return ctx, output, nil
 }
 
-func (s *routeStage) rcvsFromRouter(alert *template.Alert, routers []v2beta2.Router) []internal.Receiver {
+// rcvsFromRouter returns the receivers of the routers matched by the alert, and the route policy of the matched routers.
+func (s *routeStage) rcvsFromRouter(l log.Logger, alert *template.Alert, routers []v2beta2.Router) ([]internal.Receiver, string) {
+
+	nodes, routePolicy := s.matchRouters(l, alert, routers)
 
 	var rcvs []internal.Receiver
+	for _, n := range nodes {
+		rcvs = append(rcvs, s.rcvsFromNode(n)...)
+	}
+
+	return rcvs, routePolicy
+}
+
+// matchRouters returns the routing nodes of the routers matched by the alert, and the route policy of the matched routers.
+// The routers are evaluated in order, the nodes of all the matched routers are returned,
+// unless a matched router stops the evaluation by setting continue to false.
+func (s *routeStage) matchRouters(l log.Logger, alert *template.Alert, routers []v2beta2.Router) ([]*node, string) {
+
+	var res []*node
+	routePolicy := ""
 	for _, router := range routers {
-		ok, _ := router.Spec.AlertSelector.Matches(alert.Labels)
-		if !ok {
+		if !matcher.Matches(matcher.RouterKey(router.Name), matcher.Version(&router), router.Spec.AlertSelector, alert.Labels) {
 			continue
 		}
+		s.recorder.RouterMatched(router.Name)
 
-		if len(router.Spec.Receivers.Name) > 0 || !utils.StringIsNil(router.Spec.Receivers.RegexName) {
-			rcvs = append(rcvs, s.notifierCtl.RcvsFromName(router.Spec.Receivers.Name, router.Spec.Receivers.RegexName, router.Spec.Receivers.Type)...)
+		root := &node{
+			receivers:        router.Spec.Receivers,
+			groupLabels:      router.Spec.GroupLabels,
+			groupWait:        router.Spec.GroupWait,
+			groupInterval:    router.Spec.GroupInterval,
+			escalationPolicy: router.Spec.EscalationPolicy,
 		}
-		if router.Spec.Receivers.Selector != nil {
-			rcvs = append(rcvs, s.notifierCtl.RcvsFromSelector(router.Spec.Receivers.Selector, router.Spec.Receivers.Type)...)
+
+		routes, err := trees.get(router.Name, matcher.Version(&router), router.Spec.Routes)
+		if err != nil {
+			_ = level.Error(l).Log("msg", "RouteStage: parse routes failed", "router", router.Name, "error", err.Error())
+		}
+
+		nodes := walk(l, alert, root, routes, matcher.RouterKey(router.Name), matcher.Version(&router))
+		if len(nodes) == 0 {
+			nodes = []*node{root}
+		}
+		res = append(res, nodes...)
+
+		if utils.StringIsNil(routePolicy) {
+			routePolicy = router.Spec.RoutePolicy
+		}
+
+		if !v2beta2.Continues(router.Spec.Continue) {
+			break
+		}
+	}
+
+	return res, routePolicy
+}
+
+// node is a matched node of the routing tree, the unset settings have been inherited from its parent.
+type node struct {
+	receivers        v2beta2.ReceiverSelector
+	groupLabels      []string
+	groupWait        *metav1.Duration
+	groupInterval    *metav1.Duration
+	escalationPolicy string
+}
+
+func (n *node) inherit(route v2beta2.Route) *node {
+
+	child := *n
+	if route.Receivers != nil {
+		child.receivers = *route.Receivers
+	}
+	if len(route.GroupLabels) > 0 {
+		child.groupLabels = route.GroupLabels
+	}
+	if route.GroupWait != nil {
+		child.groupWait = route.GroupWait
+	}
+	if route.GroupInterval != nil {
+		child.groupInterval = route.GroupInterval
+	}
+	if !utils.StringIsNil(route.EscalationPolicy) {
+		child.escalationPolicy = route.EscalationPolicy
+	}
+
+	return &child
+}
+
+// walk matches the alert against the child routes in order, and returns the deepest matched nodes.
+// A matched route which has no matched child route is a matched node itself.
+// The key and the version identify the alert selectors of the routes in the router.
+func walk(l log.Logger, alert *template.Alert, parent *node, routes []*routeTree, key, version string) []*node {
+
+	var nodes []*node
+	for i, tree := range routes {
+		route := tree.route
+		k := fmt.Sprintf("%s/routes/%d", key, i)
+		if !matcher.Matches(k, version, route.AlertSelector, alert.Labels) {
+			continue
+		}
+
+		if tree.err != nil {
+			_ = level.Error(l).Log("msg", "RouteStage: parse routes failed", "route", k, "error", tree.err.Error())
+		}
+
+		n := parent.inherit(route)
+		if children := walk(l, alert, n, tree.children, k, version); len(children) > 0 {
+			nodes = append(nodes, children...)
+		} else {
+			nodes = append(nodes, n)
+		}
+
+		if !v2beta2.Continues(route.Continue) {
+			break
+		}
+	}
+
+	return nodes
+}
+
+func (s *routeStage) rcvsFromNode(n *node) []internal.Receiver {
+
+	rcvs := s.notifierCtl.RcvsFromReceiverSelector(n.receivers)
+	if opts := groupOptions(n); opts != nil {
+		for _, rcv := range rcvs {
+			// The group options of the receiver take precedence over the router.
+			rcv.SetGroupOptions(rcv.GetGroupOptions().Merge(opts))
+		}
+	}
+	if !utils.StringIsNil(n.escalationPolicy) {
+		for _, rcv := range rcvs {
+			// The escalation policy of the receiver takes precedence over the router.
+			if utils.StringIsNil(rcv.GetEscalationPolicy()) {
+				rcv.SetEscalationPolicy(n.escalationPolicy)
+			}
 		}
-		rcvs = append(rcvs, s.notifierCtl.RcvsFromTenant(router.Spec.Receivers.Channels)...)
 	}
 
 	return rcvs
 }
 
+// groupOptions returns the group options of the routing node which override the global group options.
+func groupOptions(n *node) *internal.GroupOptions {
+	if len(n.groupLabels) == 0 && n.groupWait == nil && n.groupInterval == nil {
+		return nil
+	}
+
+	opts := internal.NewGroupOptions(n.groupLabels)
+	if opts == nil {
+		opts = &internal.GroupOptions{}
+	}
+	if n.groupWait != nil {
+		opts.GroupWait = &n.groupWait.Duration
+	}
+	if n.groupInterval != nil {
+		opts.GroupInterval = &n.groupInterval.Duration
+	}
+	return opts
+}
+
 func deduplication(rcvs []internal.Receiver) []internal.Receiver {
 
 	m := make(map[string]internal.Receiver)
 	for _, rcv := range rcvs {
-		m[rcv.GetHash()] = rcv
+		// Keep the first one, so that the receivers matched by routers take precedence over the tenant receivers.
+		if _, ok := m[rcv.GetHash()]; !ok {
+			m[rcv.GetHash()] = rcv
+		}
 	}
 
 	var res []internal.Receiver
@@ -0,0 +1,78 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newRouter(name, receiver string, selector map[string]string, continues *bool, routePolicy string) v2beta2.Router {
+	r := v2beta2.Router{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 1},
+		Spec: v2beta2.RouterSpec{
+			Receivers:   v2beta2.ReceiverSelector{Name: []string{receiver}},
+			Continue:    continues,
+			RoutePolicy: routePolicy,
+		},
+	}
+	if selector != nil {
+		r.Spec.AlertSelector = &v2beta2.LabelSelector{MatchLabels: selector}
+	}
+	return r
+}
+
+func TestMatchRouters(t *testing.T) {
+	stop := false
+	routers := []v2beta2.Router{
+		newRouter("critical", "pager", map[string]string{"severity": "critical"}, nil, ""),
+		newRouter("db", "dba", map[string]string{"team": "db"}, &stop, "RouterOnly"),
+		newRouter("web", "web", map[string]string{"team": "web"}, nil, "RouterFirst"),
+		newRouter("default", "user1", nil, nil, "All"),
+	}
+
+	tests := []struct {
+		name        string
+		labels      template.KV
+		receivers   []string
+		routePolicy string
+	}{
+		// The routers continue by default, the alert is sent to the receivers of all the matched routers.
+		{"fan out by default", template.KV{"team": "web", "severity": "critical"}, []string{"pager", "web", "user1"}, "RouterFirst"},
+		{"only the default router", template.KV{"team": "app"}, []string{"user1"}, "All"},
+		// The evaluation stops at the matched router which does not continue.
+		{"stop at the router", template.KV{"team": "db", "severity": "critical"}, []string{"pager", "dba"}, "RouterOnly"},
+		{"stop at the router without the routers before", template.KV{"team": "db"}, []string{"dba"}, "RouterOnly"},
+	}
+
+	s := &routeStage{}
+	for _, test := range tests {
+		nodes, routePolicy := s.matchRouters(log.NewNopLogger(), &template.Alert{Labels: test.labels}, routers)
+		if len(nodes) != len(test.receivers) {
+			t.Fatalf("%s: expected receivers %v, got %d nodes", test.name, test.receivers, len(nodes))
+		}
+		for i, n := range nodes {
+			if n.receivers.Name[0] != test.receivers[i] {
+				t.Fatalf("%s: expected receivers %v, got %s at %d", test.name, test.receivers, n.receivers.Name[0], i)
+			}
+		}
+		// The routePolicy of the first matched router which sets it is used.
+		if routePolicy != test.routePolicy {
+			t.Fatalf("%s: expected route policy %q, got %q", test.name, test.routePolicy, routePolicy)
+		}
+	}
+}
+
+func TestMatchRoutersRoutePolicyNotSet(t *testing.T) {
+	routers := []v2beta2.Router{
+		newRouter("a", "a", nil, nil, ""),
+		newRouter("b", "b", nil, nil, "RouterOnly"),
+	}
+
+	s := &routeStage{}
+	if _, routePolicy := s.matchRouters(log.NewNopLogger(), &template.Alert{Labels: template.KV{"alertname": "a"}}, routers); routePolicy != "RouterOnly" {
+		t.Fatalf("expected the route policy of the router which sets it, got %q", routePolicy)
+	}
+}
@@ -0,0 +1,107 @@
+package route
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+const (
+	// The routing trees which have not been used in this interval will be removed.
+	sweepInterval = 10 * time.Minute
+)
+
+var trees = newTreeCache()
+
+// routeTree is a parsed route and its child routes.
+type routeTree struct {
+	route    v2beta2.Route
+	children []*routeTree
+	// The error of parsing the child routes, the route is treated as a leaf if it is set.
+	err error
+}
+
+// parseTree parses the routes and their child routes recursively.
+func parseTree(raw []apiextensionsv1.JSON) ([]*routeTree, error) {
+
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	routes, err := v2beta2.ParseRoutes(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*routeTree
+	for _, route := range routes {
+		tree := &routeTree{route: route}
+		tree.children, tree.err = parseTree(route.Routes)
+		res = append(res, tree)
+	}
+
+	return res, nil
+}
+
+// treeCache caches the routing trees of the routers, so that the routes are not parsed for every alert.
+// The routing tree of a router is parsed again when the version of the router changes.
+type treeCache struct {
+	mutex     sync.RWMutex
+	entries   map[string]*treeEntry
+	lastSweep time.Time
+}
+
+type treeEntry struct {
+	version string
+	trees   []*routeTree
+	err     error
+	used    int32
+}
+
+func newTreeCache() *treeCache {
+	return &treeCache{
+		entries:   make(map[string]*treeEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// get returns the routing tree of the router, the error of parsing is cached as well.
+func (c *treeCache) get(name, version string, raw []apiextensionsv1.JSON) ([]*routeTree, error) {
+
+	c.mutex.RLock()
+	e, ok := c.entries[name]
+	c.mutex.RUnlock()
+	if ok && e.version == version {
+		atomic.StoreInt32(&e.used, 1)
+		return e.trees, e.err
+	}
+
+	e = &treeEntry{
+		version: version,
+		used:    1,
+	}
+	e.trees, e.err = parseTree(raw)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[name] = e
+	if time.Since(c.lastSweep) > sweepInterval {
+		c.sweep()
+	}
+
+	return e.trees, e.err
+}
+
+// sweep removes the routing trees which have not been used since the last sweep, it must be called with the lock held.
+func (c *treeCache) sweep() {
+	for k, e := range c.entries {
+		if atomic.SwapInt32(&e.used, 0) == 0 {
+			delete(c.entries, k)
+		}
+	}
+	c.lastSweep = time.Now()
+}
@@ -0,0 +1,129 @@
+package route
+
+import (
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func raw(routes ...string) []apiextensionsv1.JSON {
+	var res []apiextensionsv1.JSON
+	for _, r := range routes {
+		res = append(res, apiextensionsv1.JSON{Raw: []byte(r)})
+	}
+	return res
+}
+
+func TestTreeCache(t *testing.T) {
+	c := newTreeCache()
+	routes := raw(`{"alertSelector":{"matchLabels":{"severity":"critical"}},"routes":[{"escalationPolicy":"page"}]}`)
+
+	first, err := c.get("router", "1", routes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 1 || len(first[0].children) != 1 || first[0].children[0].route.EscalationPolicy != "page" {
+		t.Fatalf("unexpected routing tree %v", first)
+	}
+
+	// The routes are parsed once for a version.
+	second, _ := c.get("router", "1", raw(`{"escalationPolicy":"changed"}`))
+	if second[0] != first[0] {
+		t.Fatal("expected the cached routing tree to be returned")
+	}
+
+	// The routes are parsed again when the version changes.
+	third, _ := c.get("router", "2", raw(`{"escalationPolicy":"changed"}`))
+	if len(third) != 1 || third[0].route.EscalationPolicy != "changed" {
+		t.Fatalf("expected the routing tree to be parsed again, got %v", third)
+	}
+
+	// The error is cached as well.
+	if _, err := c.get("invalid", "1", raw(`[`)); err == nil {
+		t.Fatal("expected an error for the invalid routes")
+	}
+	if _, err := c.get("invalid", "1", routes); err == nil {
+		t.Fatal("expected the error to be cached")
+	}
+
+	// The routing trees which are not used since the last sweep are removed.
+	c.mutex.Lock()
+	c.sweep()
+	c.sweep()
+	c.mutex.Unlock()
+	if len(c.entries) != 0 {
+		t.Fatalf("expected the unused routing trees to be removed, got %d", len(c.entries))
+	}
+}
+
+func TestParseTreeChildError(t *testing.T) {
+	trees, err := parseTree(raw(`{"escalationPolicy":"page","routes":["invalid"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trees) != 1 || trees[0].err == nil || trees[0].children != nil {
+		t.Fatalf("expected the route with invalid child routes to be a leaf, got %v", trees)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	trees, err := parseTree(raw(
+		`{"alertSelector":{"matchLabels":{"team":"db"}},"receivers":{"name":["db"]},"groupLabels":["cluster"],"continue":false,
+		  "routes":[
+		    {"alertSelector":{"matchLabels":{"severity":"critical"}},"escalationPolicy":"page"},
+		    {"alertSelector":{"matchLabels":{"cluster":"prod"}},"receivers":{"name":["prod"]},"continue":false},
+		    {"receivers":{"name":["other"]}}
+		  ]}`,
+		`{"receivers":{"name":["default"]}}`,
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := &node{receivers: v2beta2.ReceiverSelector{Name: []string{"root"}}}
+	tests := []struct {
+		labels    template.KV
+		receivers []string
+		policies  []string
+	}{
+		// The routes continue by default until a matched route which does not continue, the deepest matched routes are returned.
+		{template.KV{"team": "db", "severity": "critical", "cluster": "prod"}, []string{"db", "prod"}, []string{"page", ""}},
+		{template.KV{"team": "db", "severity": "warning", "cluster": "prod"}, []string{"prod"}, []string{""}},
+		{template.KV{"team": "db", "severity": "critical", "cluster": "test"}, []string{"db", "other"}, []string{"page", ""}},
+		{template.KV{"team": "db", "severity": "warning", "cluster": "test"}, []string{"other"}, []string{""}},
+		{template.KV{"team": "web"}, []string{"default"}, []string{""}},
+	}
+
+	for _, test := range tests {
+		nodes := walk(log.NewNopLogger(), &template.Alert{Labels: test.labels}, root, trees, "router/test", "1")
+		if len(nodes) != len(test.receivers) {
+			t.Fatalf("%v: expected %d nodes, got %d", test.labels, len(test.receivers), len(nodes))
+		}
+		for i, n := range nodes {
+			if n.receivers.Name[0] != test.receivers[i] || n.escalationPolicy != test.policies[i] {
+				t.Fatalf("%v: node %d expected %s %q, got %s %q", test.labels, i, test.receivers[i], test.policies[i], n.receivers.Name[0], n.escalationPolicy)
+			}
+		}
+		// The unset settings are inherited from the parent route.
+		if test.labels["team"] == "db" && (len(nodes[0].groupLabels) != 1 || nodes[0].groupLabels[0] != "cluster") {
+			t.Fatalf("%v: expected the group labels to be inherited", test.labels)
+		}
+	}
+}
+
+func TestWalkLeaf(t *testing.T) {
+	trees, err := parseTree(raw(`{"alertSelector":{"matchLabels":{"team":"db"}},"receivers":{"name":["db"]},
+		"routes":[{"alertSelector":{"matchLabels":{"severity":"critical"}},"receivers":{"name":["pager"]}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A matched route without matched child routes is a leaf.
+	nodes := walk(log.NewNopLogger(), &template.Alert{Labels: template.KV{"team": "db"}}, &node{}, trees, "router/test", "1")
+	if len(nodes) != 1 || nodes[0].receivers.Name[0] != "db" {
+		t.Fatalf("expected the matched route to be a leaf, got %v", nodes)
+	}
+}
@@ -0,0 +1,282 @@
+package silence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/constants"
+	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// The annotation records the expiry time of the silence of which the author has been notified,
+	// the author will be notified again if the silence is extended.
+	expiryNotifiedAnnotation = "notification.kubesphere.io/expiry-notified"
+	// The annotation records the time the silence was found disabled, the disabled silence never expires,
+	// so it is garbage collected after it has been disabled for the retention.
+	disabledAtAnnotation = "notification.kubesphere.io/disabled-at"
+
+	gcPolicyDelete  = "delete"
+	gcPolicyArchive = "archive"
+
+	silenceExpiring = "SilenceExpiring"
+)
+
+var (
+	interval     *time.Duration
+	expiryNotice *time.Duration
+	retention    *time.Duration
+	gcPolicy     *string
+)
+
+func init() {
+	interval = kingpin.Flag(
+		"silence.lifecycle.interval",
+		"Interval to check whether the silences are expiring or need to be garbage collected",
+	).Default("1m").Duration()
+	expiryNotice = kingpin.Flag(
+		"silence.expiryNotice",
+		"How long before a silence expires to notify the receivers of its author, 0 means never notifying",
+	).Default("15m").Duration()
+	retention = kingpin.Flag(
+		"silence.retention",
+		"How long to keep a silence after it expired, 0 means keeping the expired silences forever",
+	).Default("168h").Duration()
+	gcPolicy = kingpin.Flag(
+		"silence.gcPolicy",
+		fmt.Sprintf("What to do with the silence after the retention, possible values: %s, %s", gcPolicyDelete, gcPolicyArchive),
+	).Default(gcPolicyArchive).Enum(gcPolicyDelete, gcPolicyArchive)
+}
+
+// silenceController is the part of the controller which the lifecycle depends on.
+type silenceController interface {
+	ListSilences() []v2beta2.Silence
+	SilenceTenants() map[string]string
+	RcvsFromTenant(channels []v2beta2.Channel) []internal.Receiver
+	UpdateSilence(ctx context.Context, silence *v2beta2.Silence) error
+	DeleteSilence(ctx context.Context, silence *v2beta2.Silence) error
+}
+
+// SendFunc sends the notifications to the receivers,
+// and calls release once the notifications have been sent or failed after all retries. The release can be nil.
+type SendFunc func(data map[internal.Receiver][]*template.Data, release func())
+
+// Lifecycle notifies the authors of the silences before the silences expire,
+// and deletes or archives the silences after they have expired for the retention.
+type Lifecycle struct {
+	notifierCtl  silenceController
+	logger       log.Logger
+	send         SendFunc
+	interval     time.Duration
+	expiryNotice time.Duration
+	retention    time.Duration
+	gcPolicy     string
+}
+
+func NewLifecycle(notifierCtl *controller.Controller, logger log.Logger, send SendFunc) *Lifecycle {
+	l := &Lifecycle{
+		notifierCtl:  notifierCtl,
+		logger:       logger,
+		send:         send,
+		interval:     *interval,
+		expiryNotice: *expiryNotice,
+		retention:    *retention,
+		gcPolicy:     *gcPolicy,
+	}
+
+	go l.run()
+	return l
+}
+
+func (l *Lifecycle) run() {
+	if l.interval <= 0 || (l.expiryNotice <= 0 && l.retention <= 0) {
+		return
+	}
+
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		l.check(time.Now())
+	}
+}
+
+// check notifies the expiry of the silences which are expiring, and garbage collects the silences which have expired
+// or have been disabled.
+func (l *Lifecycle) check(now time.Time) {
+	tenants := l.notifierCtl.SilenceTenants()
+	for _, silence := range l.notifierCtl.ListSilences() {
+		s := silence
+		if s.Labels[v2beta2.SilenceArchivedLabel] == "true" {
+			continue
+		}
+
+		if l.checkDisabled(&s, now) {
+			continue
+		}
+
+		expiresAt := s.ExpiresAt()
+		if expiresAt == nil {
+			continue
+		}
+
+		if expiresAt.After(now) {
+			if tenant, ok := tenants[s.Name]; ok {
+				l.notifyExpiry(&s, tenant, *expiresAt, now)
+			}
+		} else {
+			l.gc(&s, *expiresAt, now)
+		}
+	}
+}
+
+// checkDisabled records the time the silence was found disabled, and garbage collects the silence which has been
+// disabled for the retention. The record is removed once the silence is enabled again. It returns true if the silence is disabled.
+func (l *Lifecycle) checkDisabled(silence *v2beta2.Silence, now time.Time) bool {
+
+	disabled := silence.Spec.Enabled != nil && !*silence.Spec.Enabled
+	v, recorded := silence.Annotations[disabledAtAnnotation]
+	if !disabled && !recorded {
+		return false
+	}
+
+	if disabled && recorded {
+		if disabledAt, err := time.Parse(time.RFC3339, v); err == nil {
+			l.gc(silence, disabledAt, now)
+			return true
+		}
+	}
+
+	// The disabled silences are kept forever when the retention is 0, so the time need not be recorded.
+	if disabled && l.retention <= 0 {
+		return true
+	}
+
+	if disabled {
+		if silence.Annotations == nil {
+			silence.Annotations = make(map[string]string)
+		}
+		silence.Annotations[disabledAtAnnotation] = now.UTC().Format(time.RFC3339)
+	} else {
+		delete(silence.Annotations, disabledAtAnnotation)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.interval)
+	defer cancel()
+	if err := l.notifierCtl.UpdateSilence(ctx, silence); err != nil {
+		_ = level.Error(l.logger).Log("msg", "Silence: record disabled time failed", "silence", silence.Name, "disabled", disabled, "error", err.Error())
+	}
+
+	return disabled
+}
+
+// notifyExpiry notifies the receivers of the author once if the silence expires within the expiry notice.
+// The createdBy of a silence is free text, so the notification of a tenant silence is sent to the tenant the silence belongs to,
+// rather than the tenant in createdBy, so that a tenant can not send notifications to the other tenants.
+// The global silences can only be created by the administrators, the notifications of them are sent to the tenant in createdBy.
+func (l *Lifecycle) notifyExpiry(silence *v2beta2.Silence, tenant string, expiresAt, now time.Time) {
+
+	if l.expiryNotice <= 0 || expiresAt.Sub(now) > l.expiryNotice {
+		return
+	}
+
+	author := tenant
+	if utils.StringIsNil(author) {
+		author = silence.Spec.CreatedBy
+	}
+	if utils.StringIsNil(author) {
+		return
+	}
+
+	notified := expiresAt.UTC().Format(time.RFC3339)
+	if silence.Annotations[expiryNotifiedAnnotation] == notified {
+		return
+	}
+
+	rcvs := l.notifierCtl.RcvsFromTenant([]v2beta2.Channel{{Tenant: author}})
+	if len(rcvs) == 0 {
+		_ = level.Debug(l.logger).Log("msg", "Silence: no receivers to notify the expiry", "silence", silence.Name, "author", author)
+		return
+	}
+
+	// Record the notification before sending it, so that the author will not be notified repeatedly
+	// when the silence can not be updated.
+	if silence.Annotations == nil {
+		silence.Annotations = make(map[string]string)
+	}
+	silence.Annotations[expiryNotifiedAnnotation] = notified
+	ctx, cancel := context.WithTimeout(context.Background(), l.interval)
+	defer cancel()
+	if err := l.notifierCtl.UpdateSilence(ctx, silence); err != nil {
+		_ = level.Error(l.logger).Log("msg", "Silence: record expiry notification failed", "silence", silence.Name, "error", err.Error())
+		return
+	}
+
+	alert := &template.Alert{
+		Status: constants.AlertFiring,
+		Labels: template.KV{
+			constants.AlertName: silenceExpiring,
+			"silence":           silence.Name,
+			"severity":          "info",
+		},
+		Annotations: template.KV{
+			constants.AlertMessage: fmt.Sprintf("Silence %s expires in %s at %s", silence.Name,
+				expiresAt.Sub(now).Round(time.Minute).String(), expiresAt.UTC().Format(time.RFC3339)),
+			"comment": silence.Spec.Comment,
+		},
+		StartsAt: now,
+	}
+
+	res := make(map[internal.Receiver][]*template.Data)
+	for _, rcv := range rcvs {
+		d := &template.Data{
+			GroupLabels: template.KV{},
+			Alerts:      template.Alerts{alert.Clone()},
+		}
+		res[rcv] = []*template.Data{d.Format()}
+	}
+
+	_ = level.Debug(l.logger).Log("msg", "Silence: notify expiry", "silence", silence.Name, "author", author, "receivers", len(res))
+	l.send(res, nil)
+}
+
+// gc deletes or archives the silence which has expired or been disabled for the retention.
+func (l *Lifecycle) gc(silence *v2beta2.Silence, expiresAt, now time.Time) {
+
+	if l.retention <= 0 || now.Sub(expiresAt) < l.retention {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.interval)
+	defer cancel()
+
+	var err error
+	if l.gcPolicy == gcPolicyArchive {
+		if silence.Labels == nil {
+			silence.Labels = make(map[string]string)
+		}
+		silence.Labels[v2beta2.SilenceArchivedLabel] = "true"
+		err = l.notifierCtl.UpdateSilence(ctx, silence)
+	} else {
+		err = l.notifierCtl.DeleteSilence(ctx, silence)
+		if errors.IsNotFound(err) {
+			err = nil
+		}
+	}
+
+	if err != nil {
+		_ = level.Error(l.logger).Log("msg", "Silence: garbage collect failed", "silence", silence.Name, "policy", l.gcPolicy, "error", err.Error())
+		return
+	}
+
+	_ = level.Info(l.logger).Log("msg", "Silence: garbage collected", "silence", silence.Name, "policy", l.gcPolicy, "expiresAt", expiresAt)
+}
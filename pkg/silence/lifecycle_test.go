@@ -0,0 +1,306 @@
+package silence
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/internal/webhook"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeController struct {
+	silences []v2beta2.Silence
+	// The tenant of each silence.
+	tenants   map[string]string
+	receivers map[string][]internal.Receiver
+	updateErr error
+	updated   map[string]*v2beta2.Silence
+	deleted   []string
+}
+
+func (c *fakeController) ListSilences() []v2beta2.Silence {
+	var ss []v2beta2.Silence
+	for _, s := range c.silences {
+		ss = append(ss, *s.DeepCopy())
+	}
+	return ss
+}
+
+func (c *fakeController) SilenceTenants() map[string]string {
+	return c.tenants
+}
+
+func (c *fakeController) RcvsFromTenant(channels []v2beta2.Channel) []internal.Receiver {
+	var rcvs []internal.Receiver
+	for _, channel := range channels {
+		rcvs = append(rcvs, c.receivers[channel.Tenant]...)
+	}
+	return rcvs
+}
+
+func (c *fakeController) UpdateSilence(_ context.Context, silence *v2beta2.Silence) error {
+	if c.updateErr != nil {
+		return c.updateErr
+	}
+	if c.updated == nil {
+		c.updated = make(map[string]*v2beta2.Silence)
+	}
+	c.updated[silence.Name] = silence.DeepCopy()
+	for i := range c.silences {
+		if c.silences[i].Name == silence.Name {
+			c.silences[i] = *silence.DeepCopy()
+		}
+	}
+	return nil
+}
+
+func (c *fakeController) DeleteSilence(_ context.Context, silence *v2beta2.Silence) error {
+	c.deleted = append(c.deleted, silence.Name)
+	return nil
+}
+
+func newSilence(name, createdBy string, endsAt time.Time) v2beta2.Silence {
+	t := metav1.NewTime(endsAt)
+	return v2beta2.Silence{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v2beta2.SilenceSpec{
+			Matcher:   &v2beta2.LabelSelector{MatchLabels: map[string]string{"namespace": "test"}},
+			EndsAt:    &t,
+			CreatedBy: createdBy,
+			Comment:   "maintenance",
+		},
+	}
+}
+
+func newLifecycle(ctl *fakeController, sent *[]map[internal.Receiver][]*template.Data) *Lifecycle {
+	return &Lifecycle{
+		notifierCtl: ctl,
+		logger:      log.NewNopLogger(),
+		send: func(data map[internal.Receiver][]*template.Data, _ func()) {
+			*sent = append(*sent, data)
+		},
+		interval:     time.Minute,
+		expiryNotice: 15 * time.Minute,
+		retention:    time.Hour,
+		gcPolicy:     gcPolicyDelete,
+	}
+}
+
+func TestNotifyExpiry(t *testing.T) {
+	now := time.Date(2023, 6, 18, 6, 0, 0, 0, time.UTC)
+	rcv := &webhook.Receiver{Common: &internal.Common{Name: "admin-webhook", TenantID: "admin"}}
+	ctl := &fakeController{
+		silences: []v2beta2.Silence{
+			newSilence("expiring", "admin", now.Add(10*time.Minute)),
+			newSilence("later", "admin", now.Add(time.Hour)),
+			newSilence("anonymous", "", now.Add(10*time.Minute)),
+			newSilence("no-receivers", "user", now.Add(10*time.Minute)),
+			// The silence of the tenant user is not notified to the tenant admin in createdBy.
+			newSilence("spoofed", "admin", now.Add(10*time.Minute)),
+			// The silence which belongs to no tenant is not notified.
+			newSilence("unknown", "admin", now.Add(10*time.Minute)),
+		},
+		tenants: map[string]string{
+			"expiring":     "admin",
+			"later":        "admin",
+			"anonymous":    "",
+			"no-receivers": "user",
+			"spoofed":      "user",
+		},
+		receivers: map[string][]internal.Receiver{"admin": {rcv}},
+	}
+	var sent []map[internal.Receiver][]*template.Data
+	l := newLifecycle(ctl, &sent)
+
+	l.check(now)
+	if len(sent) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(sent))
+	}
+	ds := sent[0][rcv]
+	if len(ds) != 1 || len(ds[0].Alerts) != 1 {
+		t.Fatalf("expected 1 alert sent to the receiver of the author, got %v", sent[0])
+	}
+	alert := ds[0].Alerts[0]
+	if alert.Labels["alertname"] != silenceExpiring || alert.Labels["silence"] != "expiring" || alert.Annotations["comment"] != "maintenance" {
+		t.Fatalf("unexpected alert %v", alert)
+	}
+
+	expiresAt := now.Add(10 * time.Minute).Format(time.RFC3339)
+	if s := ctl.updated["expiring"]; s == nil || s.Annotations[expiryNotifiedAnnotation] != expiresAt {
+		t.Fatalf("expected the expiry notification to be recorded, got %v", s)
+	}
+	if len(ctl.updated) != 1 {
+		t.Fatalf("expected only the silence expiring to be updated, got %v", ctl.updated)
+	}
+
+	// The author is notified only once.
+	l.check(now.Add(time.Minute))
+	if len(sent) != 1 {
+		t.Fatalf("expected the author not to be notified again, got %d notifications", len(sent))
+	}
+
+	// The author is notified again after the silence is extended.
+	endsAt := metav1.NewTime(now.Add(20 * time.Minute))
+	ctl.silences[0].Spec.EndsAt = &endsAt
+	l.check(now.Add(10 * time.Minute))
+	if len(sent) != 2 {
+		t.Fatalf("expected the author to be notified after the silence was extended, got %d notifications", len(sent))
+	}
+}
+
+func TestNotifyExpiryDisabled(t *testing.T) {
+	now := time.Date(2023, 6, 18, 6, 0, 0, 0, time.UTC)
+	rcv := &webhook.Receiver{Common: &internal.Common{Name: "admin-webhook", TenantID: "admin"}}
+	disabled := false
+	s := newSilence("disabled", "admin", now.Add(10*time.Minute))
+	s.Spec.Enabled = &disabled
+	ctl := &fakeController{
+		silences:  []v2beta2.Silence{s},
+		tenants:   map[string]string{"disabled": "admin"},
+		receivers: map[string][]internal.Receiver{"admin": {rcv}},
+	}
+	var sent []map[internal.Receiver][]*template.Data
+	l := newLifecycle(ctl, &sent)
+
+	l.check(now)
+	if len(sent) != 0 {
+		t.Fatal("expected the author of the disabled silence not to be notified")
+	}
+
+	// The author is not notified if the notification can not be recorded.
+	ctl.silences[0].Spec.Enabled = nil
+	ctl.updateErr = fmt.Errorf("conflict")
+	l.check(now)
+	if len(sent) != 0 {
+		t.Fatal("expected the author not to be notified when the silence can not be updated")
+	}
+}
+
+func TestGC(t *testing.T) {
+	now := time.Date(2023, 6, 18, 6, 0, 0, 0, time.UTC)
+	archived := newSilence("archived", "admin", now.Add(-2*time.Hour))
+	archived.Labels = map[string]string{v2beta2.SilenceArchivedLabel: "true"}
+	forever := newSilence("forever", "admin", now)
+	forever.Spec.EndsAt = nil
+
+	newController := func() *fakeController {
+		return &fakeController{
+			silences: []v2beta2.Silence{
+				newSilence("expired", "admin", now.Add(-2*time.Hour)),
+				newSilence("recent", "admin", now.Add(-30*time.Minute)),
+				archived,
+				forever,
+			},
+		}
+	}
+
+	var sent []map[internal.Receiver][]*template.Data
+
+	ctl := newController()
+	l := newLifecycle(ctl, &sent)
+	l.check(now)
+	if len(ctl.deleted) != 1 || ctl.deleted[0] != "expired" || len(ctl.updated) != 0 {
+		t.Fatalf("expected only the silence expired to be deleted, deleted %v, updated %d", ctl.deleted, len(ctl.updated))
+	}
+
+	ctl = newController()
+	l = newLifecycle(ctl, &sent)
+	l.gcPolicy = gcPolicyArchive
+	l.check(now)
+	if len(ctl.deleted) != 0 || len(ctl.updated) != 1 || ctl.updated["expired"].Labels[v2beta2.SilenceArchivedLabel] != "true" {
+		t.Fatalf("expected only the silence expired to be archived, deleted %v, updated %v", ctl.deleted, ctl.updated)
+	}
+
+	// The archived silence is not garbage collected again.
+	ctl.updated = nil
+	l.check(now.Add(time.Hour))
+	if len(ctl.updated) != 1 || ctl.updated["recent"] == nil {
+		t.Fatalf("expected only the silence recent to be archived, updated %v", ctl.updated)
+	}
+
+	ctl = newController()
+	l = newLifecycle(ctl, &sent)
+	l.retention = 0
+	l.check(now)
+	if len(ctl.deleted) != 0 || len(ctl.updated) != 0 {
+		t.Fatal("expected the expired silences to be kept forever when the retention is 0")
+	}
+
+	if len(sent) != 0 {
+		t.Fatalf("expected no expiry notifications for the expired silences, got %d", len(sent))
+	}
+}
+
+func TestNotifyExpiryGlobal(t *testing.T) {
+	now := time.Date(2023, 6, 18, 6, 0, 0, 0, time.UTC)
+	rcv := &webhook.Receiver{Common: &internal.Common{Name: "admin-webhook", TenantID: "admin"}}
+	ctl := &fakeController{
+		silences:  []v2beta2.Silence{newSilence("global", "admin", now.Add(10*time.Minute))},
+		tenants:   map[string]string{"global": ""},
+		receivers: map[string][]internal.Receiver{"admin": {rcv}},
+	}
+	var sent []map[internal.Receiver][]*template.Data
+	l := newLifecycle(ctl, &sent)
+
+	// The global silence is notified to the tenant in createdBy.
+	l.check(now)
+	if len(sent) != 1 || len(sent[0][rcv]) != 1 {
+		t.Fatalf("expected the author of the global silence to be notified, got %v", sent)
+	}
+}
+
+func TestGCDisabled(t *testing.T) {
+	now := time.Date(2023, 6, 18, 6, 0, 0, 0, time.UTC)
+	disabled := false
+	s := newSilence("disabled", "admin", now)
+	s.Spec.EndsAt = nil
+	s.Spec.Enabled = &disabled
+	ctl := &fakeController{silences: []v2beta2.Silence{s}}
+	var sent []map[internal.Receiver][]*template.Data
+	l := newLifecycle(ctl, &sent)
+
+	// The time the silence was found disabled is recorded.
+	l.check(now)
+	if u := ctl.updated["disabled"]; u == nil || u.Annotations[disabledAtAnnotation] != now.Format(time.RFC3339) {
+		t.Fatalf("expected the disabled time to be recorded, got %v", u)
+	}
+
+	l.check(now.Add(30 * time.Minute))
+	if len(ctl.deleted) != 0 {
+		t.Fatal("expected the disabled silence to be kept within the retention")
+	}
+
+	// The record is removed once the silence is enabled again.
+	ctl.silences[0].Spec.Enabled = nil
+	l.check(now.Add(30 * time.Minute))
+	if _, ok := ctl.updated["disabled"].Annotations[disabledAtAnnotation]; ok {
+		t.Fatal("expected the disabled time to be removed after the silence was enabled")
+	}
+
+	// The silence is garbage collected after it has been disabled for the retention.
+	ctl.silences[0].Spec.Enabled = &disabled
+	l.check(now.Add(40 * time.Minute))
+	l.check(now.Add(90 * time.Minute))
+	if len(ctl.deleted) != 0 {
+		t.Fatal("expected the retention to start again after the silence was disabled again")
+	}
+	l.check(now.Add(100 * time.Minute))
+	if len(ctl.deleted) != 1 || ctl.deleted[0] != "disabled" {
+		t.Fatalf("expected the disabled silence to be garbage collected, deleted %v", ctl.deleted)
+	}
+
+	// The disabled silences are kept forever when the retention is 0.
+	ctl = &fakeController{silences: []v2beta2.Silence{s}}
+	l = newLifecycle(ctl, &sent)
+	l.retention = 0
+	l.check(now.Add(time.Hour))
+	if len(ctl.deleted) != 0 || len(ctl.updated) != 0 {
+		t.Fatal("expected the disabled silence to be kept forever when the retention is 0")
+	}
+}
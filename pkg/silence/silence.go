@@ -5,19 +5,24 @@ import (
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
 	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/matcher"
 	"github.com/kubesphere/notification-manager/pkg/stage"
+	"github.com/kubesphere/notification-manager/pkg/status"
 	"github.com/kubesphere/notification-manager/pkg/template"
 	"github.com/modern-go/reflect2"
 )
 
 type silenceStage struct {
 	notifierCtl *controller.Controller
+	recorder    *status.Recorder
 }
 
-func NewStage(notifierCtl *controller.Controller) stage.Stage {
+func NewStage(notifierCtl *controller.Controller, recorder *status.Recorder) stage.Stage {
 	return &silenceStage{
 		notifierCtl,
+		recorder,
 	}
 }
 
@@ -40,16 +45,29 @@ func (s *silenceStage) Exec(ctx context.Context, l log.Logger, data interface{})
 		return ctx, input, nil
 	}
 
+	var silences []v2beta2.Silence
+	var matchers []*v2beta2.Matcher
+	for _, silence := range ss {
+		// The silence applied to specific receivers takes effect in the filter stage.
+		if silence.Spec.Receivers != nil {
+			continue
+		}
+
+		m, err := matcher.Get(matcher.SilenceKey(silence.Name), matcher.Version(&silence), silence.Spec.Matcher)
+		if err != nil {
+			return nil, nil, err
+		}
+		silences = append(silences, silence)
+		matchers = append(matchers, m)
+	}
+
 	var output []*template.Alert
 	for _, alert := range input {
 		mute := false
-		for _, silence := range ss {
-			ok, err := silence.Spec.Matcher.Matches(alert.Labels)
-			if err != nil {
-				return nil, nil, err
-			}
-			if ok {
+		for i, m := range matchers {
+			if m.Matches(alert.Labels) {
 				mute = true
+				s.recorder.SilenceSuppressed(silences[i].Name)
 				break
 			}
 		}
@@ -0,0 +1,428 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/controller"
+	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	interval *time.Duration
+)
+
+func init() {
+	interval = kingpin.Flag(
+		"status.interval",
+		"Interval to write the status of the notification manager, routers, receivers, silences and configs, 0 means never write the status",
+	).Default("1m").Duration()
+}
+
+type routerDelta struct {
+	matched   int64
+	lastMatch time.Time
+}
+
+type receiverDelta struct {
+	sent        int64
+	failed      int64
+	lastSuccess time.Time
+	lastErrTime time.Time
+	lastErr     string
+}
+
+type silenceDelta struct {
+	suppressed     int64
+	lastSuppressed time.Time
+}
+
+// Recorder records what happened to the routers, receivers and silences in memory,
+// and writes them to the status subresources periodically.
+// The counters are accumulated to the status, so that they survive restarts and can be shared between the replicas.
+// The resources are read from the cache of the controller, and the status is only written when it changes.
+type Recorder struct {
+	mutex     sync.Mutex
+	reader    client.Reader
+	client    client.Client
+	bindings  func() map[string][]string
+	logger    log.Logger
+	routers   map[string]*routerDelta
+	receivers map[string]*receiverDelta
+	silences  map[string]*silenceDelta
+	// The notifications sent to all receivers, including the receivers which are not defined by the Receiver CRDs.
+	total *receiverDelta
+}
+
+func NewRecorder(ctx context.Context, logger log.Logger, notifierCtl *controller.Controller) *Recorder {
+
+	r := newRecorder(notifierCtl.Reader(), notifierCtl.Client(), notifierCtl.ConfigBindings, logger)
+	if *interval > 0 {
+		go r.run(ctx)
+	}
+
+	return r
+}
+
+func newRecorder(reader client.Reader, c client.Client, bindings func() map[string][]string, logger log.Logger) *Recorder {
+	return &Recorder{
+		reader:    reader,
+		client:    c,
+		bindings:  bindings,
+		logger:    logger,
+		routers:   make(map[string]*routerDelta),
+		receivers: make(map[string]*receiverDelta),
+		silences:  make(map[string]*silenceDelta),
+		total:     &receiverDelta{},
+	}
+}
+
+// RouterMatched records that an alert matched the router.
+func (r *Recorder) RouterMatched(name string) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	d := r.routers[name]
+	if d == nil {
+		d = &routerDelta{}
+		r.routers[name] = d
+	}
+	d.matched++
+	d.lastMatch = time.Now()
+}
+
+// ReceiverNotified records the result of a notification sent to the receiver,
+// the name is empty if the receiver is not defined by a Receiver CRD.
+func (r *Recorder) ReceiverNotified(name string, err error) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	r.total.record(now, err)
+	if name == "" {
+		return
+	}
+
+	d := r.receivers[name]
+	if d == nil {
+		d = &receiverDelta{}
+		r.receivers[name] = d
+	}
+	d.record(now, err)
+}
+
+func (d *receiverDelta) record(now time.Time, err error) {
+	if err == nil {
+		d.sent++
+		d.lastSuccess = now
+	} else {
+		d.failed++
+		d.lastErrTime = now
+		d.lastErr = err.Error()
+	}
+}
+
+// SilenceSuppressed records that an alert was suppressed by the silence.
+func (r *Recorder) SilenceSuppressed(name string) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	d := r.silences[name]
+	if d == nil {
+		d = &silenceDelta{}
+		r.silences[name] = d
+	}
+	d.suppressed++
+	d.lastSuppressed = time.Now()
+}
+
+func (r *Recorder) run(ctx context.Context) {
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.flush(ctx)
+		}
+	}
+}
+
+// flush writes the recorded deltas to the status subresources.
+// The deltas which failed to be written are kept and will be written next time.
+func (r *Recorder) flush(ctx context.Context) {
+
+	r.mutex.Lock()
+	routers, receivers, silences, total := r.routers, r.receivers, r.silences, r.total
+	r.routers = make(map[string]*routerDelta)
+	r.receivers = make(map[string]*receiverDelta)
+	r.silences = make(map[string]*silenceDelta)
+	r.total = &receiverDelta{}
+	r.mutex.Unlock()
+
+	for name, d := range routers {
+		if err := r.flushRouter(ctx, name, d); err != nil {
+			_ = level.Warn(r.logger).Log("msg", "Failed to write router status", "router", name, "error", err.Error())
+			r.mutex.Lock()
+			r.routers[name] = mergeRouterDelta(r.routers[name], d)
+			r.mutex.Unlock()
+		}
+	}
+
+	for name, d := range receivers {
+		if err := r.flushReceiver(ctx, name, d); err != nil {
+			_ = level.Warn(r.logger).Log("msg", "Failed to write receiver status", "receiver", name, "error", err.Error())
+			r.mutex.Lock()
+			r.receivers[name] = mergeReceiverDelta(r.receivers[name], d)
+			r.mutex.Unlock()
+		}
+	}
+
+	failed, active := r.flushSilences(ctx, silences)
+	r.mutex.Lock()
+	for name, d := range failed {
+		r.silences[name] = mergeSilenceDelta(r.silences[name], d)
+	}
+	r.mutex.Unlock()
+
+	r.flushConfigs(ctx)
+
+	if err := r.flushNotificationManagers(ctx, total, active); err != nil {
+		_ = level.Warn(r.logger).Log("msg", "Failed to write notification manager status", "error", err.Error())
+		r.mutex.Lock()
+		r.total = mergeReceiverDelta(r.total, total)
+		r.mutex.Unlock()
+	}
+}
+
+func (r *Recorder) flushRouter(ctx context.Context, name string, d *routerDelta) error {
+
+	router := &v2beta2.Router{}
+	if err := r.reader.Get(ctx, types.NamespacedName{Name: name}, router); err != nil {
+		return ignoreNotFound(err)
+	}
+
+	status := router.Status.DeepCopy()
+	status.MatchedAlerts += d.matched
+	status.LastMatchTime = &metav1.Time{Time: d.lastMatch}
+
+	return ignoreNotFound(r.patch(ctx, router, status))
+}
+
+func (r *Recorder) flushReceiver(ctx context.Context, name string, d *receiverDelta) error {
+
+	receiver := &v2beta2.Receiver{}
+	if err := r.reader.Get(ctx, types.NamespacedName{Name: name}, receiver); err != nil {
+		return ignoreNotFound(err)
+	}
+
+	status := receiver.Status.DeepCopy()
+	status.SentNotifications += d.sent
+	status.FailedNotifications += d.failed
+	if !d.lastSuccess.IsZero() {
+		status.LastSuccessTime = &metav1.Time{Time: d.lastSuccess}
+	}
+	if !d.lastErrTime.IsZero() {
+		status.LastErrorTime = &metav1.Time{Time: d.lastErrTime}
+		status.LastError = d.lastErr
+	}
+
+	return ignoreNotFound(r.patch(ctx, receiver, status))
+}
+
+// flushSilences updates the active state of all silences, and accumulates the suppressed alerts.
+// It returns the deltas which failed to be written, and the number of the active silences,
+// which is -1 if the silences failed to be listed.
+func (r *Recorder) flushSilences(ctx context.Context, deltas map[string]*silenceDelta) (map[string]*silenceDelta, int32) {
+
+	list := &v2beta2.SilenceList{}
+	if err := r.reader.List(ctx, list); err != nil {
+		_ = level.Warn(r.logger).Log("msg", "Failed to list silences", "error", err.Error())
+		return deltas, -1
+	}
+
+	failed := make(map[string]*silenceDelta)
+	var active int32
+	for i := range list.Items {
+		silence := &list.Items[i]
+		status := silence.Status.DeepCopy()
+		status.Active = silence.IsActive()
+		if status.Active {
+			active++
+		}
+		d := deltas[silence.Name]
+		if d != nil {
+			status.SuppressedAlerts += d.suppressed
+			status.LastSuppressedTime = &metav1.Time{Time: d.lastSuppressed}
+		}
+
+		if reflect.DeepEqual(status, &silence.Status) {
+			continue
+		}
+
+		if err := ignoreNotFound(r.patch(ctx, silence, status)); err != nil {
+			_ = level.Warn(r.logger).Log("msg", "Failed to write silence status", "silence", silence.Name, "error", err.Error())
+			if d != nil {
+				failed[silence.Name] = d
+			}
+		}
+	}
+
+	return failed, active
+}
+
+// flushConfigs updates the receivers bound to each config.
+func (r *Recorder) flushConfigs(ctx context.Context) {
+
+	list := &v2beta2.ConfigList{}
+	if err := r.reader.List(ctx, list); err != nil {
+		_ = level.Warn(r.logger).Log("msg", "Failed to list configs", "error", err.Error())
+		return
+	}
+
+	bindings := r.bindings()
+	for i := range list.Items {
+		config := &list.Items[i]
+		receivers := bindings[config.Name]
+		sort.Strings(receivers)
+		if reflect.DeepEqual(receivers, config.Status.Receivers) {
+			continue
+		}
+
+		// The receivers are always included in the patch, so that they will be removed if no receiver is bound.
+		status := map[string]interface{}{"receivers": receivers}
+		if err := ignoreNotFound(r.patch(ctx, config, status)); err != nil {
+			_ = level.Warn(r.logger).Log("msg", "Failed to write config status", "config", config.Name, "error", err.Error())
+		}
+	}
+}
+
+// flushNotificationManagers accumulates the notifications sent to all receivers, and updates the number of the active
+// silences, the active silences are not updated if they are unknown.
+func (r *Recorder) flushNotificationManagers(ctx context.Context, d *receiverDelta, active int32) error {
+
+	list := &v2beta2.NotificationManagerList{}
+	if err := r.reader.List(ctx, list); err != nil {
+		return err
+	}
+
+	var res error
+	for i := range list.Items {
+		nm := &list.Items[i]
+		status := nm.Status.DeepCopy()
+		status.SentNotifications += d.sent
+		status.FailedNotifications += d.failed
+		if !d.lastSuccess.IsZero() {
+			status.LastSuccessTime = &metav1.Time{Time: d.lastSuccess}
+		}
+		if !d.lastErrTime.IsZero() {
+			status.LastErrorTime = &metav1.Time{Time: d.lastErrTime}
+			status.LastError = d.lastErr
+		}
+		if active >= 0 {
+			status.ActiveSilences = active
+		}
+
+		if reflect.DeepEqual(status, &nm.Status) {
+			continue
+		}
+
+		if err := ignoreNotFound(r.patch(ctx, nm, status)); err != nil {
+			res = err
+		}
+	}
+
+	return res
+}
+
+// patch merge-patches the status subresource of the object.
+// The resourceVersion is included in the patch, so that the patch fails if the object has been changed by others.
+func (r *Recorder) patch(ctx context.Context, obj client.Object, status interface{}) error {
+
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": obj.GetResourceVersion(),
+		},
+		"status": status,
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.client.Status().Patch(ctx, obj, client.RawPatch(types.MergePatchType, data))
+}
+
+func ignoreNotFound(err error) error {
+	if errors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+func mergeRouterDelta(d, other *routerDelta) *routerDelta {
+	if d == nil {
+		return other
+	}
+
+	d.matched += other.matched
+	if other.lastMatch.After(d.lastMatch) {
+		d.lastMatch = other.lastMatch
+	}
+	return d
+}
+
+func mergeReceiverDelta(d, other *receiverDelta) *receiverDelta {
+	if d == nil {
+		return other
+	}
+
+	d.sent += other.sent
+	d.failed += other.failed
+	if other.lastSuccess.After(d.lastSuccess) {
+		d.lastSuccess = other.lastSuccess
+	}
+	if other.lastErrTime.After(d.lastErrTime) {
+		d.lastErrTime = other.lastErrTime
+		d.lastErr = other.lastErr
+	}
+	return d
+}
+
+func mergeSilenceDelta(d, other *silenceDelta) *silenceDelta {
+	if d == nil {
+		return other
+	}
+
+	d.suppressed += other.suppressed
+	if other.lastSuppressed.After(d.lastSuppressed) {
+		d.lastSuppressed = other.lastSuppressed
+	}
+	return d
+}
@@ -0,0 +1,201 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newTestRecorder(t *testing.T, funcs interceptor.Funcs, objs ...client.Object) (*Recorder, client.Client) {
+	scheme := runtime.NewScheme()
+	if err := v2beta2.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&v2beta2.NotificationManager{}, &v2beta2.Receiver{}, &v2beta2.Router{}, &v2beta2.Silence{}, &v2beta2.Config{}).
+		WithInterceptorFuncs(funcs).
+		Build()
+	bindings := func() map[string][]string {
+		return map[string][]string{"config": {"receiver"}}
+	}
+
+	return newRecorder(c, c, bindings, log.NewNopLogger()), c
+}
+
+func testObjects() []client.Object {
+	return []client.Object{
+		&v2beta2.NotificationManager{ObjectMeta: metav1.ObjectMeta{Name: "notification-manager"}},
+		&v2beta2.Receiver{
+			ObjectMeta: metav1.ObjectMeta{Name: "receiver"},
+			Status:     v2beta2.ReceiverStatus{SentNotifications: 1},
+		},
+		&v2beta2.Router{ObjectMeta: metav1.ObjectMeta{Name: "router"}},
+		&v2beta2.Silence{ObjectMeta: metav1.ObjectMeta{Name: "silence"}},
+		&v2beta2.Config{ObjectMeta: metav1.ObjectMeta{Name: "config"}},
+	}
+}
+
+func TestFlush(t *testing.T) {
+	r, c := newTestRecorder(t, interceptor.Funcs{}, testObjects()...)
+	ctx := context.Background()
+
+	r.RouterMatched("router")
+	r.ReceiverNotified("receiver", nil)
+	r.ReceiverNotified("receiver", nil)
+	r.ReceiverNotified("receiver", errors.New("timeout"))
+	// The receivers which are not defined by the Receiver CRDs are only counted by the notification manager.
+	r.ReceiverNotified("", nil)
+	r.SilenceSuppressed("silence")
+	r.flush(ctx)
+
+	receiver := &v2beta2.Receiver{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "receiver"}, receiver); err != nil {
+		t.Fatal(err)
+	}
+	if receiver.Status.SentNotifications != 3 || receiver.Status.FailedNotifications != 1 ||
+		receiver.Status.LastError != "timeout" || receiver.Status.LastSuccessTime == nil {
+		t.Fatalf("unexpected receiver status %+v", receiver.Status)
+	}
+
+	router := &v2beta2.Router{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "router"}, router); err != nil {
+		t.Fatal(err)
+	}
+	if router.Status.MatchedAlerts != 1 || router.Status.LastMatchTime == nil {
+		t.Fatalf("unexpected router status %+v", router.Status)
+	}
+
+	silence := &v2beta2.Silence{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "silence"}, silence); err != nil {
+		t.Fatal(err)
+	}
+	if !silence.Status.Active || silence.Status.SuppressedAlerts != 1 {
+		t.Fatalf("unexpected silence status %+v", silence.Status)
+	}
+
+	config := &v2beta2.Config{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "config"}, config); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Status.Receivers) != 1 || config.Status.Receivers[0] != "receiver" {
+		t.Fatalf("unexpected config status %+v", config.Status)
+	}
+
+	nm := &v2beta2.NotificationManager{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "notification-manager"}, nm); err != nil {
+		t.Fatal(err)
+	}
+	if nm.Status.SentNotifications != 3 || nm.Status.FailedNotifications != 1 ||
+		nm.Status.LastError != "timeout" || nm.Status.ActiveSilences != 1 {
+		t.Fatalf("unexpected notification manager status %+v", nm.Status)
+	}
+
+	// The counters are accumulated to the status.
+	r.ReceiverNotified("receiver", nil)
+	r.flush(ctx)
+	if err := c.Get(ctx, types.NamespacedName{Name: "notification-manager"}, nm); err != nil {
+		t.Fatal(err)
+	}
+	if nm.Status.SentNotifications != 4 {
+		t.Fatalf("expected the sent notifications to be accumulated, got %d", nm.Status.SentNotifications)
+	}
+}
+
+func TestFlushUnchanged(t *testing.T) {
+	patches := 0
+	funcs := interceptor.Funcs{
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			patches++
+			return c.SubResource(subResource).Patch(ctx, obj, patch, opts...)
+		},
+	}
+	r, _ := newTestRecorder(t, funcs, testObjects()...)
+
+	// The silence, the config and the notification manager are written for the first time.
+	r.flush(context.Background())
+	if patches != 3 {
+		t.Fatalf("expected 3 patches, got %d", patches)
+	}
+
+	// Nothing is written if the status does not change, so that the resourceVersions are not changed.
+	patches = 0
+	r.flush(context.Background())
+	if patches != 0 {
+		t.Fatalf("expected no patch, got %d", patches)
+	}
+}
+
+func TestFlushFailed(t *testing.T) {
+	failed := true
+	funcs := interceptor.Funcs{
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			if failed {
+				return errors.New("conflict")
+			}
+			return c.SubResource(subResource).Patch(ctx, obj, patch, opts...)
+		},
+	}
+	r, c := newTestRecorder(t, funcs, testObjects()...)
+	ctx := context.Background()
+
+	r.ReceiverNotified("receiver", nil)
+	r.RouterMatched("router")
+	r.SilenceSuppressed("silence")
+	r.flush(ctx)
+
+	// The deltas which failed to be written are kept.
+	if r.receivers["receiver"] == nil || r.routers["router"] == nil || r.silences["silence"] == nil || r.total.sent != 1 {
+		t.Fatal("expected the deltas to be kept")
+	}
+
+	// The deltas are written next time.
+	failed = false
+	r.ReceiverNotified("receiver", nil)
+	r.flush(ctx)
+
+	receiver := &v2beta2.Receiver{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "receiver"}, receiver); err != nil {
+		t.Fatal(err)
+	}
+	if receiver.Status.SentNotifications != 3 {
+		t.Fatalf("expected 3 sent notifications, got %d", receiver.Status.SentNotifications)
+	}
+
+	nm := &v2beta2.NotificationManager{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "notification-manager"}, nm); err != nil {
+		t.Fatal(err)
+	}
+	if nm.Status.SentNotifications != 2 {
+		t.Fatalf("expected 2 sent notifications, got %d", nm.Status.SentNotifications)
+	}
+	if len(r.receivers) != 0 || len(r.routers) != 0 || len(r.silences) != 0 || r.total.sent != 0 {
+		t.Fatal("expected the deltas to be cleared after written")
+	}
+}
+
+func TestMergeReceiverDelta(t *testing.T) {
+	now := time.Now()
+	d := &receiverDelta{sent: 1, lastSuccess: now.Add(-time.Minute)}
+	other := &receiverDelta{sent: 1, failed: 1, lastSuccess: now, lastErrTime: now, lastErr: "timeout"}
+
+	d = mergeReceiverDelta(d, other)
+	if d.sent != 2 || d.failed != 1 || !d.lastSuccess.Equal(now) || d.lastErr != "timeout" {
+		t.Fatalf("unexpected merged delta %+v", d)
+	}
+	if mergeReceiverDelta(nil, other) != other {
+		t.Fatal("expected the other delta to be returned")
+	}
+}
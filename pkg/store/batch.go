@@ -0,0 +1,61 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+const batchKey = "batch"
+
+// Batch tracks the alerts pulled from the store in a batch. The stages which keep the alerts after the batch finished,
+// such as the aggregation groups and the escalations, hold the batch until they send the alerts,
+// and the alerts will be acknowledged after the batch finished and all the holds are released.
+type Batch struct {
+	refs int32
+	ack  func()
+}
+
+// NewBatch returns a batch held by the caller, the ack function is called once all the holds are released.
+func NewBatch(ack func()) *Batch {
+	return &Batch{
+		refs: 1,
+		ack:  ack,
+	}
+}
+
+// Hold holds the batch, and returns the function to release the hold, the function can be called more than once.
+func (b *Batch) Hold() func() {
+	atomic.AddInt32(&b.refs, 1)
+
+	var once sync.Once
+	return func() {
+		once.Do(b.release)
+	}
+}
+
+// Release releases the hold of the creator of the batch.
+func (b *Batch) Release() {
+	b.release()
+}
+
+func (b *Batch) release() {
+	if atomic.AddInt32(&b.refs, -1) == 0 {
+		b.ack()
+	}
+}
+
+// WithBatch returns a copy of the context which carries the batch.
+func WithBatch(ctx context.Context, b *Batch) context.Context {
+	return context.WithValue(ctx, batchKey, b)
+}
+
+// Hold holds the batch carried by the context, and returns the function to release the hold.
+// A no-op function is returned if the context carries no batch.
+func Hold(ctx context.Context) func() {
+	if b, ok := ctx.Value(batchKey).(*Batch); ok && b != nil {
+		return b.Hold()
+	}
+
+	return func() {}
+}
@@ -0,0 +1,33 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	acked := 0
+	b := NewBatch(func() {
+		acked++
+	})
+
+	ctx := WithBatch(context.Background(), b)
+	r1 := Hold(ctx)
+	r2 := Hold(ctx)
+
+	b.Release()
+	r1()
+	// Releasing a hold twice must not release the other holds.
+	r1()
+	if acked != 0 {
+		t.Fatal("the batch should not be acknowledged before all holds are released")
+	}
+
+	r2()
+	if acked != 1 {
+		t.Fatalf("expected the batch to be acknowledged once, got %d", acked)
+	}
+
+	// A context without batch returns a no-op release.
+	Hold(context.Background())()
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kubesphere/notification-manager/pkg/store/provider"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	pushSuccess = "success"
+	pushTimeout = "timeout"
+	pushClosed  = "closed"
+	pushError   = "error"
+	// The alert was dropped because it had been pushed and not acknowledged yet.
+	pushDuplicate = "duplicate"
+)
+
+var (
+	pushTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "notification_manager",
+		Subsystem: "store",
+		Name:      "push_total",
+		Help:      "The total number of alerts pushed to the store, partitioned by result.",
+	}, []string{"result"})
+
+	pushDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "notification_manager",
+		Subsystem: "store",
+		Name:      "push_duration_seconds",
+		Help:      "The time taken to push an alert to the store.",
+		Buckets:   []float64{.001, .005, .01, .05, .1, .5, 1, 2, 5},
+	})
+
+	// The provider of the latest store, whose queue depth and capacity are exposed.
+	// It holds a queueHolder, because the providers of the stores may have different types.
+	queueProvider     atomic.Value
+	registerQueueOnce sync.Once
+)
+
+func init() {
+	prometheus.MustRegister(pushTotal, pushDuration)
+}
+
+type queueHolder struct {
+	provider.Provider
+}
+
+// queueMetric returns the value of the provider of the latest store, or 0 if there is no store.
+func queueMetric(f func(p provider.Provider) int) func() float64 {
+	return func() float64 {
+		h, ok := queueProvider.Load().(queueHolder)
+		if !ok {
+			return 0
+		}
+		return float64(f(h.Provider))
+	}
+}
+
+// registerQueueMetrics exposes the queue depth and capacity of the store, the metrics are registered only once,
+// and follow the latest store created.
+func registerQueueMetrics(p provider.Provider) {
+	queueProvider.Store(queueHolder{p})
+	registerQueueOnce.Do(func() {
+		prometheus.MustRegister(
+			prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace: "notification_manager",
+				Subsystem: "store",
+				Name:      "queue_depth",
+				Help:      "The number of alerts waiting to be processed in the store.",
+			}, queueMetric(provider.Provider.Len)),
+			prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace: "notification_manager",
+				Subsystem: "store",
+				Name:      "queue_capacity",
+				Help:      "The maximum number of alerts which can wait to be processed in the store.",
+			}, queueMetric(provider.Provider.Cap)),
+		)
+	})
+}
+
+func pushResult(err error) string {
+	switch {
+	case err == nil:
+		return pushSuccess
+	case errors.Is(err, provider.ErrTimeout):
+		return pushTimeout
+	case errors.Is(err, provider.ErrClosed):
+		return pushClosed
+	default:
+		return pushError
+	}
+}
@@ -0,0 +1,416 @@
+package file
+
+import (
+	"context"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/store/provider"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const (
+	fsyncAlways   = "always"
+	fsyncInterval = "interval"
+	fsyncNever    = "never"
+)
+
+var (
+	dir         *string
+	segmentSize *int64
+	fsyncPolicy *string
+	fsyncPeriod *time.Duration
+	queueLen    *int
+	pushTimeout *time.Duration
+)
+
+// fileProvider is a provider which persists the alerts to a segmented write-ahead log on local disk.
+// An alert is written to the log before it is pushed to the queue, and it will be acknowledged after
+// the dispatcher finishes processing it. The alerts which have not been acknowledged will be replayed
+// when the provider restarts. A segment will be removed after all the alerts in it and in the segments
+// before it are acknowledged.
+type fileProvider struct {
+	mutex sync.Mutex
+
+	dir      string
+	segments []*segment
+	active   *os.File
+	size     int64
+	dirty    bool
+	seq      uint64
+	// The sequence and the segment of the alerts which have not been acknowledged.
+	inflight map[*template.Alert]uint64
+	owners   map[uint64]*segment
+
+	ch chan *template.Alert
+	// slots limits the number of alerts in the queue, a slot is taken before writing the log,
+	// so that sending to the queue will never block.
+	slots  chan struct{}
+	closed bool
+	stopCh chan struct{}
+}
+
+func init() {
+	dir = kingpin.Flag(
+		"store.file.dir",
+		"Directory where the write-ahead log of the file store is located",
+	).Default("/var/lib/notification-manager/wal").String()
+	segmentSize = kingpin.Flag(
+		"store.file.segmentSize",
+		"The maximum size of a write-ahead log segment, in bytes",
+	).Default("67108864").Int64()
+	fsyncPolicy = kingpin.Flag(
+		"store.file.fsync",
+		"When to fsync the write-ahead log. Possible values: always, interval, never",
+	).Default(fsyncInterval).Enum(fsyncAlways, fsyncInterval, fsyncNever)
+	fsyncPeriod = kingpin.Flag(
+		"store.file.fsyncInterval",
+		"Interval to fsync the write-ahead log when the fsync policy is interval",
+	).Default("1s").Duration()
+	queueLen = kingpin.Flag(
+		"store.file.queue",
+		"File store queue capacity",
+	).Default("10000").Int()
+	pushTimeout = kingpin.Flag(
+		"store.file.pushTimeout",
+		"Push timeout",
+	).Default("3s").Duration()
+}
+
+func NewProvider() (provider.Provider, error) {
+
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		return nil, err
+	}
+
+	p := &fileProvider{
+		dir:      *dir,
+		inflight: make(map[*template.Alert]uint64),
+		owners:   make(map[uint64]*segment),
+		ch:       make(chan *template.Alert, *queueLen),
+		slots:    make(chan struct{}, *queueLen),
+		stopCh:   make(chan struct{}),
+	}
+
+	pending, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var next uint64 = 1
+	if len(p.segments) > 0 {
+		next = p.segments[len(p.segments)-1].index + 1
+	}
+	if err := p.openSegment(next); err != nil {
+		return nil, err
+	}
+
+	if err := p.truncate(); err != nil {
+		return nil, err
+	}
+
+	go p.replay(pending)
+
+	if *fsyncPolicy == fsyncInterval {
+		go p.sync()
+	}
+
+	return p, nil
+}
+
+// load reads all segments, and returns the alerts which have not been acknowledged in the order they were pushed.
+func (p *fileProvider) load() ([]*template.Alert, error) {
+
+	indexes, err := listSegments(p.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make(map[uint64]*template.Alert)
+	for _, index := range indexes {
+		seg := &segment{index: index}
+		records, err := readSegment(segmentName(p.dir, index))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range records {
+			switch r.Type {
+			case recordPush:
+				if r.Alert == nil {
+					continue
+				}
+				alerts[r.Seq] = r.Alert
+				p.owners[r.Seq] = seg
+				seg.pending++
+				if r.Seq > p.seq {
+					p.seq = r.Seq
+				}
+			case recordAck:
+				for _, seq := range r.Acks {
+					if seq > p.seq {
+						p.seq = seq
+					}
+					if owner, ok := p.owners[seq]; ok {
+						owner.pending--
+						delete(p.owners, seq)
+						delete(alerts, seq)
+					}
+				}
+			}
+		}
+
+		p.segments = append(p.segments, seg)
+	}
+
+	seqs := make([]uint64, 0, len(alerts))
+	for seq := range alerts {
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool {
+		return seqs[i] < seqs[j]
+	})
+
+	var pending []*template.Alert
+	for _, seq := range seqs {
+		p.inflight[alerts[seq]] = seq
+		pending = append(pending, alerts[seq])
+	}
+
+	return pending, nil
+}
+
+// replay pushes the alerts which have not been acknowledged to the queue again.
+func (p *fileProvider) replay(alerts []*template.Alert) {
+	_ = p.Requeue(alerts)
+}
+
+func (p *fileProvider) sync() {
+	ticker := time.NewTicker(*fsyncPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopCh:
+			return
+		case <-ticker.C:
+			p.mutex.Lock()
+			if p.dirty {
+				_ = p.active.Sync()
+				p.dirty = false
+			}
+			p.mutex.Unlock()
+		}
+	}
+}
+
+func (p *fileProvider) openSegment(index uint64) error {
+	f, err := os.OpenFile(segmentName(p.dir, index), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	p.active = f
+	p.size = 0
+	p.segments = append(p.segments, &segment{index: index})
+	return nil
+}
+
+// write appends a record to the active segment, and cuts a new segment if the active one is full.
+// It must be called with the lock held.
+func (p *fileProvider) write(r *record) error {
+	bs, err := encodeRecord(r)
+	if err != nil {
+		return err
+	}
+
+	n, err := p.active.Write(bs)
+	if err != nil {
+		// Remove the torn record, otherwise the records appended after it will be discarded when replaying.
+		if n > 0 {
+			if err := p.active.Truncate(p.size); err != nil {
+				// The torn record can not be removed, write the records to a new segment.
+				_ = p.active.Close()
+				if err := p.openSegment(p.segments[len(p.segments)-1].index + 1); err != nil {
+					return err
+				}
+			}
+		}
+		return err
+	}
+	p.size += int64(n)
+
+	if *fsyncPolicy == fsyncAlways {
+		if err := p.active.Sync(); err != nil {
+			return err
+		}
+	} else {
+		p.dirty = true
+	}
+
+	if r.Type == recordPush {
+		seg := p.segments[len(p.segments)-1]
+		seg.pending++
+		p.owners[r.Seq] = seg
+	}
+
+	if p.size >= *segmentSize {
+		if err := p.active.Sync(); err != nil {
+			return err
+		}
+		p.dirty = false
+		if err := p.active.Close(); err != nil {
+			return err
+		}
+		return p.openSegment(p.segments[len(p.segments)-1].index + 1)
+	}
+
+	return nil
+}
+
+// truncate removes the oldest segments in which all alerts have been acknowledged.
+// The segments are removed in order, so the ack records of an alert will never be removed before the alert.
+// It must be called with the lock held.
+func (p *fileProvider) truncate() error {
+	for len(p.segments) > 1 && p.segments[0].pending <= 0 {
+		if err := os.Remove(segmentName(p.dir, p.segments[0].index)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		p.segments = p.segments[1:]
+	}
+
+	return nil
+}
+
+func (p *fileProvider) Push(alert *template.Alert) error {
+	ctx, cancel := context.WithTimeout(context.Background(), *pushTimeout)
+	defer cancel()
+
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return provider.ErrTimeout
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		<-p.slots
+		return provider.ErrClosed
+	}
+
+	p.seq = p.seq + 1
+	if err := p.write(&record{Type: recordPush, Seq: p.seq, Alert: alert}); err != nil {
+		<-p.slots
+		return err
+	}
+
+	p.inflight[alert] = p.seq
+	p.ch <- alert
+	return nil
+}
+
+func (p *fileProvider) Pull(batchSize int, batchWait time.Duration) ([]*template.Alert, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), batchWait)
+	defer cancel()
+
+	var as []*template.Alert
+	for {
+		select {
+		case <-ctx.Done():
+			return as, nil
+		case alert := <-p.ch:
+			if alert == nil {
+				return as, provider.ErrClosed
+			}
+			<-p.slots
+			as = append(as, alert)
+			if len(as) >= batchSize {
+				return as, nil
+			}
+		}
+	}
+}
+
+func (p *fileProvider) Ack(alerts []*template.Alert) error {
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var seqs []uint64
+	for _, alert := range alerts {
+		seq, ok := p.inflight[alert]
+		if !ok {
+			continue
+		}
+
+		delete(p.inflight, alert)
+		if owner := p.owners[seq]; owner != nil {
+			owner.pending--
+			delete(p.owners, seq)
+		}
+		seqs = append(seqs, seq)
+	}
+
+	if len(seqs) == 0 {
+		return nil
+	}
+
+	if err := p.write(&record{Type: recordAck, Acks: seqs}); err != nil {
+		return err
+	}
+
+	return p.truncate()
+}
+
+// Requeue pushes the alerts back to the queue without writing the log again, it blocks until there is room in the queue.
+// The alerts which can not be requeued because the provider has been closed will be replayed after restarting.
+func (p *fileProvider) Requeue(alerts []*template.Alert) error {
+	for _, alert := range alerts {
+		select {
+		case p.slots <- struct{}{}:
+		case <-p.stopCh:
+			return provider.ErrClosed
+		}
+
+		p.mutex.Lock()
+		if p.closed {
+			p.mutex.Unlock()
+			return provider.ErrClosed
+		}
+		p.ch <- alert
+		p.mutex.Unlock()
+	}
+
+	return nil
+}
+
+func (p *fileProvider) Len() int {
+	return len(p.ch)
+}
+
+func (p *fileProvider) Cap() int {
+	return cap(p.ch)
+}
+
+func (p *fileProvider) Close() error {
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		return nil
+	}
+
+	p.closed = true
+	close(p.stopCh)
+	close(p.ch)
+
+	// The active segment is kept open, so the alerts which are still being processed can be acknowledged.
+	return p.active.Sync()
+}
@@ -0,0 +1,222 @@
+package file
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/template"
+)
+
+func newTestProvider(t *testing.T, path string, size int64) *fileProvider {
+	t.Helper()
+
+	*dir = path
+	*segmentSize = size
+	*fsyncPolicy = fsyncNever
+	*queueLen = 100
+	*pushTimeout = time.Second
+
+	p, err := NewProvider()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.(*fileProvider)
+}
+
+func newAlert(name string) *template.Alert {
+	return &template.Alert{
+		Status: "firing",
+		Labels: template.KV{"alertname": name},
+	}
+}
+
+func pull(t *testing.T, p *fileProvider, n int) []*template.Alert {
+	t.Helper()
+
+	alerts, err := p.Pull(n, 500*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != n {
+		t.Fatalf("expected %d alerts, got %d", n, len(alerts))
+	}
+	return alerts
+}
+
+func names(alerts []*template.Alert) []string {
+	var res []string
+	for _, a := range alerts {
+		res = append(res, a.Labels["alertname"])
+	}
+	return res
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRestartRecovery(t *testing.T) {
+	path := t.TempDir()
+
+	p := newTestProvider(t, path, 1<<20)
+	for _, name := range []string{"a", "b", "c"} {
+		if err := p.Push(newAlert(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	alerts := pull(t, p, 3)
+	// Only the first alert has been processed before crashing.
+	if err := p.Ack(alerts[:1]); err != nil {
+		t.Fatal(err)
+	}
+	_ = p.Close()
+
+	p = newTestProvider(t, path, 1<<20)
+	defer func() {
+		_ = p.Close()
+	}()
+
+	if got := names(pull(t, p, 2)); !equal(got, []string{"b", "c"}) {
+		t.Fatalf("expected the unacknowledged alerts to be replayed in order, got %v", got)
+	}
+}
+
+func TestTornTailReplay(t *testing.T) {
+	path := t.TempDir()
+
+	p := newTestProvider(t, path, 1<<20)
+	for _, name := range []string{"a", "b"} {
+		if err := p.Push(newAlert(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	index := p.segments[len(p.segments)-1].index
+	_ = p.Close()
+
+	// Simulate a crash during writing, the tail of the segment is a record header with a huge length and a partial payload.
+	f, err := os.OpenFile(segmentName(path, index), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0, '{'}); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	p = newTestProvider(t, path, 1<<20)
+	defer func() {
+		_ = p.Close()
+	}()
+
+	if got := names(pull(t, p, 2)); !equal(got, []string{"a", "b"}) {
+		t.Fatalf("expected the records before the torn tail to be replayed, got %v", got)
+	}
+}
+
+func TestTornRecordTruncated(t *testing.T) {
+	path := t.TempDir()
+
+	p := newTestProvider(t, path, 1<<20)
+	if err := p.Push(newAlert("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed write truncates the segment to the size before the write, so that the partial record
+	// will not hide the records written after it.
+	if _, err := p.active.Write([]byte{0, 0, 0, 10, 1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.active.Truncate(p.size); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Push(newAlert("b")); err != nil {
+		t.Fatal(err)
+	}
+	_ = p.Close()
+
+	records, err := readSegment(segmentName(path, p.segments[len(p.segments)-1].index))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+}
+
+func TestAckTruncation(t *testing.T) {
+	path := t.TempDir()
+
+	// Every push cuts a new segment.
+	p := newTestProvider(t, path, 1)
+	defer func() {
+		_ = p.Close()
+	}()
+
+	for _, name := range []string{"a", "b", "c"} {
+		if err := p.Push(newAlert(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	alerts := pull(t, p, 3)
+
+	// The segment of b can not be removed before it is acknowledged, neither can the segments after it.
+	if err := p.Ack([]*template.Alert{alerts[0], alerts[2]}); err != nil {
+		t.Fatal(err)
+	}
+	indexes, err := listSegments(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indexes) == 0 || indexes[0] != 2 {
+		t.Fatalf("expected the segments to start from the segment of b, got %v", indexes)
+	}
+
+	if err := p.Ack(alerts[1:2]); err != nil {
+		t.Fatal(err)
+	}
+	indexes, err = listSegments(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(indexes) != 1 {
+		t.Fatalf("expected only the active segment is kept, got %v", indexes)
+	}
+
+	// Acknowledging twice is a no-op.
+	if err := p.Ack(alerts); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRequeue(t *testing.T) {
+	p := newTestProvider(t, t.TempDir(), 1<<20)
+	defer func() {
+		_ = p.Close()
+	}()
+
+	if err := p.Push(newAlert("a")); err != nil {
+		t.Fatal(err)
+	}
+	alerts := pull(t, p, 1)
+	if err := p.Requeue(alerts); err != nil {
+		t.Fatal(err)
+	}
+
+	requeued := pull(t, p, 1)
+	if requeued[0] != alerts[0] {
+		t.Fatal("expected the same alert to be pulled again")
+	}
+	if _, ok := p.inflight[alerts[0]]; !ok {
+		t.Fatal("expected the requeued alert to be still in flight")
+	}
+}
@@ -0,0 +1,146 @@
+package file
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+)
+
+const (
+	segmentSuffix = ".wal"
+	// Every record starts with a header which contains the length and the crc32 checksum of the payload.
+	headerSize = 8
+
+	recordPush = "push"
+	recordAck  = "ack"
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// record is the unit written to the write-ahead log.
+// A push record holds an alert, an ack record holds the sequences of the processed alerts.
+type record struct {
+	Type  string          `json:"type"`
+	Seq   uint64          `json:"seq,omitempty"`
+	Alert *template.Alert `json:"alert,omitempty"`
+	Acks  []uint64        `json:"acks,omitempty"`
+}
+
+type segment struct {
+	index uint64
+	// The number of alerts in this segment which have not been acknowledged.
+	pending int
+}
+
+func segmentName(dir string, index uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%016d%s", index, segmentSuffix))
+}
+
+// listSegments returns the indexes of all segments in the directory in ascending order.
+func listSegments(dir string) ([]uint64, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []uint64
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), segmentSuffix) {
+			continue
+		}
+
+		index, err := strconv.ParseUint(strings.TrimSuffix(f.Name(), segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i] < indexes[j]
+	})
+
+	return indexes, nil
+}
+
+func encodeRecord(r *record) ([]byte, error) {
+	payload, err := utils.JsonMarshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, headerSize+len(payload))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.Checksum(payload, castagnoli))
+	copy(buf[headerSize:], payload)
+
+	return buf, nil
+}
+
+// readSegment reads all records in a segment.
+// A torn or corrupted record at the tail of the segment, which is usually caused by a crash during writing,
+// stops the reading, the records before it are still returned.
+func readSegment(path string) ([]*record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	remaining := info.Size()
+
+	var records []*record
+	reader := bufio.NewReader(f)
+	header := make([]byte, headerSize)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return records, nil
+			}
+			return records, err
+		}
+
+		remaining = remaining - headerSize
+
+		// The length of a torn record may be garbage, do not allocate more than the rest of the segment.
+		length := int64(binary.BigEndian.Uint32(header[0:4]))
+		if length > remaining {
+			return records, nil
+		}
+		remaining = remaining - length
+
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return records, nil
+			}
+			return records, err
+		}
+
+		if crc32.Checksum(payload, castagnoli) != binary.BigEndian.Uint32(header[4:8]) {
+			return records, nil
+		}
+
+		r := &record{}
+		if err := utils.JsonUnmarshal(payload, r); err != nil {
+			return records, nil
+		}
+		records = append(records, r)
+	}
+}
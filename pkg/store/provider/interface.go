@@ -1,13 +1,29 @@
 package provider
 
 import (
+	"errors"
 	"time"
 
 	"github.com/kubesphere/notification-manager/pkg/template"
 )
 
+var (
+	// ErrTimeout is returned when an alert can not be pushed to the store in time, usually because the store is saturated.
+	ErrTimeout = errors.New("store push timeout")
+	// ErrClosed is returned when the store has been closed.
+	ErrClosed = errors.New("store closed")
+)
+
 type Provider interface {
 	Push(alert *template.Alert) error
 	Pull(batchSize int, batchWait time.Duration) ([]*template.Alert, error)
+	// Ack marks the alerts returned by Pull as processed, the provider can release them after that.
+	Ack(alerts []*template.Alert) error
+	// Requeue returns the alerts returned by Pull to the queue, they will be pulled again later.
+	Requeue(alerts []*template.Alert) error
+	// Len returns the number of alerts waiting to be pulled.
+	Len() int
+	// Cap returns the maximum number of alerts which can wait to be pulled.
+	Cap() int
 	Close() error
 }
@@ -2,11 +2,11 @@ package memory
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/kubesphere/notification-manager/pkg/store/provider"
 	"github.com/kubesphere/notification-manager/pkg/template"
-	"github.com/kubesphere/notification-manager/pkg/utils"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
 
@@ -17,6 +17,9 @@ var (
 
 type memProvider struct {
 	ch chan *template.Alert
+	// Protect the channel from being closed while pushing.
+	mutex  sync.RWMutex
+	closed bool
 }
 
 func init() {
@@ -38,6 +41,13 @@ func NewProvider() provider.Provider {
 }
 
 func (p *memProvider) Push(alert *template.Alert) error {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.closed {
+		return provider.ErrClosed
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), *pushTimeout)
 	defer cancel()
 
@@ -45,7 +55,7 @@ func (p *memProvider) Push(alert *template.Alert) error {
 	case p.ch <- alert:
 		return nil
 	case <-ctx.Done():
-		return utils.Error("Time out")
+		return provider.ErrTimeout
 	}
 }
 
@@ -61,7 +71,7 @@ func (p *memProvider) Pull(batchSize int, batchWait time.Duration) ([]*template.
 			return as, nil
 		case alert := <-p.ch:
 			if alert == nil {
-				return as, utils.Error("Store closed")
+				return as, provider.ErrClosed
 			}
 			as = append(as, alert)
 			if len(as) >= batchSize {
@@ -71,7 +81,37 @@ func (p *memProvider) Pull(batchSize int, batchWait time.Duration) ([]*template.
 	}
 }
 
+func (p *memProvider) Ack(_ []*template.Alert) error {
+	return nil
+}
+
+func (p *memProvider) Requeue(alerts []*template.Alert) error {
+	for _, alert := range alerts {
+		if err := p.Push(alert); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *memProvider) Len() int {
+	return len(p.ch)
+}
+
+func (p *memProvider) Cap() int {
+	return cap(p.ch)
+}
+
 func (p *memProvider) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		return nil
+	}
+
+	p.closed = true
 	close(p.ch)
 	return nil
 }
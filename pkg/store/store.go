@@ -1,25 +1,102 @@
 package store
 
 import (
+	"sync"
+	"time"
+
 	"github.com/kubesphere/notification-manager/pkg/store/provider"
+	"github.com/kubesphere/notification-manager/pkg/store/provider/file"
 	"github.com/kubesphere/notification-manager/pkg/store/provider/memory"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/kubesphere/notification-manager/pkg/utils"
 )
 
 const (
 	providerMemory = "memory"
+	providerFile   = "file"
 )
 
 type AlertStore struct {
 	provider.Provider
+
+	mutex sync.Mutex
+	// The IDs of the alerts which have been pushed but not acknowledged yet.
+	pending map[string]struct{}
 }
 
-func NewAlertStore(provider string) *AlertStore {
+func NewAlertStore(provider string) (*AlertStore, error) {
 
-	as := &AlertStore{}
+	as := &AlertStore{
+		pending: make(map[string]struct{}),
+	}
 
-	if provider == providerMemory {
+	switch provider {
+	case providerMemory:
 		as.Provider = memory.NewProvider()
+	case providerFile:
+		p, err := file.NewProvider()
+		if err != nil {
+			return nil, err
+		}
+		as.Provider = p
+	default:
+		return nil, utils.Errorf("unknown store type %s", provider)
+	}
+
+	registerQueueMetrics(as.Provider)
+
+	return as, nil
+}
+
+// Push pushes an alert to the store, and records the result and the latency of the push.
+// The alert which has the same ID as an alert pushed but not acknowledged yet is dropped,
+// because the sender resends all the alerts when some of them are rejected.
+func (as *AlertStore) Push(alert *template.Alert) error {
+
+	if !as.markPending(alert.ID) {
+		pushTotal.WithLabelValues(pushDuplicate).Inc()
+		return nil
 	}
 
-	return as
+	start := time.Now()
+	err := as.Provider.Push(alert)
+	pushDuration.Observe(time.Since(start).Seconds())
+	pushTotal.WithLabelValues(pushResult(err)).Inc()
+	if err != nil {
+		as.unmarkPending(alert.ID)
+	}
+	return err
+}
+
+// Ack acknowledges the alerts, the alerts with the same IDs can be pushed again after that.
+func (as *AlertStore) Ack(alerts []*template.Alert) error {
+
+	err := as.Provider.Ack(alerts)
+	for _, alert := range alerts {
+		as.unmarkPending(alert.ID)
+	}
+	return err
+}
+
+// markPending marks the alert as pending, false is returned if it has been pending.
+func (as *AlertStore) markPending(id string) bool {
+	if id == "" {
+		return true
+	}
+
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	if _, ok := as.pending[id]; ok {
+		return false
+	}
+	as.pending[id] = struct{}{}
+	return true
+}
+
+func (as *AlertStore) unmarkPending(id string) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	delete(as.pending, id)
 }
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubesphere/notification-manager/pkg/store/provider"
+	"github.com/kubesphere/notification-manager/pkg/template"
+)
+
+type fakeProvider struct {
+	provider.Provider
+	pushed []*template.Alert
+	err    error
+}
+
+func (p *fakeProvider) Push(alert *template.Alert) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.pushed = append(p.pushed, alert)
+	return nil
+}
+
+func (p *fakeProvider) Ack(_ []*template.Alert) error {
+	return nil
+}
+
+func TestPushDuplicate(t *testing.T) {
+	p := &fakeProvider{}
+	as := &AlertStore{Provider: p, pending: make(map[string]struct{})}
+
+	a := &template.Alert{ID: "a"}
+	b := &template.Alert{ID: "b"}
+
+	// The push of b fails, so it can be pushed again.
+	p.err = provider.ErrTimeout
+	if err := as.Push(b); err == nil {
+		t.Fatal("expected the push to fail")
+	}
+	p.err = nil
+
+	for _, alert := range []*template.Alert{a, b, a, b} {
+		if err := as.Push(alert); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(p.pushed) != 2 {
+		t.Fatalf("expected the duplicate alerts to be dropped, got %d alerts pushed", len(p.pushed))
+	}
+
+	// The alert can be pushed again after it was acknowledged.
+	if err := as.Ack([]*template.Alert{a}); err != nil {
+		t.Fatal(err)
+	}
+	if err := as.Push(a); err != nil {
+		t.Fatal(err)
+	}
+	if err := as.Push(b); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.pushed) != 3 || p.pushed[2] != a {
+		t.Fatalf("expected only the acknowledged alert to be pushed again, got %d alerts pushed", len(p.pushed))
+	}
+
+	// The alerts without ID are never dropped.
+	for i := 0; i < 2; i++ {
+		if err := as.Push(&template.Alert{StartsAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(p.pushed) != 5 {
+		t.Fatalf("expected the alerts without ID to be pushed, got %d alerts pushed", len(p.pushed))
+	}
+}
+
+func TestNewAlertStoreTwice(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		as, err := NewAlertStore(providerMemory)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = as.Close()
+	}
+}
@@ -206,6 +206,18 @@ type Alert struct {
 	NotifySuccessful bool                              `json:"-"`
 	NotificationTime time.Time                         `json:"notificationTime,omitempty"`
 	Receiver         map[string]map[string]interface{} `json:"receiver,omitempty"`
+	// Acknowledgement is set if someone has acknowledged the alert.
+	Acknowledgement *Acknowledgement `json:"acknowledgement,omitempty"`
+	// The labels copied from the metadata of the objects the alert belongs to,
+	// which are excluded from the fingerprint.
+	enriched map[string]struct{}
+}
+
+// Acknowledgement records who acknowledged an alert and when.
+type Acknowledgement struct {
+	By      string    `json:"by"`
+	Comment string    `json:"comment,omitempty"`
+	Time    time.Time `json:"time"`
 }
 
 func (a *Alert) Message() string {
@@ -238,6 +250,41 @@ func (a *Alert) MessageCN() string {
 	return message
 }
 
+// Fingerprint returns the hash of the labels, which identifies the alerts with the same labels.
+// The receiver name label added when notifying is excluded, so that the fingerprint will not change after the alert is sent.
+// The enriched labels are excluded too, so that the fingerprint will not change when the objects the alert belongs to
+// change or are deleted, for example, a resolved alert whose pod has gone.
+func (a *Alert) Fingerprint() string {
+	if _, ok := a.Labels[constants.ReceiverName]; !ok && len(a.enriched) == 0 {
+		return utils.Hash(a.Labels)
+	}
+
+	labels := a.Labels.Clone()
+	delete(labels, constants.ReceiverName)
+	for name := range a.enriched {
+		delete(labels, name)
+	}
+	return utils.Hash(labels)
+}
+
+// Enrich sets the label copied from the metadata of the object the alert belongs to, the label will be excluded
+// from the fingerprint. The existing label will not be overwritten.
+func (a *Alert) Enrich(name, value string) {
+	if a.Labels == nil {
+		a.Labels = make(KV)
+	}
+
+	if _, ok := a.Labels[name]; ok {
+		return
+	}
+
+	a.Labels[name] = value
+	if a.enriched == nil {
+		a.enriched = make(map[string]struct{})
+	}
+	a.enriched[name] = struct{}{}
+}
+
 // Alerts is a list of Alert objects.
 type Alerts []*Alert
 
@@ -264,6 +311,14 @@ func (as Alerts) Resolved() []*Alert {
 }
 
 func (a *Alert) Clone() *Alert {
+	var enriched map[string]struct{}
+	if a.enriched != nil {
+		enriched = make(map[string]struct{}, len(a.enriched))
+		for name := range a.enriched {
+			enriched[name] = struct{}{}
+		}
+	}
+
 	return &Alert{
 		ID:               a.ID,
 		Status:           a.Status,
@@ -273,5 +328,7 @@ func (a *Alert) Clone() *Alert {
 		Labels:           a.Labels.Clone(),
 		Annotations:      a.Annotations.Clone(),
 		Receiver:         a.Receiver,
+		Acknowledgement:  a.Acknowledgement,
+		enriched:         enriched,
 	}
 }
@@ -0,0 +1,181 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/kubesphere/notification-manager/pkg/constants"
+	"github.com/kubesphere/notification-manager/pkg/enrichment"
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/route"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+	"github.com/modern-go/reflect2"
+)
+
+type ackRequest struct {
+	// The labels of the alert, which are used to find the receivers the alert is routed to.
+	// The labels of the alert seen by this replica recently are used if not set.
+	Labels template.KV `json:"labels,omitempty"`
+	// Who acknowledges the alert, defaults to the authenticated tenant.
+	By      string `json:"by,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// authorize returns the authenticated tenant of the request, and whether the alert with the fingerprint
+// is routed to a receiver of the tenant. A response will be written if the request is not authorized.
+func (h *HttpHandler) authorize(w http.ResponseWriter, r *http.Request, fingerprint string, labels template.KV) (string, bool) {
+
+	tenant := r.Header.Get(*tenantHeader)
+	if utils.StringIsNil(tenant) {
+		h.handle(w, &response{http.StatusUnauthorized, fmt.Sprintf("header %s is required", *tenantHeader)})
+		return "", false
+	}
+
+	var alert *template.Alert
+	if labels != nil {
+		alert = h.alertOfLabels(fingerprint, labels)
+		if alert == nil {
+			h.handle(w, &response{http.StatusBadRequest, fmt.Sprintf("the labels do not match the fingerprint %s", fingerprint)})
+			return "", false
+		}
+	} else if alert = h.recent.Get(fingerprint); alert == nil {
+		h.handle(w, &response{http.StatusNotFound, fmt.Sprintf("alert %s has not been seen recently, the labels of the alert are required", fingerprint)})
+		return "", false
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.wkrTimeout)
+	defer cancel()
+
+	routed, err := h.routedToTenant(ctx, alert, tenant)
+	if err != nil {
+		h.handle(w, &response{http.StatusInternalServerError, fmt.Sprintf("route alert %s failed, %s", fingerprint, err.Error())})
+		return "", false
+	}
+	if !routed {
+		h.handle(w, &response{http.StatusNotFound, fmt.Sprintf("alert %s is not sent to tenant %s", fingerprint, tenant)})
+		return "", false
+	}
+
+	return tenant, true
+}
+
+// alertOfLabels returns the alert with the labels if its fingerprint is the given one, otherwise nil.
+// The labels may contain the labels copied by the enrichment, which are excluded from the fingerprint.
+func (h *HttpHandler) alertOfLabels(fingerprint string, labels template.KV) *template.Alert {
+
+	alert := &template.Alert{Status: constants.AlertFiring, Labels: labels.Clone()}
+	if alert.Fingerprint() == fingerprint {
+		return alert
+	}
+
+	alert = &template.Alert{Status: constants.AlertFiring, Labels: labels.Clone()}
+	enriched := make(map[string]string)
+	for _, name := range enrichment.LabelNames(h.notifierCtl.GetEnrichment()) {
+		if v, ok := alert.Labels[name]; ok {
+			enriched[name] = v
+			delete(alert.Labels, name)
+		}
+	}
+	for name, v := range enriched {
+		alert.Enrich(name, v)
+	}
+	if len(enriched) > 0 && alert.Fingerprint() == fingerprint {
+		return alert
+	}
+
+	return nil
+}
+
+// routedToTenant returns whether the alert is routed to a receiver of the tenant by the routers and the tenant receivers,
+// so that every replica gets the same result no matter which one has processed the alert.
+func (h *HttpHandler) routedToTenant(ctx context.Context, alert *template.Alert, tenant string) (bool, error) {
+
+	_, output, err := route.NewStage(h.notifierCtl, nil).Exec(ctx, h.logger, []*template.Alert{alert})
+	if err != nil || reflect2.IsNil(output) {
+		return false, err
+	}
+
+	for rcv := range output.(map[internal.Receiver][]*template.Alert) {
+		if rcv.GetTenantID() == tenant {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// AckAlert acknowledges the alert with the fingerprint, the acknowledged alert will not be notified repeatedly,
+// and its escalation will be stopped. Only the tenant to which the alert is routed can acknowledge it.
+func (h *HttpHandler) AckAlert(w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		_ = r.Body.Close()
+	}()
+
+	req := ackRequest{}
+	if err := decodeAckRequest(r, &req); err != nil {
+		h.handle(w, &response{http.StatusBadRequest, err.Error()})
+		return
+	}
+
+	fingerprint := chi.URLParam(r, "fingerprint")
+	tenant, ok := h.authorize(w, r, fingerprint, req.Labels)
+	if !ok {
+		return
+	}
+
+	// A tenant can not acknowledge the alert on behalf of others.
+	if utils.StringIsNil(req.By) {
+		req.By = tenant
+	} else if req.By != tenant {
+		h.handle(w, &response{http.StatusForbidden, fmt.Sprintf("by must be the tenant %s", tenant)})
+		return
+	}
+
+	ack, err := h.acks.Acknowledge(fingerprint, req.By, req.Comment)
+	if err != nil {
+		h.handle(w, &response{http.StatusInternalServerError, fmt.Sprintf("acknowledge alert %s failed, %s", fingerprint, err.Error())})
+		return
+	}
+
+	bs, _ := utils.JsonMarshalIndent(ack, "", "  ")
+	_, _ = w.Write(bs)
+}
+
+// UnackAlert removes the acknowledgement of the alert with the fingerprint.
+// Only the tenant to which the alert is routed can remove the acknowledgement.
+func (h *HttpHandler) UnackAlert(w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		_ = r.Body.Close()
+	}()
+
+	req := ackRequest{}
+	if err := decodeAckRequest(r, &req); err != nil {
+		h.handle(w, &response{http.StatusBadRequest, err.Error()})
+		return
+	}
+
+	fingerprint := chi.URLParam(r, "fingerprint")
+	if _, ok := h.authorize(w, r, fingerprint, req.Labels); !ok {
+		return
+	}
+
+	if err := h.acks.Delete(fingerprint); err != nil {
+		h.handle(w, &response{http.StatusInternalServerError, fmt.Sprintf("unacknowledge alert %s failed, %s", fingerprint, err.Error())})
+		return
+	}
+
+	h.handle(w, &response{http.StatusOK, fmt.Sprintf("unacknowledge alert %s successfully", fingerprint)})
+}
+
+// decodeAckRequest decodes the body of the request, the body is optional.
+func decodeAckRequest(r *http.Request, req *ackRequest) error {
+	if err := utils.JsonDecode(r.Body, req); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/dispatcher"
+	"github.com/kubesphere/notification-manager/pkg/template"
+)
+
+func newAckRouter() http.Handler {
+	h := &HttpHandler{
+		logger:      log.NewNopLogger(),
+		notifierCtl: &controller.Controller{},
+		recent:      dispatcher.NewRecentAlerts(),
+	}
+
+	r := chi.NewRouter()
+	r.Post("/api/v2/alerts/{fingerprint}/ack", h.AckAlert)
+	r.Post("/api/v2/alerts/{fingerprint}/unack", h.UnackAlert)
+	return r
+}
+
+func TestAckUnauthorized(t *testing.T) {
+	*tenantHeader = "X-Remote-User"
+	router := newAckRouter()
+
+	fingerprint := (&template.Alert{Labels: template.KV{"alertname": "a"}}).Fingerprint()
+	tests := []struct {
+		name   string
+		url    string
+		tenant string
+		body   string
+		status int
+	}{
+		{"no tenant", "/api/v2/alerts/" + fingerprint + "/ack", "", "", http.StatusUnauthorized},
+		{"invalid body", "/api/v2/alerts/" + fingerprint + "/ack", "user", "{", http.StatusBadRequest},
+		{"labels not matching the fingerprint", "/api/v2/alerts/" + fingerprint + "/ack", "user", `{"labels":{"alertname":"b"}}`, http.StatusBadRequest},
+		// The alert has not been seen by this replica, and the labels are not set.
+		{"unknown alert", "/api/v2/alerts/" + fingerprint + "/ack", "user", "", http.StatusNotFound},
+		{"unknown alert to unack", "/api/v2/alerts/" + fingerprint + "/unack", "user", "", http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, test.url, strings.NewReader(test.body))
+		if test.tenant != "" {
+			req.Header.Set(*tenantHeader, test.tenant)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.status {
+			t.Fatalf("%s: expected status %d, got %d, %s", test.name, test.status, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestAlertOfLabels(t *testing.T) {
+	h := &HttpHandler{notifierCtl: &controller.Controller{}}
+
+	labels := template.KV{"alertname": "a", "namespace": "default"}
+	fingerprint := (&template.Alert{Labels: labels}).Fingerprint()
+
+	alert := h.alertOfLabels(fingerprint, labels)
+	if alert == nil || alert.Fingerprint() != fingerprint {
+		t.Fatalf("expected the alert with the fingerprint %s, got %v", fingerprint, alert)
+	}
+	if h.alertOfLabels(fingerprint, template.KV{"alertname": "a"}) != nil {
+		t.Fatal("expected no alert when the labels do not match the fingerprint")
+	}
+}
@@ -0,0 +1,126 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/kubesphere/notification-manager/pkg/deadletter"
+	"github.com/kubesphere/notification-manager/pkg/internal"
+	"github.com/kubesphere/notification-manager/pkg/notify"
+	"github.com/kubesphere/notification-manager/pkg/notify/notifier"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+)
+
+// deadLetterTenant returns the tenant which the dead letters are filtered by. A tenant can only access its own dead letters,
+// an admin can access the dead letters of all the tenants, and filter them by the requested tenant.
+// A response will be written if the request is not authenticated.
+func (h *HttpHandler) deadLetterTenant(w http.ResponseWriter, r *http.Request) (string, bool) {
+
+	tenant := r.Header.Get(*tenantHeader)
+	if utils.StringIsNil(tenant) {
+		h.handle(w, &response{http.StatusUnauthorized, fmt.Sprintf("header %s is required", *tenantHeader)})
+		return "", false
+	}
+
+	if isAdmin(tenant) {
+		return r.FormValue("tenant"), true
+	}
+
+	return tenant, true
+}
+
+// getDeadLetter returns the dead letter with the id if it can be accessed by the tenant of the request.
+// A response will be written if the dead letter is not found.
+func (h *HttpHandler) getDeadLetter(w http.ResponseWriter, r *http.Request) (*deadletter.Entry, bool) {
+
+	tenant, ok := h.deadLetterTenant(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	id := chi.URLParam(r, "id")
+	e := h.deadLetters.Get(id)
+	// The dead letter of other tenants is not found for the tenant.
+	if e == nil || (!utils.StringIsNil(tenant) && e.Tenant != tenant) {
+		h.handle(w, &response{http.StatusNotFound, fmt.Sprintf("dead letter %s not found", id)})
+		return nil, false
+	}
+
+	return e, true
+}
+
+// ListDeadLetters lists the notifications of the tenant in the dead-letter queue,
+// the result can be filtered by the receiver type and the receiver name, and by the tenant for an admin.
+func (h *HttpHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+
+	_ = r.ParseForm()
+	tenant, ok := h.deadLetterTenant(w, r)
+	if !ok {
+		return
+	}
+
+	bs, _ := utils.JsonMarshalIndent(h.deadLetters.List(tenant, r.FormValue("type"), r.FormValue("name")), "", "  ")
+	_, _ = w.Write(bs)
+}
+
+// ReplayDeadLetter sends the notification in the dead-letter queue to the original receiver again,
+// the notification will be removed from the queue if it is sent successfully.
+func (h *HttpHandler) ReplayDeadLetter(w http.ResponseWriter, r *http.Request) {
+
+	e, ok := h.getDeadLetter(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.wkrTimeout)
+	ctx = context.WithValue(ctx, "seq", "replay-"+e.ID)
+	defer cancel()
+	if e.Attempt != nil {
+		ctx = notifier.WithAttempt(ctx, e.Attempt)
+	}
+
+	input := map[internal.Receiver][]*template.Data{
+		e.Receiver: {e.Data},
+	}
+	if _, _, err := notify.NewStage(h.notifierCtl).Exec(ctx, h.logger, input); err != nil {
+		h.handle(w, &response{http.StatusInternalServerError, fmt.Sprintf("replay dead letter %s failed, %s", e.ID, err.Error())})
+		return
+	}
+
+	h.deadLetters.Remove(e.ID)
+	h.handle(w, &response{http.StatusOK, fmt.Sprintf("replay dead letter %s successfully", e.ID)})
+}
+
+// DeleteDeadLetter removes the notification from the dead-letter queue.
+func (h *HttpHandler) DeleteDeadLetter(w http.ResponseWriter, r *http.Request) {
+
+	e, ok := h.getDeadLetter(w, r)
+	if !ok {
+		return
+	}
+
+	if !h.deadLetters.Remove(e.ID) {
+		h.handle(w, &response{http.StatusNotFound, fmt.Sprintf("dead letter %s not found", e.ID)})
+		return
+	}
+
+	h.handle(w, &response{http.StatusOK, fmt.Sprintf("delete dead letter %s successfully", e.ID)})
+}
+
+// PurgeDeadLetters removes the notifications of the tenant matched the receiver type and the receiver name
+// from the dead-letter queue. Only an admin can remove the notifications of all the tenants,
+// all notifications will be removed if the admin specifies no filter.
+func (h *HttpHandler) PurgeDeadLetters(w http.ResponseWriter, r *http.Request) {
+
+	_ = r.ParseForm()
+	tenant, ok := h.deadLetterTenant(w, r)
+	if !ok {
+		return
+	}
+
+	n := h.deadLetters.Purge(tenant, r.FormValue("type"), r.FormValue("name"))
+	h.handle(w, &response{http.StatusOK, fmt.Sprintf("delete %d dead letters successfully", n)})
+}
@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/pkg/deadletter"
+)
+
+func newDeadLetterRouter() http.Handler {
+	h := &HttpHandler{logger: log.NewNopLogger(), deadLetters: &deadletter.Queue{}}
+
+	r := chi.NewRouter()
+	r.Get("/api/v2/deadletters", h.ListDeadLetters)
+	r.Delete("/api/v2/deadletters", h.PurgeDeadLetters)
+	r.Post("/api/v2/deadletters/{id}/replay", h.ReplayDeadLetter)
+	r.Delete("/api/v2/deadletters/{id}", h.DeleteDeadLetter)
+	return r
+}
+
+func TestDeadLettersUnauthorized(t *testing.T) {
+	*tenantHeader = "X-Remote-User"
+	router := newDeadLetterRouter()
+
+	requests := []struct {
+		method, url string
+	}{
+		{http.MethodGet, "/api/v2/deadletters"},
+		{http.MethodDelete, "/api/v2/deadletters"},
+		{http.MethodPost, "/api/v2/deadletters/id/replay"},
+		{http.MethodDelete, "/api/v2/deadletters/id"},
+	}
+	for _, req := range requests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(req.method, req.url, nil))
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("%s %s: expected status %d, got %d", req.method, req.url, http.StatusUnauthorized, w.Code)
+		}
+
+		r := httptest.NewRequest(req.method, req.url, nil)
+		r.Header.Set(*tenantHeader, "user")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code == http.StatusUnauthorized {
+			t.Fatalf("%s %s: expected the authenticated request to be accepted", req.method, req.url)
+		}
+	}
+}
+
+func TestDeadLetterTenant(t *testing.T) {
+	*tenantHeader = "X-Remote-User"
+	*adminTenants = []string{"admin"}
+	defer func() {
+		*adminTenants = nil
+	}()
+
+	h := &HttpHandler{logger: log.NewNopLogger()}
+	tests := []struct {
+		tenant, url, expected string
+	}{
+		// A tenant can only access its own dead letters.
+		{"user", "/api/v2/deadletters", "user"},
+		{"user", "/api/v2/deadletters?tenant=other", "user"},
+		// An admin can access the dead letters of all the tenants.
+		{"admin", "/api/v2/deadletters", ""},
+		{"admin", "/api/v2/deadletters?tenant=other", "other"},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, test.url, nil)
+		r.Header.Set(*tenantHeader, test.tenant)
+		_ = r.ParseForm()
+		tenant, ok := h.deadLetterTenant(httptest.NewRecorder(), r)
+		if !ok || tenant != test.expected {
+			t.Fatalf("tenant %s, %s: expected %q, got (%q, %v)", test.tenant, test.url, test.expected, tenant, ok)
+		}
+	}
+}
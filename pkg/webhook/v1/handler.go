@@ -2,22 +2,34 @@ package v1
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/ack"
 	"github.com/kubesphere/notification-manager/pkg/aggregation"
 	"github.com/kubesphere/notification-manager/pkg/constants"
 	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/deadletter"
+	"github.com/kubesphere/notification-manager/pkg/dispatcher"
 	"github.com/kubesphere/notification-manager/pkg/internal"
 	"github.com/kubesphere/notification-manager/pkg/notify"
 	"github.com/kubesphere/notification-manager/pkg/stage"
 	"github.com/kubesphere/notification-manager/pkg/store"
+	"github.com/kubesphere/notification-manager/pkg/store/provider"
 	"github.com/kubesphere/notification-manager/pkg/template"
 	"github.com/kubesphere/notification-manager/pkg/utils"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// The time in seconds the sender should wait before retrying when the store is saturated.
+	retryAfter = 30
 )
 
 type HttpHandler struct {
@@ -25,6 +37,9 @@ type HttpHandler struct {
 	wkrTimeout  time.Duration
 	notifierCtl *controller.Controller
 	alerts      *store.AlertStore
+	deadLetters *deadletter.Queue
+	acks        *ack.Store
+	recent      *dispatcher.RecentAlerts
 }
 
 type response struct {
@@ -32,12 +47,22 @@ type response struct {
 	Message string
 }
 
-func New(logger log.Logger, wkrTimeout time.Duration, ctl *controller.Controller, alerts *store.AlertStore) *HttpHandler {
+type alertResponse struct {
+	Status   int
+	Message  string
+	Accepted []string `json:"Accepted,omitempty"`
+	Rejected []string `json:"Rejected,omitempty"`
+}
+
+func New(logger log.Logger, wkrTimeout time.Duration, ctl *controller.Controller, alerts *store.AlertStore, deadLetters *deadletter.Queue, acks *ack.Store, recent *dispatcher.RecentAlerts) *HttpHandler {
 	h := &HttpHandler{
 		logger:      logger,
 		wkrTimeout:  wkrTimeout,
 		notifierCtl: ctl,
 		alerts:      alerts,
+		deadLetters: deadLetters,
+		acks:        acks,
+		recent:      recent,
 	}
 	return h
 }
@@ -62,26 +87,48 @@ func (h *HttpHandler) Alert(w http.ResponseWriter, r *http.Request) {
 	//}
 
 	cluster := h.notifierCtl.GetCluster()
+	var accepted, rejected []string
+	var pushErr error
 	for _, alert := range data.Alerts {
 		if v := alert.Labels["cluster"]; v == "" {
 			alert.Labels["cluster"] = cluster
 		}
 
+		// The ID is calculated before the alert time is added, so that the alert resent by Alertmanager has the same ID,
+		// and will not be queued twice if it has been accepted.
+		alert.ID = utils.Hash(alert)
+
 		if alert.Labels["alerttype"] == "metric" {
 			alert.Annotations["alerttime"] = time.Now().Local().String()
 		}
+		// Once the store is saturated, reject the remaining alerts directly instead of waiting for each of them.
+		if pushErr == nil {
+			if pushErr = h.alerts.Push(alert); pushErr == nil {
+				accepted = append(accepted, alert.ID)
+				continue
+			}
+			_ = level.Error(h.logger).Log("msg", "push alert error", "error", pushErr.Error())
+		}
+		rejected = append(rejected, alert.ID)
+	}
 
-		alert.ID = utils.Hash(alert)
-		if err := h.alerts.Push(alert); err != nil {
-			_ = level.Error(h.logger).Log("msg", "push alert error", "error", err.Error())
+	if pushErr != nil {
+		// Alertmanager only retries the notifications which failed with a 5xx status code,
+		// so the rejected alerts will be resent after the `Retry-After`.
+		msg := "Notification manager is saturated"
+		if errors.Is(pushErr, provider.ErrClosed) {
+			msg = "Notification manager is shutting down"
 		}
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		h.handleAlert(w, &alertResponse{http.StatusServiceUnavailable, msg, accepted, rejected})
+		return
 	}
 
-	h.handle(w, &response{http.StatusOK, "Notification request accepted"})
+	h.handleAlert(w, &alertResponse{Status: http.StatusOK, Message: "Notification request accepted", Accepted: accepted})
 }
 
-func (h *HttpHandler) ServeMetrics(w http.ResponseWriter, _ *http.Request) {
-	h.handle(w, &response{http.StatusOK, "metrics"})
+func (h *HttpHandler) ServeMetrics(w http.ResponseWriter, r *http.Request) {
+	promhttp.Handler().ServeHTTP(w, r)
 }
 
 func (h *HttpHandler) ServeReload(w http.ResponseWriter, _ *http.Request) {
@@ -113,6 +160,18 @@ func (h *HttpHandler) handle(w http.ResponseWriter, resp *response) {
 	}
 }
 
+func (h *HttpHandler) handleAlert(w http.ResponseWriter, resp *alertResponse) {
+	bytes, _ := utils.JsonMarshal(resp)
+	w.WriteHeader(resp.Status)
+	_, _ = w.Write(bytes)
+
+	if resp.Status != http.StatusOK {
+		_ = level.Error(h.logger).Log("msg", resp.Message, "accepted", len(resp.Accepted), "rejected", len(resp.Rejected))
+	} else {
+		_ = level.Debug(h.logger).Log("msg", resp.Message, "accepted", len(resp.Accepted))
+	}
+}
+
 func (h *HttpHandler) ListReceivers(w http.ResponseWriter, r *http.Request) {
 
 	_ = r.ParseForm()
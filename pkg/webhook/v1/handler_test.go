@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/store"
+)
+
+func TestAlertUnavailable(t *testing.T) {
+	// The memory store has no capacity without the flags parsed, so every push times out.
+	alerts, err := store.NewAlertStore("memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = alerts.Close()
+	}()
+
+	h := &HttpHandler{
+		logger: log.NewNopLogger(),
+		notifierCtl: &controller.Controller{
+			ReceiverOpts: &v2beta2.Options{Global: &v2beta2.GlobalOptions{Cluster: "host"}},
+		},
+		alerts: alerts,
+	}
+
+	body := `{"alerts": [
+		{"status": "firing", "labels": {"alertname": "a"}, "annotations": {}},
+		{"status": "firing", "labels": {"alertname": "b"}, "annotations": {}}
+	]}`
+	r := httptest.NewRequest(http.MethodPost, "/api/v2/alerts", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	h.Alert(w, r)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+	if w.Header().Get("Retry-After") != strconv.Itoa(retryAfter) {
+		t.Fatalf("expected the Retry-After header %d, got %q", retryAfter, w.Header().Get("Retry-After"))
+	}
+
+	resp := alertResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Accepted) != 0 || len(resp.Rejected) != 2 {
+		t.Fatalf("expected all the alerts to be rejected, got %+v", resp)
+	}
+
+	// The alerts get the same IDs when they are resent.
+	r = httptest.NewRequest(http.MethodPost, "/api/v2/alerts", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	h.Alert(w, r)
+	resent := alertResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resent); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(resent.Rejected, ",") != strings.Join(resp.Rejected, ",") {
+		t.Fatalf("expected the same IDs, got %v and %v", resp.Rejected, resent.Rejected)
+	}
+}
@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/oncall"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+)
+
+const (
+	// The time range of the shifts queried at once can not be longer than it.
+	maxOnCallRange = time.Hour * 24 * 90
+	// How far to look ahead for the end of the current shift.
+	onCallLookahead = time.Hour * 24 * 30
+)
+
+type onCallReceiver struct {
+	Tenant string `json:"tenant"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+}
+
+type onCallShift struct {
+	oncall.Shift
+	// The receivers currently selected by the participant.
+	ResolvedReceivers []onCallReceiver `json:"resolvedReceivers"`
+}
+
+// ListOnCall lists who is on call in the on-call schedule. The current shift will be returned if the time range
+// is not specified, or the shifts in the time range [start, end) will be returned.
+func (h *HttpHandler) ListOnCall(w http.ResponseWriter, r *http.Request) {
+
+	name := chi.URLParam(r, "schedule")
+	_ = r.ParseForm()
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.wkrTimeout)
+	defer cancel()
+
+	schedule, err := h.notifierCtl.GetOnCallSchedule(ctx, name)
+	if err != nil {
+		h.handle(w, &response{http.StatusInternalServerError, err.Error()})
+		return
+	}
+	if schedule == nil {
+		h.handle(w, &response{http.StatusNotFound, fmt.Sprintf("on-call schedule %s not found", name)})
+		return
+	}
+
+	now := time.Now()
+	start, end := now, now.Add(onCallLookahead)
+	current := utils.StringIsNil(r.FormValue("start")) && utils.StringIsNil(r.FormValue("end"))
+	if !current {
+		if start, err = time.Parse(time.RFC3339, r.FormValue("start")); err != nil {
+			h.handle(w, &response{http.StatusBadRequest, fmt.Sprintf("invalid start, %s", err.Error())})
+			return
+		}
+		if end, err = time.Parse(time.RFC3339, r.FormValue("end")); err != nil {
+			h.handle(w, &response{http.StatusBadRequest, fmt.Sprintf("invalid end, %s", err.Error())})
+			return
+		}
+		if !end.After(start) || end.Sub(start) > maxOnCallRange {
+			h.handle(w, &response{http.StatusBadRequest, "end must be after start, and the time range must not be longer than 90 days"})
+			return
+		}
+	}
+
+	shifts, err := oncall.Shifts(schedule, start, end)
+	if err != nil {
+		h.handle(w, &response{http.StatusInternalServerError, err.Error()})
+		return
+	}
+
+	// Only the shift which is on call now is needed.
+	if current && len(shifts) > 0 {
+		if shifts[0].Start.After(now) {
+			shifts = nil
+		} else {
+			shifts = shifts[:1]
+		}
+	}
+
+	res := make([]onCallShift, 0, len(shifts))
+	for _, shift := range shifts {
+		res = append(res, onCallShift{
+			Shift:             shift,
+			ResolvedReceivers: h.resolveParticipant(*shift.Receivers),
+		})
+	}
+
+	bs, _ := utils.JsonMarshalIndent(res, "", "  ")
+	_, _ = w.Write(bs)
+}
+
+func (h *HttpHandler) resolveParticipant(rs v2beta2.ReceiverSelector) []onCallReceiver {
+
+	rs.Schedule = ""
+	res := make([]onCallReceiver, 0)
+	for _, rcv := range h.notifierCtl.RcvsFromReceiverSelector(rs) {
+		res = append(res, onCallReceiver{
+			Tenant: rcv.GetTenantID(),
+			Type:   rcv.GetType(),
+			Name:   rcv.GetName(),
+		})
+	}
+
+	return res
+}
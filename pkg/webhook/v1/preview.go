@@ -0,0 +1,195 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/dispatcher"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+)
+
+const (
+	// The default time range of the silence preview.
+	defaultPreviewRange = time.Hour
+)
+
+type silencePreviewRequest struct {
+	Spec  v2beta2.SilenceSpec `json:"spec"`
+	Start *time.Time          `json:"start,omitempty"`
+	End   *time.Time          `json:"end,omitempty"`
+}
+
+type previewAlert struct {
+	Fingerprint string      `json:"fingerprint"`
+	Status      string      `json:"status"`
+	Labels      template.KV `json:"labels"`
+	FirstSeen   time.Time   `json:"firstSeen"`
+	LastSeen    time.Time   `json:"lastSeen"`
+	// The number of times the alert was routed to the receivers of the tenant.
+	Notifications int      `json:"notifications"`
+	Tenants       []string `json:"tenants"`
+}
+
+type silencePreviewResponse struct {
+	Start         time.Time      `json:"start"`
+	End           time.Time      `json:"end"`
+	Alerts        []previewAlert `json:"alerts"`
+	Notifications int            `json:"notifications"`
+	Tenants       []string       `json:"tenants"`
+}
+
+// PreviewSilence returns the recently routed alerts which would have been muted by the silence in the time range,
+// how many notifications they would have generated, and which tenants they belong to.
+// Only the alerts routed to the receivers of the tenant set in the tenant header are returned,
+// unless the tenant is an admin tenant, which previews the alerts routed to the receivers of all the tenants.
+func (h *HttpHandler) PreviewSilence(w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		_ = r.Body.Close()
+	}()
+
+	tenant := r.Header.Get(*tenantHeader)
+	if utils.StringIsNil(tenant) {
+		h.handle(w, &response{http.StatusUnauthorized, fmt.Sprintf("header %s is required", *tenantHeader)})
+		return
+	}
+
+	req := silencePreviewRequest{}
+	if err := utils.JsonDecode(r.Body, &req); err != nil {
+		h.handle(w, &response{http.StatusBadRequest, err.Error()})
+		return
+	}
+
+	if req.Spec.Matcher == nil {
+		h.handle(w, &response{http.StatusBadRequest, "spec.matcher must be specified"})
+		return
+	}
+
+	silence := &v2beta2.Silence{Spec: req.Spec}
+	if _, err := silence.ValidateCreate(); err != nil {
+		h.handle(w, &response{http.StatusBadRequest, err.Error()})
+		return
+	}
+
+	m, err := req.Spec.Matcher.Compile()
+	if err != nil {
+		h.handle(w, &response{http.StatusBadRequest, err.Error()})
+		return
+	}
+
+	end := time.Now()
+	if req.End != nil {
+		end = *req.End
+	}
+	start := end.Add(-defaultPreviewRange)
+	if req.Start != nil {
+		start = *req.Start
+	}
+	if !end.After(start) {
+		h.handle(w, &response{http.StatusBadRequest, "end must be after start"})
+		return
+	}
+
+	res := preview(h.recent.List(start, end), silence, m, tenant, isAdmin(tenant))
+	res.Start = start
+	res.End = end
+
+	bs, err := utils.JsonMarshalIndent(res, "", "  ")
+	if err != nil {
+		h.handle(w, &response{http.StatusInternalServerError, fmt.Sprintf("marshal preview failed, %s", err.Error())})
+		return
+	}
+	_, _ = w.Write(bs)
+}
+
+// preview returns the alerts of the tenant in the recent alerts which would have been muted by the silence,
+// the alerts of all the tenants are returned if all is true.
+// Every time an alert was routed to a receiver counts as a notification, so the number of notifications is an upper bound,
+// the notifications which would be deduplicated by the notification log or merged by the aggregation are also counted.
+func preview(recent []*dispatcher.RecentAlert, silence *v2beta2.Silence, m *v2beta2.Matcher, tenant string, all bool) silencePreviewResponse {
+	res := silencePreviewResponse{
+		Alerts:  make([]previewAlert, 0),
+		Tenants: make([]string, 0),
+	}
+	// The silence is not saved, so its label selectors are compiled rather than cached by the matcher cache.
+	var selector *v2beta2.Matcher
+	if rs := silence.Spec.Receivers; rs != nil && rs.Selector != nil {
+		if selector, _ = rs.Selector.Compile(); selector == nil {
+			return res
+		}
+	}
+
+	alerts := make(map[string]*previewAlert)
+	// The tenants of the alerts, the global receivers do not belong to any tenant.
+	tenants := make(map[string]map[string]struct{})
+	for _, ra := range recent {
+		if !silence.IsActiveAt(ra.SeenAt) {
+			continue
+		}
+
+		if !m.Matches(ra.Alert.Labels) {
+			continue
+		}
+
+		notifications := 0
+		seen := make(map[string]struct{})
+		for _, rcv := range ra.Receivers {
+			if !all && rcv.Tenant != tenant {
+				continue
+			}
+			if !silence.MatchReceiver(rcv.Name, rcv.Type, rcv.Labels, selector) {
+				continue
+			}
+			notifications++
+			if !utils.StringIsNil(rcv.Tenant) {
+				seen[rcv.Tenant] = struct{}{}
+			}
+		}
+		if notifications == 0 {
+			continue
+		}
+
+		fingerprint := ra.Alert.Fingerprint()
+		pa := alerts[fingerprint]
+		if pa == nil {
+			pa = &previewAlert{
+				Fingerprint: fingerprint,
+				Labels:      ra.Alert.Labels,
+				FirstSeen:   ra.SeenAt,
+			}
+			alerts[fingerprint] = pa
+			tenants[fingerprint] = make(map[string]struct{})
+		}
+		for t := range seen {
+			tenants[fingerprint][t] = struct{}{}
+		}
+		pa.Status = ra.Alert.Status
+		pa.LastSeen = ra.SeenAt
+		pa.Notifications += notifications
+		res.Notifications += notifications
+	}
+
+	set := make(map[string]struct{})
+	for fingerprint, pa := range alerts {
+		pa.Tenants = make([]string, 0)
+		for t := range tenants[fingerprint] {
+			pa.Tenants = append(pa.Tenants, t)
+			set[t] = struct{}{}
+		}
+		sort.Strings(pa.Tenants)
+		res.Alerts = append(res.Alerts, *pa)
+	}
+	sort.Slice(res.Alerts, func(i, j int) bool {
+		return res.Alerts[i].FirstSeen.Before(res.Alerts[j].FirstSeen)
+	})
+
+	for t := range set {
+		res.Tenants = append(res.Tenants, t)
+	}
+	sort.Strings(res.Tenants)
+
+	return res
+}
@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/dispatcher"
+	"github.com/kubesphere/notification-manager/pkg/template"
+)
+
+func TestPreview(t *testing.T) {
+	now := time.Now()
+	alert := func(name string) *template.Alert {
+		return &template.Alert{Status: "firing", Labels: template.KV{"alertname": name, "namespace": "test"}}
+	}
+	recent := []*dispatcher.RecentAlert{
+		{
+			Alert:  alert("a"),
+			SeenAt: now.Add(-2 * time.Minute),
+			Receivers: []dispatcher.RecentReceiver{
+				{Tenant: "admin", Type: "slack", Name: "slack"},
+				{Tenant: "admin", Type: "email", Name: "email"},
+				{Tenant: "user", Type: "slack", Name: "slack"},
+			},
+		},
+		{
+			Alert:  alert("a"),
+			SeenAt: now.Add(-time.Minute),
+			Receivers: []dispatcher.RecentReceiver{
+				{Tenant: "admin", Type: "slack", Name: "slack"},
+				// The global receiver does not belong to any tenant.
+				{Type: "webhook", Name: "global"},
+			},
+		},
+		{
+			Alert:     alert("b"),
+			SeenAt:    now,
+			Receivers: []dispatcher.RecentReceiver{{Tenant: "user", Type: "slack", Name: "slack"}},
+		},
+		{
+			Alert:     &template.Alert{Status: "firing", Labels: template.KV{"alertname": "c", "namespace": "other"}},
+			SeenAt:    now,
+			Receivers: []dispatcher.RecentReceiver{{Tenant: "admin", Type: "slack", Name: "slack"}},
+		},
+	}
+
+	silence := &v2beta2.Silence{
+		Spec: v2beta2.SilenceSpec{
+			Matcher: &v2beta2.LabelSelector{MatchLabels: map[string]string{"namespace": "test"}},
+		},
+	}
+	m, err := silence.Spec.Matcher.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := preview(recent, silence, m, "admin", false)
+	if len(res.Alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(res.Alerts))
+	}
+	pa := res.Alerts[0]
+	if pa.Labels["alertname"] != "a" || pa.Notifications != 3 || !pa.FirstSeen.Equal(recent[0].SeenAt) || !pa.LastSeen.Equal(recent[1].SeenAt) {
+		t.Fatalf("unexpected alert %+v", pa)
+	}
+	if res.Notifications != 3 || len(res.Tenants) != 1 || res.Tenants[0] != "admin" {
+		t.Fatalf("unexpected response, notifications %d, tenants %v", res.Notifications, res.Tenants)
+	}
+
+	silence.Spec.Receivers = &v2beta2.SilenceReceiverSelector{Type: []string{"email"}}
+	if res := preview(recent, silence, m, "admin", false); res.Notifications != 1 {
+		t.Fatalf("expected 1 notification sent to the email receiver, got %d", res.Notifications)
+	}
+
+	if res := preview(recent, silence, m, "guest", false); len(res.Alerts) != 0 || len(res.Tenants) != 0 {
+		t.Fatalf("expected no alerts of the tenant guest, got %+v", res)
+	}
+
+	// The admin tenant previews the alerts routed to the receivers of all the tenants and the global receivers.
+	silence.Spec.Receivers = nil
+	res = preview(recent, silence, m, "guest", true)
+	if len(res.Alerts) != 2 || res.Notifications != 6 {
+		t.Fatalf("expected 2 alerts and 6 notifications, got %d alerts and %d notifications", len(res.Alerts), res.Notifications)
+	}
+	if len(res.Tenants) != 2 || res.Tenants[0] != "admin" || res.Tenants[1] != "user" {
+		t.Fatalf("expected the tenants admin and user, got %v", res.Tenants)
+	}
+	if pa := res.Alerts[0]; pa.Notifications != 5 || len(pa.Tenants) != 2 {
+		t.Fatalf("unexpected alert %+v", pa)
+	}
+	if pa := res.Alerts[1]; pa.Labels["alertname"] != "b" || len(pa.Tenants) != 1 || pa.Tenants[0] != "user" {
+		t.Fatalf("unexpected alert %+v", pa)
+	}
+}
+
+func TestPreviewAdminScope(t *testing.T) {
+	*adminTenants = []string{"admin"}
+	defer func() {
+		*adminTenants = nil
+	}()
+
+	recent := []*dispatcher.RecentAlert{{
+		Alert:     &template.Alert{Status: "firing", Labels: template.KV{"alertname": "a"}},
+		SeenAt:    time.Now(),
+		Receivers: []dispatcher.RecentReceiver{{Tenant: "user", Type: "slack", Name: "slack"}},
+	}}
+	silence := &v2beta2.Silence{Spec: v2beta2.SilenceSpec{Matcher: &v2beta2.LabelSelector{}}}
+	m, err := silence.Spec.Matcher.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		tenant string
+		alerts int
+	}{
+		// The admin tenant previews the alerts of all the tenants.
+		{"admin", 1},
+		// The normal tenant only previews the alerts routed to its own receivers.
+		{"other", 0},
+		{"user", 1},
+	}
+	for _, test := range tests {
+		res := preview(recent, silence, m, test.tenant, isAdmin(test.tenant))
+		if len(res.Alerts) != test.alerts {
+			t.Fatalf("%s: expected %d alerts, got %d", test.tenant, test.alerts, len(res.Alerts))
+		}
+		if test.alerts > 0 && (len(res.Tenants) != 1 || res.Tenants[0] != "user") {
+			t.Fatalf("%s: expected the tenant user, got %v", test.tenant, res.Tenants)
+		}
+	}
+}
+
+func TestPreviewSilenceUnauthorized(t *testing.T) {
+	*tenantHeader = "X-Remote-User"
+	h := &HttpHandler{logger: log.NewNopLogger()}
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v2/silences/preview", strings.NewReader(`{"spec": {"matcher": {}}}`))
+	w := httptest.NewRecorder()
+	h.PreviewSilence(w, r)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
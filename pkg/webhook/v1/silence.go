@@ -0,0 +1,482 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/utils"
+	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	silenceStateActive  = "active"
+	silenceStatePending = "pending"
+	silenceStateExpired = "expired"
+)
+
+var (
+	tenantHeader *string
+	adminTenants *[]string
+)
+
+func init() {
+	tenantHeader = kingpin.Flag(
+		"silence.api.tenantHeader",
+		"The request header which carries the authenticated tenant of the silences and acknowledgements APIs, it should be set by an authenticating proxy",
+	).Default("X-Remote-User").String()
+	adminTenants = kingpin.Flag(
+		"silence.api.adminTenant",
+		"The tenant which can access the notifications of all the tenants through the APIs, such as previewing the global silences and managing all the dead letters, can be repeated",
+	).Strings()
+}
+
+// isAdmin returns true if the authenticated tenant can access the notifications of all the tenants.
+func isAdmin(tenant string) bool {
+	return !utils.StringIsNil(tenant) && utils.StringInList(tenant, *adminTenants)
+}
+
+// silenceMatcher is the matcher of the Alertmanager silences API.
+type silenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	// Defaults to true.
+	IsEqual *bool `json:"isEqual,omitempty"`
+}
+
+// postableSilence is the silence posted to the Alertmanager silences API.
+type postableSilence struct {
+	ID        string           `json:"id,omitempty"`
+	Matchers  []silenceMatcher `json:"matchers"`
+	StartsAt  time.Time        `json:"startsAt"`
+	EndsAt    time.Time        `json:"endsAt"`
+	CreatedBy string           `json:"createdBy"`
+	Comment   string           `json:"comment"`
+}
+
+type silenceStatus struct {
+	State string `json:"state"`
+}
+
+// gettableSilence is the silence returned by the Alertmanager silences API.
+type gettableSilence struct {
+	ID        string           `json:"id"`
+	Status    silenceStatus    `json:"status"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+	Matchers  []silenceMatcher `json:"matchers"`
+	StartsAt  *time.Time       `json:"startsAt,omitempty"`
+	EndsAt    *time.Time       `json:"endsAt,omitempty"`
+	CreatedBy string           `json:"createdBy"`
+	Comment   string           `json:"comment"`
+}
+
+// ListSilences lists the silences of the tenant in the format of the Alertmanager silences API.
+func (h *HttpHandler) ListSilences(w http.ResponseWriter, r *http.Request) {
+
+	tenant := r.Header.Get(*tenantHeader)
+	if utils.StringIsNil(tenant) {
+		h.handle(w, &response{http.StatusUnauthorized, fmt.Sprintf("header %s is required", *tenantHeader)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.wkrTimeout)
+	defer cancel()
+
+	silences, err := h.notifierCtl.GetTenantSilences(ctx, tenant)
+	if err != nil {
+		h.handle(w, &response{http.StatusInternalServerError, err.Error()})
+		return
+	}
+
+	res := make([]gettableSilence, 0)
+	for i := range silences {
+		res = append(res, toGettableSilence(&silences[i]))
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+
+	bs, _ := utils.JsonMarshalIndent(res, "", "  ")
+	_, _ = w.Write(bs)
+}
+
+// GetSilence returns the silence with the id in the format of the Alertmanager silences API.
+func (h *HttpHandler) GetSilence(w http.ResponseWriter, r *http.Request) {
+
+	tenant := r.Header.Get(*tenantHeader)
+	if utils.StringIsNil(tenant) {
+		h.handle(w, &response{http.StatusUnauthorized, fmt.Sprintf("header %s is required", *tenantHeader)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.wkrTimeout)
+	defer cancel()
+
+	id := chi.URLParam(r, "id")
+	silence, err := h.notifierCtl.GetTenantSilence(ctx, tenant, id)
+	if err != nil {
+		h.handle(w, &response{http.StatusInternalServerError, err.Error()})
+		return
+	}
+	if silence == nil {
+		h.handle(w, &response{http.StatusNotFound, fmt.Sprintf("silence %s not found", id)})
+		return
+	}
+
+	bs, _ := utils.JsonMarshalIndent(toGettableSilence(silence), "", "  ")
+	_, _ = w.Write(bs)
+}
+
+// PostSilence creates a silence, or updates the silence if the id is specified.
+func (h *HttpHandler) PostSilence(w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		_ = r.Body.Close()
+	}()
+
+	tenant := r.Header.Get(*tenantHeader)
+	if utils.StringIsNil(tenant) {
+		h.handle(w, &response{http.StatusUnauthorized, fmt.Sprintf("header %s is required", *tenantHeader)})
+		return
+	}
+
+	ps := postableSilence{}
+	if err := utils.JsonDecode(r.Body, &ps); err != nil {
+		h.handle(w, &response{http.StatusBadRequest, err.Error()})
+		return
+	}
+
+	// The creator is the authenticated tenant, which will be notified when the silence expires.
+	if utils.StringIsNil(ps.CreatedBy) {
+		ps.CreatedBy = tenant
+	}
+	if ps.CreatedBy != tenant {
+		h.handle(w, &response{http.StatusForbidden, fmt.Sprintf("createdBy must be the tenant %s", tenant)})
+		return
+	}
+
+	matcher, err := validatePostableSilence(&ps)
+	if err != nil {
+		h.handle(w, &response{http.StatusBadRequest, err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.wkrTimeout)
+	defer cancel()
+
+	silence := &v2beta2.Silence{}
+	if utils.StringIsNil(ps.ID) {
+		set, err := h.notifierCtl.TenantSilenceLabels(tenant)
+		if err != nil {
+			h.handle(w, &response{http.StatusServiceUnavailable, err.Error()})
+			return
+		}
+		silence.GenerateName = "silence-"
+		silence.Labels = set
+		silence.Spec.CreatedBy = ps.CreatedBy
+	} else {
+		old, err := h.notifierCtl.GetTenantSilence(ctx, tenant, ps.ID)
+		if err != nil {
+			h.handle(w, &response{http.StatusInternalServerError, err.Error()})
+			return
+		}
+		if old == nil {
+			h.handle(w, &response{http.StatusNotFound, fmt.Sprintf("silence %s not found", ps.ID)})
+			return
+		}
+		silence = old.DeepCopy()
+	}
+
+	updateSilenceSpec(&silence.Spec, matcher, &ps)
+
+	if utils.StringIsNil(ps.ID) {
+		err = h.notifierCtl.CreateSilence(ctx, silence)
+	} else {
+		err = h.notifierCtl.UpdateSilence(ctx, silence)
+	}
+	if err != nil {
+		h.handle(w, &response{statusFromError(err), fmt.Sprintf("save silence failed, %s", err.Error())})
+		return
+	}
+
+	bs, _ := utils.JsonMarshalIndent(map[string]string{"silenceID": silence.Name}, "", "  ")
+	_, _ = w.Write(bs)
+}
+
+// updateSilenceSpec updates the fields of the silence which are supported by the Alertmanager silences API,
+// the other fields such as the receivers, the schedule and the creator are kept.
+func updateSilenceSpec(spec *v2beta2.SilenceSpec, matcher *v2beta2.LabelSelector, ps *postableSilence) {
+	spec.Matcher = matcher
+	spec.StartsAt = &metav1.Time{Time: ps.StartsAt}
+	spec.EndsAt = &metav1.Time{Time: ps.EndsAt}
+	spec.Comment = ps.Comment
+}
+
+// ExpireSilence expires the silence with the id. The silence which has started ends now,
+// and the silence which has not started is disabled.
+func (h *HttpHandler) ExpireSilence(w http.ResponseWriter, r *http.Request) {
+
+	tenant := r.Header.Get(*tenantHeader)
+	if utils.StringIsNil(tenant) {
+		h.handle(w, &response{http.StatusUnauthorized, fmt.Sprintf("header %s is required", *tenantHeader)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.wkrTimeout)
+	defer cancel()
+
+	id := chi.URLParam(r, "id")
+	old, err := h.notifierCtl.GetTenantSilence(ctx, tenant, id)
+	if err != nil {
+		h.handle(w, &response{http.StatusInternalServerError, err.Error()})
+		return
+	}
+	if old == nil {
+		h.handle(w, &response{http.StatusNotFound, fmt.Sprintf("silence %s not found", id)})
+		return
+	}
+
+	if silenceState(old) == silenceStateExpired {
+		h.handle(w, &response{http.StatusOK, fmt.Sprintf("silence %s has expired", id)})
+		return
+	}
+
+	silence := old.DeepCopy()
+	now := time.Now()
+	if silence.Spec.StartsAt == nil || !silence.Spec.StartsAt.After(now) {
+		silence.Spec.EndsAt = &metav1.Time{Time: now}
+	} else {
+		enabled := false
+		silence.Spec.Enabled = &enabled
+	}
+
+	if err := h.notifierCtl.UpdateSilence(ctx, silence); err != nil {
+		h.handle(w, &response{statusFromError(err), fmt.Sprintf("expire silence %s failed, %s", id, err.Error())})
+		return
+	}
+
+	h.handle(w, &response{http.StatusOK, fmt.Sprintf("expire silence %s successfully", id)})
+}
+
+// validatePostableSilence validates the silence and translates its matchers into a label selector.
+func validatePostableSilence(ps *postableSilence) (*v2beta2.LabelSelector, error) {
+
+	if len(ps.Matchers) == 0 {
+		return nil, fmt.Errorf("at least one matcher is required")
+	}
+	if utils.StringIsNil(ps.CreatedBy) {
+		return nil, fmt.Errorf("createdBy is required")
+	}
+	if utils.StringIsNil(ps.Comment) {
+		return nil, fmt.Errorf("comment is required")
+	}
+	if ps.StartsAt.IsZero() {
+		ps.StartsAt = time.Now()
+	}
+	if !ps.EndsAt.After(ps.StartsAt) {
+		return nil, fmt.Errorf("endsAt must be after startsAt")
+	}
+	if !ps.EndsAt.After(time.Now()) {
+		return nil, fmt.Errorf("endsAt must be in the future")
+	}
+
+	selector := &v2beta2.LabelSelector{}
+	for _, m := range ps.Matchers {
+		if utils.StringIsNil(m.Name) {
+			return nil, fmt.Errorf("the name of matcher is required")
+		}
+
+		isEqual := m.IsEqual == nil || *m.IsEqual
+		if m.IsRegex {
+			if _, err := regexp.Compile(m.Value); err != nil {
+				return nil, fmt.Errorf("invalid regex %s, %s", m.Value, err.Error())
+			}
+
+			op := v2beta2.LabelSelectorOpMatch
+			if !isEqual {
+				op = v2beta2.LabelSelectorOpNotMatch
+			}
+			selector.MatchExpressions = append(selector.MatchExpressions, v2beta2.LabelSelectorRequirement{
+				Key:        m.Name,
+				Operator:   op,
+				RegexValue: anchorRegex(m.Value),
+			})
+			continue
+		}
+
+		// Like Alertmanager, an empty value matches the alerts without the label.
+		if utils.StringIsNil(m.Value) && len(validation.IsQualifiedName(m.Name)) == 0 {
+			op := metav1.LabelSelectorOpDoesNotExist
+			if !isEqual {
+				op = metav1.LabelSelectorOpExists
+			}
+			selector.MatchExpressions = append(selector.MatchExpressions, v2beta2.LabelSelectorRequirement{
+				Key:      m.Name,
+				Operator: v2beta2.LabelSelectorOperator(op),
+			})
+			continue
+		}
+
+		// The values which are not valid label values can only be matched by regular expressions.
+		if len(validation.IsValidLabelValue(m.Value)) > 0 || len(validation.IsQualifiedName(m.Name)) > 0 {
+			op := v2beta2.LabelSelectorOpMatch
+			if !isEqual {
+				op = v2beta2.LabelSelectorOpNotMatch
+			}
+			selector.MatchExpressions = append(selector.MatchExpressions, v2beta2.LabelSelectorRequirement{
+				Key:        m.Name,
+				Operator:   op,
+				RegexValue: anchorRegex(regexp.QuoteMeta(m.Value)),
+			})
+			continue
+		}
+
+		if isEqual {
+			if selector.MatchLabels == nil {
+				selector.MatchLabels = make(map[string]string)
+			}
+			selector.MatchLabels[m.Name] = m.Value
+		} else {
+			selector.MatchExpressions = append(selector.MatchExpressions, v2beta2.LabelSelectorRequirement{
+				Key:      m.Name,
+				Operator: v2beta2.LabelSelectorOperator(metav1.LabelSelectorOpNotIn),
+				Values:   []string{m.Value},
+			})
+		}
+	}
+
+	if err := selector.Validate(); err != nil {
+		return nil, err
+	}
+
+	return selector, nil
+}
+
+// toGettableSilence translates the silence into the format of the Alertmanager silences API.
+func toGettableSilence(silence *v2beta2.Silence) gettableSilence {
+
+	gs := gettableSilence{
+		ID:        silence.Name,
+		Status:    silenceStatus{State: silenceState(silence)},
+		UpdatedAt: silence.CreationTimestamp.Time,
+		Matchers:  make([]silenceMatcher, 0),
+		EndsAt:    silence.ExpiresAt(),
+		CreatedBy: silence.Spec.CreatedBy,
+		Comment:   silence.Spec.Comment,
+	}
+
+	for _, f := range silence.ManagedFields {
+		if f.Time != nil && f.Time.After(gs.UpdatedAt) {
+			gs.UpdatedAt = f.Time.Time
+		}
+	}
+
+	if silence.Spec.StartsAt != nil {
+		startsAt := silence.Spec.StartsAt.Time
+		gs.StartsAt = &startsAt
+	}
+
+	if silence.Spec.Matcher == nil {
+		return gs
+	}
+
+	var keys []string
+	for k := range silence.Spec.Matcher.MatchLabels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		gs.Matchers = append(gs.Matchers, newSilenceMatcher(k, silence.Spec.Matcher.MatchLabels[k], false, true))
+	}
+
+	for _, req := range silence.Spec.Matcher.MatchExpressions {
+		switch req.Operator {
+		case v2beta2.LabelSelectorOpMatch:
+			gs.Matchers = append(gs.Matchers, newSilenceMatcher(req.Key, unanchorRegex(req.RegexValue), true, true))
+		case v2beta2.LabelSelectorOpNotMatch:
+			gs.Matchers = append(gs.Matchers, newSilenceMatcher(req.Key, unanchorRegex(req.RegexValue), true, false))
+		case v2beta2.LabelSelectorOperator(metav1.LabelSelectorOpIn), v2beta2.LabelSelectorOperator(metav1.LabelSelectorOpNotIn):
+			isEqual := req.Operator == v2beta2.LabelSelectorOperator(metav1.LabelSelectorOpIn)
+			if len(req.Values) == 1 {
+				gs.Matchers = append(gs.Matchers, newSilenceMatcher(req.Key, req.Values[0], false, isEqual))
+			} else {
+				var values []string
+				for _, v := range req.Values {
+					values = append(values, regexp.QuoteMeta(v))
+				}
+				gs.Matchers = append(gs.Matchers, newSilenceMatcher(req.Key, strings.Join(values, "|"), true, isEqual))
+			}
+		case v2beta2.LabelSelectorOperator(metav1.LabelSelectorOpExists):
+			gs.Matchers = append(gs.Matchers, newSilenceMatcher(req.Key, ".+", true, true))
+		case v2beta2.LabelSelectorOperator(metav1.LabelSelectorOpDoesNotExist):
+			gs.Matchers = append(gs.Matchers, newSilenceMatcher(req.Key, "", false, true))
+		}
+	}
+
+	return gs
+}
+
+func silenceState(silence *v2beta2.Silence) string {
+
+	if silence.IsActive() {
+		return silenceStateActive
+	}
+
+	if silence.Spec.Enabled != nil && !*silence.Spec.Enabled {
+		return silenceStateExpired
+	}
+
+	if silence.Spec.EndsAt != nil && !silence.Spec.EndsAt.After(time.Now()) {
+		return silenceStateExpired
+	}
+
+	// The silence scheduled periodically will be active next time.
+	if !utils.StringIsNil(silence.Spec.Schedule) || len(silence.Spec.TimeWindows) > 0 {
+		return silenceStatePending
+	}
+
+	if silence.Spec.StartsAt != nil && silence.Spec.StartsAt.After(time.Now()) {
+		return silenceStatePending
+	}
+
+	return silenceStateExpired
+}
+
+func newSilenceMatcher(name, value string, isRegex, isEqual bool) silenceMatcher {
+	return silenceMatcher{
+		Name:    name,
+		Value:   value,
+		IsRegex: isRegex,
+		IsEqual: &isEqual,
+	}
+}
+
+// anchorRegex anchors the regular expression so that it matches the whole label value like Alertmanager.
+func anchorRegex(s string) string {
+	return fmt.Sprintf("^(?:%s)$", s)
+}
+
+func unanchorRegex(s string) string {
+	if strings.HasPrefix(s, "^(?:") && strings.HasSuffix(s, ")$") {
+		return strings.TrimSuffix(strings.TrimPrefix(s, "^(?:"), ")$")
+	}
+
+	return s
+}
+
+func statusFromError(err error) int {
+	if status, ok := err.(errors.APIStatus); ok {
+		return int(status.Status().Code)
+	}
+
+	return http.StatusInternalServerError
+}
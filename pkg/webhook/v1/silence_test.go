@@ -0,0 +1,179 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/kubesphere/notification-manager/apis/v2beta2"
+	"github.com/kubesphere/notification-manager/pkg/template"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidatePostableSilence(t *testing.T) {
+	notEqual := false
+
+	tests := []struct {
+		name     string
+		matcher  silenceMatcher
+		matches  []template.KV
+		excludes []template.KV
+	}{
+		{
+			"equal",
+			silenceMatcher{Name: "alertname", Value: "KubePodCrashLooping"},
+			[]template.KV{{"alertname": "KubePodCrashLooping"}},
+			[]template.KV{{"alertname": "Watchdog"}, {}},
+		},
+		{
+			"not equal",
+			silenceMatcher{Name: "alertname", Value: "Watchdog", IsEqual: &notEqual},
+			[]template.KV{{"alertname": "KubePodCrashLooping"}, {}},
+			[]template.KV{{"alertname": "Watchdog"}},
+		},
+		{
+			"regex is anchored",
+			silenceMatcher{Name: "namespace", Value: "kube-.*", IsRegex: true},
+			[]template.KV{{"namespace": "kube-system"}},
+			[]template.KV{{"namespace": "my-kube-system"}},
+		},
+		{
+			"invalid label value",
+			silenceMatcher{Name: "summary", Value: "disk is full (90%)"},
+			[]template.KV{{"summary": "disk is full (90%)"}},
+			[]template.KV{{"summary": "disk is full (90)"}},
+		},
+		{
+			// Like Alertmanager, an empty value matches the alerts without the label.
+			"empty value",
+			silenceMatcher{Name: "severity", Value: ""},
+			[]template.KV{{"alertname": "Watchdog"}},
+			[]template.KV{{"severity": "critical"}},
+		},
+		{
+			"not equal to empty value",
+			silenceMatcher{Name: "severity", Value: "", IsEqual: &notEqual},
+			[]template.KV{{"severity": "critical"}},
+			[]template.KV{{"alertname": "Watchdog"}},
+		},
+	}
+
+	for _, test := range tests {
+		ps := &postableSilence{
+			Matchers:  []silenceMatcher{test.matcher},
+			EndsAt:    time.Now().Add(time.Hour),
+			CreatedBy: "admin",
+			Comment:   "test",
+		}
+		selector, err := validatePostableSilence(ps)
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+
+		m, err := selector.Compile()
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		for _, labels := range test.matches {
+			if !m.Matches(labels) {
+				t.Fatalf("%s: expected %v to be matched", test.name, labels)
+			}
+		}
+		for _, labels := range test.excludes {
+			if m.Matches(labels) {
+				t.Fatalf("%s: expected %v not to be matched", test.name, labels)
+			}
+		}
+
+		// The matcher is translated back into the same Alertmanager matcher.
+		gs := toGettableSilence(&v2beta2.Silence{Spec: v2beta2.SilenceSpec{Matcher: selector}})
+		if len(gs.Matchers) != 1 || gs.Matchers[0].Name != test.matcher.Name {
+			t.Fatalf("%s: unexpected matchers %v", test.name, gs.Matchers)
+		}
+	}
+}
+
+func TestValidatePostableSilenceInvalid(t *testing.T) {
+	valid := func() *postableSilence {
+		return &postableSilence{
+			Matchers:  []silenceMatcher{{Name: "alertname", Value: "Watchdog"}},
+			EndsAt:    time.Now().Add(time.Hour),
+			CreatedBy: "admin",
+			Comment:   "test",
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(ps *postableSilence)
+	}{
+		{"no matchers", func(ps *postableSilence) { ps.Matchers = nil }},
+		{"no createdBy", func(ps *postableSilence) { ps.CreatedBy = "" }},
+		{"no comment", func(ps *postableSilence) { ps.Comment = "" }},
+		{"endsAt before startsAt", func(ps *postableSilence) { ps.StartsAt = ps.EndsAt.Add(time.Minute) }},
+		{"endsAt in the past", func(ps *postableSilence) {
+			ps.StartsAt = time.Now().Add(-2 * time.Hour)
+			ps.EndsAt = time.Now().Add(-time.Hour)
+		}},
+		{"no matcher name", func(ps *postableSilence) { ps.Matchers[0].Name = "" }},
+		{"invalid regex", func(ps *postableSilence) {
+			ps.Matchers[0] = silenceMatcher{Name: "alertname", Value: "(", IsRegex: true}
+		}},
+	}
+
+	for _, test := range tests {
+		ps := valid()
+		test.modify(ps)
+		if _, err := validatePostableSilence(ps); err == nil {
+			t.Fatalf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestPostSilenceCreatedBy(t *testing.T) {
+	*tenantHeader = "X-Remote-User"
+	h := &HttpHandler{logger: log.NewNopLogger()}
+
+	// The silence can not be created on behalf of another tenant.
+	body := `{"matchers":[{"name":"alertname","value":"Watchdog"}],"endsAt":"2099-01-01T00:00:00Z","createdBy":"admin","comment":"test"}`
+	r := httptest.NewRequest(http.MethodPost, "/api/v2/silences", strings.NewReader(body))
+	r.Header.Set(*tenantHeader, "user")
+	w := httptest.NewRecorder()
+	h.PostSilence(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+}
+
+func TestUpdateSilenceSpec(t *testing.T) {
+	enabled := false
+	spec := v2beta2.SilenceSpec{
+		Enabled:   &enabled,
+		Matcher:   &v2beta2.LabelSelector{MatchLabels: map[string]string{"alertname": "a"}},
+		Receivers: &v2beta2.SilenceReceiverSelector{Type: []string{"email"}},
+		Schedule:  "0 22 * * *",
+		CreatedBy: "user",
+		Comment:   "old",
+	}
+
+	ps := &postableSilence{
+		StartsAt:  time.Now(),
+		EndsAt:    time.Now().Add(time.Hour),
+		CreatedBy: "user",
+		Comment:   "new",
+	}
+	matcher := &v2beta2.LabelSelector{MatchLabels: map[string]string{"alertname": "b"}}
+	updateSilenceSpec(&spec, matcher, ps)
+
+	if spec.Matcher != matcher || spec.Comment != "new" ||
+		!spec.StartsAt.Equal(&metav1.Time{Time: ps.StartsAt}) || !spec.EndsAt.Equal(&metav1.Time{Time: ps.EndsAt}) {
+		t.Fatalf("expected the fields of the Alertmanager silences API to be updated, got %+v", spec)
+	}
+	// The fields which are not supported by the Alertmanager silences API are kept.
+	if spec.Enabled == nil || *spec.Enabled || spec.Receivers == nil || spec.Schedule != "0 22 * * *" || spec.CreatedBy != "user" {
+		t.Fatalf("expected the other fields to be kept, got %+v", spec)
+	}
+}
@@ -9,7 +9,10 @@ import (
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
+	"github.com/kubesphere/notification-manager/pkg/ack"
 	"github.com/kubesphere/notification-manager/pkg/controller"
+	"github.com/kubesphere/notification-manager/pkg/deadletter"
+	"github.com/kubesphere/notification-manager/pkg/dispatcher"
 	"github.com/kubesphere/notification-manager/pkg/store"
 	v1 "github.com/kubesphere/notification-manager/pkg/webhook/v1"
 )
@@ -27,14 +30,14 @@ type Webhook struct {
 	handler *v1.HttpHandler
 }
 
-func New(logger log.Logger, notifierCtl *controller.Controller, alerts *store.AlertStore, o *Options) *Webhook {
+func New(logger log.Logger, notifierCtl *controller.Controller, alerts *store.AlertStore, deadLetters *deadletter.Queue, acks *ack.Store, recent *dispatcher.RecentAlerts, o *Options) *Webhook {
 
 	h := &Webhook{
 		Options: o,
 		logger:  logger,
 	}
 
-	h.handler = v1.New(logger, h.WorkerTimeout, notifierCtl, alerts)
+	h.handler = v1.New(logger, h.WorkerTimeout, notifierCtl, alerts, deadLetters, acks, recent)
 	h.router = chi.NewRouter()
 
 	h.router.Use(middleware.RequestID)
@@ -47,6 +50,18 @@ func New(logger log.Logger, notifierCtl *controller.Controller, alerts *store.Al
 	h.router.Post("/api/v2/alerts", h.handler.Alert)
 	h.router.Post("/api/v2/verify", h.handler.Verify)
 	h.router.Post("/api/v2/notifications", h.handler.Notification)
+	h.router.Post("/api/v2/alerts/{fingerprint}/ack", h.handler.AckAlert)
+	h.router.Post("/api/v2/alerts/{fingerprint}/unack", h.handler.UnackAlert)
+	h.router.Get("/api/v2/oncall/{schedule}", h.handler.ListOnCall)
+	h.router.Get("/api/v2/silences", h.handler.ListSilences)
+	h.router.Post("/api/v2/silences", h.handler.PostSilence)
+	h.router.Post("/api/v2/silences/preview", h.handler.PreviewSilence)
+	h.router.Get("/api/v2/silence/{id}", h.handler.GetSilence)
+	h.router.Delete("/api/v2/silence/{id}", h.handler.ExpireSilence)
+	h.router.Get("/api/v2/deadletters", h.handler.ListDeadLetters)
+	h.router.Delete("/api/v2/deadletters", h.handler.PurgeDeadLetters)
+	h.router.Post("/api/v2/deadletters/{id}/replay", h.handler.ReplayDeadLetter)
+	h.router.Delete("/api/v2/deadletters/{id}", h.handler.DeleteDeadLetter)
 	h.router.Get("/metrics", h.handler.ServeMetrics)
 	h.router.Get("/-/reload", h.handler.ServeReload)
 	h.router.Get("/-/ready", h.handler.ServeHealthCheck)
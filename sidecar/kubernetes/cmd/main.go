@@ -31,6 +31,19 @@ import (
 
 var waitHandlerGroup sync.WaitGroup
 
+// namespace is an item of the request of the batch tenant lookup.
+type namespace struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace"`
+}
+
+// tenantsOfNamespace is an item of the response of the batch tenant lookup.
+type tenantsOfNamespace struct {
+	Cluster   string   `json:"cluster,omitempty"`
+	Namespace string   `json:"namespace"`
+	Tenants   []string `json:"tenants"`
+}
+
 func main() {
 
 	cmd := NewServerCommand()
@@ -69,6 +82,7 @@ func httpserver() error {
 		Consumes(restful.MIME_JSON).
 		Produces(restful.MIME_JSON)
 	ws.Route(ws.GET("/tenant").To(handler))
+	ws.Route(ws.POST("/tenants").To(batchHandler))
 	ws.Route(ws.GET("/readiness").To(readiness))
 	ws.Route(ws.GET("/liveness").To(readiness))
 	ws.Route(ws.GET("/preStop").To(preStop))
@@ -104,6 +118,32 @@ func handler(req *restful.Request, resp *restful.Response) {
 	responseWithJson(resp, tenants)
 }
 
+// batchHandler returns the tenants of many namespaces at once, the namespace without tenants has empty tenants.
+func batchHandler(req *restful.Request, resp *restful.Response) {
+
+	waitHandlerGroup.Add(1)
+	defer waitHandlerGroup.Done()
+
+	var nss []namespace
+	if err := req.ReadEntity(&nss); err != nil {
+		responseWithHeaderAndEntity(resp, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res := make([]tenantsOfNamespace, 0, len(nss))
+	for _, ns := range nss {
+		res = append(res, tenantsOfNamespace{
+			Cluster:   ns.Cluster,
+			Namespace: ns.Namespace,
+			Tenants:   []string{ns.Namespace},
+		})
+	}
+
+	fmt.Printf("get tenants of %d namespaces", len(nss))
+
+	responseWithJson(resp, res)
+}
+
 // readiness
 func readiness(_ *restful.Request, resp *restful.Response) {
 
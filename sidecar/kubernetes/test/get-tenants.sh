@@ -1,2 +1,3 @@
 #!/bin/bash
 curl -XGET http://127.0.0.1:19094/api/v2/tenant?namespace=test
+curl -XPOST http://127.0.0.1:19094/api/v2/tenants -H 'Content-Type: application/json' -d '[{"namespace":"test"},{"namespace":"default"}]'
@@ -34,6 +34,19 @@ const (
 	defaultBatchSize = 500
 )
 
+// namespace is an item of the request of the batch tenant lookup.
+type namespace struct {
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace"`
+}
+
+// tenantsOfNamespace is an item of the response of the batch tenant lookup.
+type tenantsOfNamespace struct {
+	Cluster   string   `json:"cluster,omitempty"`
+	Namespace string   `json:"namespace"`
+	Tenants   []string `json:"tenants"`
+}
+
 var (
 	waitHandlerGroup sync.WaitGroup
 	host             string
@@ -97,6 +110,7 @@ func httpserver() error {
 		Consumes(restful.MIME_JSON).
 		Produces(restful.MIME_JSON)
 	ws.Route(ws.GET("/api/v2/tenant").To(handler))
+	ws.Route(ws.POST("/api/v2/tenants").To(batchHandler))
 	ws.Route(ws.GET("/readiness").To(readiness))
 	ws.Route(ws.GET("/liveness").To(readiness))
 	ws.Route(ws.GET("/preStop").To(preStop))
@@ -131,6 +145,35 @@ func handler(req *restful.Request, resp *restful.Response) {
 	responseWithJson(resp, tenants)
 }
 
+// batchHandler returns the tenants of many namespaces at once, the namespace without tenants has empty tenants.
+func batchHandler(req *restful.Request, resp *restful.Response) {
+
+	waitHandlerGroup.Add(1)
+	defer waitHandlerGroup.Done()
+
+	var nss []namespace
+	if err := req.ReadEntity(&nss); err != nil {
+		responseWithHeaderAndEntity(resp, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res := make([]tenantsOfNamespace, 0, len(nss))
+	for _, ns := range nss {
+		tenants := b.FromNamespace(ns.Cluster, ns.Namespace)
+		if tenants == nil {
+			tenants = []string{}
+		}
+
+		res = append(res, tenantsOfNamespace{
+			Cluster:   ns.Cluster,
+			Namespace: ns.Namespace,
+			Tenants:   tenants,
+		})
+	}
+
+	responseWithJson(resp, res)
+}
+
 // readiness
 func readiness(_ *restful.Request, resp *restful.Response) {
 
@@ -1,2 +1,3 @@
 #!/bin/bash
 curl -XGET http://127.0.0.1:19094/api/v2/tenant?cluster=host&namespace=kubesphere-monitoring-system
+curl -XPOST http://127.0.0.1:19094/api/v2/tenants -H 'Content-Type: application/json' -d '[{"cluster":"host","namespace":"kubesphere-monitoring-system"},{"cluster":"host","namespace":"default"}]'